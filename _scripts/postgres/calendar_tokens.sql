@@ -0,0 +1,26 @@
+-- 以前のスクリプトで作成したアカウントごとのトークンは、アカウントのタスクに限定できないため削除する
+-- (トークンは再発行する)
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'calendar_tokens' AND column_name = 'account_id'
+    ) THEN
+        DROP TABLE calendar_tokens;
+    END IF;
+END
+$$;
+
+-- テーブルを作成する
+-- タスクはまだアカウントごとに分かれていないため、API全体で1つのトークンだけを保持する (1行だけ)
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+    id BOOLEAN NOT NULL DEFAULT TRUE CHECK (id),
+    token_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id)
+);
+
+-- コメントを設定する
+COMMENT ON TABLE calendar_tokens IS 'カレンダーフィードのトークン (1行だけ)';
+COMMENT ON COLUMN calendar_tokens.token_hash IS 'フィードトークンのSHA-256ハッシュ';
+COMMENT ON COLUMN calendar_tokens.created_at IS '発行日時';
@@ -0,0 +1,24 @@
+-- テーブルを作成する
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    account_id BIGINT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL,
+    status_code INTEGER,
+    header JSONB,
+    body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (account_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+-- コメントを設定する
+COMMENT ON COLUMN idempotency_keys.account_id IS 'アカウントID';
+COMMENT ON COLUMN idempotency_keys.key IS 'Idempotency-Key ヘッダーの値';
+COMMENT ON COLUMN idempotency_keys.fingerprint IS 'リクエストのハッシュ';
+COMMENT ON COLUMN idempotency_keys.status_code IS 'レスポンスのステータスコード (処理中はNULL)';
+COMMENT ON COLUMN idempotency_keys.header IS 'レスポンスヘッダー';
+COMMENT ON COLUMN idempotency_keys.body IS 'レスポンスボディ';
+COMMENT ON COLUMN idempotency_keys.created_at IS '作成日時';
+COMMENT ON COLUMN idempotency_keys.expires_at IS '有効期限';
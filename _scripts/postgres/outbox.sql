@@ -0,0 +1,52 @@
+-- テーブルを作成する
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    task_id BIGINT NOT NULL,
+    account_id BIGINT NOT NULL DEFAULT 0,
+    task JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_account_id_idx ON outbox (account_id, id);
+CREATE INDEX IF NOT EXISTS outbox_occurred_at_idx ON outbox (occurred_at) WHERE published_at IS NOT NULL;
+
+-- イベントのIDをコミットの順に採番する関数を作成する
+-- タスクの書き込みと同じアドバイザリーロック (tasks.sql の trigger_set_change を参照) を取得してから採番することで、
+-- 先に採番したトランザクションがコミットするまで、他のトランザクションは採番できない
+-- イベントストリーム (OutboxSQL.FindAfter) は、この順番を前提に最後に送ったIDより後のイベントだけを読み取る
+-- ロックを外す場合は、コミットの時点の値で並べるようにイベントストリームも変更する
+CREATE OR REPLACE FUNCTION trigger_assign_outbox_id() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('task_change_seq'));
+    NEW.id = nextval(pg_get_serial_sequence('outbox', 'id'));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS outbox_assign_id ON outbox;
+CREATE TRIGGER outbox_assign_id BEFORE INSERT ON outbox FOR EACH ROW EXECUTE FUNCTION trigger_assign_outbox_id();
+
+-- イベントを書き込んだアカウントIDを task_events チャンネルに通知する
+-- 通知はコミットした時に送られ、同じトランザクション内の同じアカウントの通知は1つにまとめられる
+CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('task_events', NEW.account_id::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS outbox_notify ON outbox;
+CREATE TRIGGER outbox_notify AFTER INSERT ON outbox FOR EACH ROW EXECUTE FUNCTION notify_outbox_event();
+
+-- コメントを設定する
+COMMENT ON COLUMN outbox.id IS 'イベントID (コミットした順の連番)';
+COMMENT ON COLUMN outbox.event_type IS 'イベントの種類';
+COMMENT ON COLUMN outbox.task_id IS 'タスクID';
+COMMENT ON COLUMN outbox.account_id IS '変更を行ったアカウントID';
+COMMENT ON COLUMN outbox.task IS '変更後のタスク (削除の場合はNULL)';
+COMMENT ON COLUMN outbox.occurred_at IS '発生日時';
+COMMENT ON COLUMN outbox.published_at IS '発行日時 (未発行の場合はNULL)';
@@ -0,0 +1,20 @@
+-- テーブルを作成する
+-- スキーマを変更するスクリプトは、既存のDBに再度適用できるように書き (ADD COLUMN IF NOT EXISTS など)、
+-- 最後に上げたバージョンを記録する (tasks.sql を参照)
+-- バージョンを上げた場合は database.SchemaVersion も合わせて更新する
+-- スクリプトはファイル名の順に実行されるため、バージョンを記録するスクリプトはこのファイルより後に実行される名前にする
+CREATE TABLE IF NOT EXISTS schema_version (
+    id BOOLEAN NOT NULL DEFAULT TRUE CHECK (id),
+    version INTEGER NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+-- 既に記録したバージョンは下げない
+INSERT INTO schema_version (version) VALUES (1)
+ON CONFLICT (id) DO NOTHING;
+
+-- コメントを設定する
+COMMENT ON TABLE schema_version IS '適用済みのスキーマのバージョン (1行だけ)';
+COMMENT ON COLUMN schema_version.version IS 'スキーマのバージョン';
+COMMENT ON COLUMN schema_version.applied_at IS 'バージョンを適用した日時';
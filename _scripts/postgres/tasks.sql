@@ -2,49 +2,174 @@
 CREATE TABLE IF NOT EXISTS tasks (
     id SERIAL NOT NULL,
     title VARCHAR(15) NOT NULL,
+    due_date TIMESTAMPTZ,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    priority SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 9),
+    recurrence TEXT NOT NULL DEFAULT '',
+    contexts TEXT[] NOT NULL DEFAULT '{}',
+    extensions JSONB NOT NULL DEFAULT '[]',
+    completed_at TIMESTAMPTZ,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    change_seq BIGINT NOT NULL DEFAULT 0,
+    field_versions JSONB NOT NULL DEFAULT '{}',
     PRIMARY KEY (id)
 );
 
+-- 以前のスクリプトで作成したテーブルに、後から追加したカラムを追加する
+-- (このスクリプトは既存のDBに再度適用できる)
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS due_date TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS completed BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 9),
+    ADD COLUMN IF NOT EXISTS recurrence TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS contexts TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS extensions JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS field_versions JSONB NOT NULL DEFAULT '{}';
+
+-- 以前のスクリプトでタイムゾーンなしで作成した期日・完了日時を、UTC の日時として TIMESTAMPTZ に変換する
+-- 再度適用した時に変換を繰り返さないよう、型がタイムゾーンなしの場合だけ変換する
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'tasks' AND column_name = 'due_date' AND data_type = 'timestamp without time zone'
+    ) THEN
+        ALTER TABLE tasks ALTER COLUMN due_date TYPE TIMESTAMPTZ USING due_date AT TIME ZONE 'UTC';
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'tasks' AND column_name = 'completed_at' AND data_type = 'timestamp without time zone'
+    ) THEN
+        ALTER TABLE tasks ALTER COLUMN completed_at TYPE TIMESTAMPTZ USING completed_at AT TIME ZONE 'UTC';
+    END IF;
+END
+$$;
+
+-- タスクの変更ごとに採番する連番
+CREATE SEQUENCE IF NOT EXISTS task_change_seq;
+
+CREATE INDEX IF NOT EXISTS tasks_change_seq_idx ON tasks (change_seq);
+
+-- 削除したタスク (同期でクライアントに削除を伝えるために残す)
+CREATE TABLE IF NOT EXISTS task_tombstones (
+    task_id BIGINT NOT NULL,
+    change_seq BIGINT NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_tombstones_change_seq_idx ON task_tombstones (change_seq);
+
 -- コメントを設定する
 COMMENT ON COLUMN tasks.id IS 'タスクID';
 COMMENT ON COLUMN tasks.title IS 'タイトル';
+COMMENT ON COLUMN tasks.due_date IS '期日';
+COMMENT ON COLUMN tasks.tags IS 'タグ';
+COMMENT ON COLUMN tasks.completed IS '完了フラグ';
+COMMENT ON COLUMN tasks.priority IS '優先度 (1が最も高く、0は未設定)';
+COMMENT ON COLUMN tasks.recurrence IS '繰り返しの規則 (RFC 5545 の RRULE)';
+COMMENT ON COLUMN tasks.contexts IS 'コンテキスト (todo.txt の @context)';
+COMMENT ON COLUMN tasks.extensions IS '拡張項目 (todo.txt の key:value を順番通りに保持する)';
+COMMENT ON COLUMN tasks.completed_at IS '完了日時';
 COMMENT ON COLUMN tasks.created_at IS '作成日時';
 COMMENT ON COLUMN tasks.updated_at IS '更新日時';
+COMMENT ON COLUMN tasks.change_seq IS '最後に変更した時の連番';
+COMMENT ON COLUMN tasks.field_versions IS '項目ごとの最後の変更 ({"title": {"updated_at": ..., "seq": ...}, ...})';
+COMMENT ON COLUMN task_tombstones.task_id IS 'タスクID';
+COMMENT ON COLUMN task_tombstones.change_seq IS '削除した時の連番';
+COMMENT ON COLUMN task_tombstones.deleted_at IS '削除日時';
 
 -- 関数を作成する
-CREATE FUNCTION trigger_set_timestamp() RETURNS TRIGGER AS $$
+CREATE OR REPLACE FUNCTION trigger_set_timestamp() RETURNS TRIGGER AS $$
 BEGIN
     NEW.updated_at = NOW();
     RETURN NEW;
 END;
 $$ LANGUAGE plpgsql;
 
+-- 完了フラグに合わせて完了日時をセットする関数を作成する
+CREATE OR REPLACE FUNCTION trigger_set_completed_at() RETURNS TRIGGER AS $$
+BEGIN
+    IF NOT NEW.completed THEN
+        NEW.completed_at = NULL;
+    ELSIF TG_OP = 'INSERT' OR NOT OLD.completed THEN
+        NEW.completed_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- 変更の連番と項目ごとの変更を記録する関数を作成する
+-- 連番の順番とコミットの順番を揃えるため、採番する前にタスクの書き込みのロックを取得する
+-- このロックはタスクを書き込むすべてのトランザクションをコミットまで直列化する
+-- outbox のIDの採番 (outbox.sql の trigger_assign_outbox_id) も同じロックを使い、イベントストリームがIDの順に読み取れるようにしている
+-- 項目の変更日時は、書き込む値で変更されていればその日時 (同期でクライアントが変更した日時)、そうでなければ現在日時とする
+CREATE OR REPLACE FUNCTION trigger_set_change() RETURNS TRIGGER AS $$
+DECLARE
+    field TEXT;
+    changed_at TIMESTAMPTZ;
+    versions JSONB;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('task_change_seq'));
+    NEW.change_seq = nextval('task_change_seq');
+
+    versions = CASE WHEN TG_OP = 'UPDATE' THEN OLD.field_versions ELSE '{}' END;
+    FOREACH field IN ARRAY ARRAY['title', 'due_date', 'tags', 'completed', 'priority', 'recurrence', 'contexts', 'extensions'] LOOP
+        IF TG_OP = 'INSERT' OR to_jsonb(NEW) -> field IS DISTINCT FROM to_jsonb(OLD) -> field THEN
+            changed_at = (NEW.field_versions -> field ->> 'updated_at')::TIMESTAMPTZ;
+            IF changed_at IS NULL OR changed_at IS NOT DISTINCT FROM (versions -> field ->> 'updated_at')::TIMESTAMPTZ THEN
+                changed_at = NOW();
+            END IF;
+            versions = jsonb_set(versions, ARRAY[field], jsonb_build_object('updated_at', changed_at, 'seq', NEW.change_seq));
+        END IF;
+    END LOOP;
+
+    NEW.field_versions = versions;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- 削除したタスクを記録する関数を作成する
+CREATE OR REPLACE FUNCTION trigger_record_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('task_change_seq'));
+    INSERT INTO task_tombstones (task_id, change_seq) VALUES (OLD.id, nextval('task_change_seq'))
+    ON CONFLICT (task_id) DO UPDATE SET change_seq = EXCLUDED.change_seq, deleted_at = EXCLUDED.deleted_at;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
 -- トリガーを作成する
+DROP TRIGGER IF EXISTS set_change ON tasks;
+DROP TRIGGER IF EXISTS record_tombstone ON tasks;
+DROP TRIGGER IF EXISTS set_timestamp ON tasks;
+DROP TRIGGER IF EXISTS set_completed_at ON tasks;
+CREATE TRIGGER set_change BEFORE INSERT OR UPDATE ON tasks FOR EACH ROW EXECUTE PROCEDURE trigger_set_change();
+CREATE TRIGGER record_tombstone AFTER DELETE ON tasks FOR EACH ROW EXECUTE PROCEDURE trigger_record_tombstone();
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON tasks FOR EACH ROW EXECUTE PROCEDURE trigger_set_timestamp();
+CREATE TRIGGER set_completed_at BEFORE INSERT OR UPDATE ON tasks FOR EACH ROW EXECUTE PROCEDURE trigger_set_completed_at();
 
--- ダミーデータをインサートする
-INSERT INTO tasks (
-    title
-) 
-VALUES 
-(
-    'task1'
-), 
-(
-    'task2'
-), 
-(
-    'task3'
-);
+-- ダミーデータをインサートする (再度適用した場合は追加しない)
+INSERT INTO tasks (title)
+SELECT title FROM (VALUES ('task1'), ('task2'), ('task3')) AS dummy (title)
+WHERE NOT EXISTS (SELECT 1 FROM tasks);
+
+-- スキーマのバージョンを記録する (schema_version.sql を参照)
+-- 2: タスクの項目 (期日・タグなど) と同期用のカラム・削除の記録、outbox のIDをコミットした順に採番するトリガー
+-- 3: 期日・完了日時を TIMESTAMPTZ に変更
+-- 4: カレンダーフィードのトークンをAPI全体で1つにする (calendar_tokens.sql)
+INSERT INTO schema_version (version) VALUES (4)
+ON CONFLICT (id) DO UPDATE SET version = GREATEST(schema_version.version, EXCLUDED.version), applied_at = CURRENT_TIMESTAMP;
 
 -- account_id INTEGER NOT NULL,
 -- COMMENT ON COLUMN tasks.account_id IS 'アカウントID';
 
--- completed INTEGER CHECK(id = 0 OR id = 1) NOT NULL DEFAULT 0,
--- COMMENT ON COLUMN tasks.completed IS '完了フラグ 0:未完了 1:完了';
-
 -- CREATE TABLE IF NOT EXISTS accountstodo (
 --     id SERIAL NOT NULL,
 --     name VARCHAR(15) NOT NULL,
@@ -0,0 +1,76 @@
+-- テーブルを作成する
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGSERIAL NOT NULL,
+    account_id BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    secret TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_account_id_idx ON webhook_subscriptions (account_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL NOT NULL,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id VARCHAR(32) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload BYTEA NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_id_idx ON webhook_deliveries (subscription_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_event_idx ON webhook_deliveries (subscription_id, event_id);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    delivery_id BIGINT NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    attempted_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (delivery_id, attempt)
+);
+
+-- コメントを設定する
+COMMENT ON COLUMN webhook_subscriptions.id IS 'ID';
+COMMENT ON COLUMN webhook_subscriptions.account_id IS 'アカウントID';
+COMMENT ON COLUMN webhook_subscriptions.url IS '配信先のURL';
+COMMENT ON COLUMN webhook_subscriptions.events IS '購読するイベントの種類';
+COMMENT ON COLUMN webhook_subscriptions.secret IS '署名に使用する秘密鍵';
+COMMENT ON COLUMN webhook_subscriptions.active IS '有効フラグ';
+COMMENT ON COLUMN webhook_subscriptions.consecutive_failures IS '連続で配信に失敗した回数';
+COMMENT ON COLUMN webhook_subscriptions.created_at IS '作成日時';
+COMMENT ON COLUMN webhook_subscriptions.updated_at IS '更新日時';
+
+COMMENT ON COLUMN webhook_deliveries.id IS 'ID';
+COMMENT ON COLUMN webhook_deliveries.subscription_id IS '購読ID';
+COMMENT ON COLUMN webhook_deliveries.event_id IS 'イベントID';
+COMMENT ON COLUMN webhook_deliveries.event_type IS 'イベントの種類';
+COMMENT ON COLUMN webhook_deliveries.payload IS '送信するJSON (署名の対象になるためバイト列のまま保持する)';
+COMMENT ON COLUMN webhook_deliveries.status IS '配信の状態';
+COMMENT ON COLUMN webhook_deliveries.attempts IS '試行回数';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS '次に試行する日時';
+COMMENT ON COLUMN webhook_deliveries.last_status_code IS '最後の試行のステータスコード';
+COMMENT ON COLUMN webhook_deliveries.last_error IS '最後の試行のエラー';
+COMMENT ON COLUMN webhook_deliveries.created_at IS '作成日時';
+COMMENT ON COLUMN webhook_deliveries.updated_at IS '更新日時';
+
+COMMENT ON COLUMN webhook_delivery_attempts.delivery_id IS '配信ID';
+COMMENT ON COLUMN webhook_delivery_attempts.attempt IS '何回目の試行か';
+COMMENT ON COLUMN webhook_delivery_attempts.status_code IS 'ステータスコード (応答がなかった場合は0)';
+COMMENT ON COLUMN webhook_delivery_attempts.error IS 'エラー';
+COMMENT ON COLUMN webhook_delivery_attempts.duration_ms IS '所要時間 (ミリ秒)';
+COMMENT ON COLUMN webhook_delivery_attempts.attempted_at IS '試行日時';
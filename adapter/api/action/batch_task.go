@@ -0,0 +1,154 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type BatchTaskAction struct {
+	uc        usecase.BatchTaskUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewBatchTaskAction(uc usecase.BatchTaskUseCase, log logger.Logger, v validator.Validator) BatchTaskAction {
+	return BatchTaskAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// 一括操作のレスポンス
+type batchTaskResponse struct {
+	Committed bool                   `json:"committed"`
+	Results   []batchOperationResult `json:"results"`
+}
+
+// 操作ごとの結果
+type batchOperationResult struct {
+	Index  int                       `json:"index"`
+	Op     string                    `json:"op"`
+	Status int                       `json:"status"`
+	TaskID domain.TaskID             `json:"task_id,omitempty"`
+	Task   *usecase.UpdateTaskOutput `json:"task,omitempty"`
+	Error  *response.Error           `json:"error,omitempty"`
+}
+
+func (t BatchTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "batch_task"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	var input usecase.BatchTaskInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	if errs := t.validateInput(r.Context(), input); len(errs) > 0 {
+		logging.NewError(
+			t.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewValidationError(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := t.uc.Execute(r.Context(), input)
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			status,
+		).Log("error when executing batch operations")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	// ロールバックされた場合は、失敗した操作のステータスをレスポンス全体のステータスとする
+	var (
+		status  = http.StatusOK
+		results = make([]batchOperationResult, len(output.Results))
+	)
+	for i, res := range output.Results {
+		results[i] = batchOperationResult{
+			Index:  i,
+			Op:     res.Op,
+			TaskID: res.TaskID,
+			Task:   res.Task,
+		}
+
+		switch {
+		case res.Err != nil:
+			var opStatus = response.StatusCode(res.Err)
+			var problem = response.NewError(res.Err, opStatus).Embed(w)
+
+			results[i].Status = opStatus
+			results[i].Error = &problem
+			if !output.Committed {
+				status = opStatus
+			}
+		case !output.Committed:
+			// 他の操作の失敗によって取り消された、または実行されなかった
+			results[i].Status = http.StatusFailedDependency
+		default:
+			results[i].Status = successStatus(res.Op)
+		}
+	}
+
+	if output.Committed {
+		logging.NewInfo(t.log, logKey, status).Log("success executing batch operations")
+	} else {
+		logging.NewError(
+			t.log,
+			response.ErrBatchRolledBack,
+			logKey,
+			status,
+		).Log("batch operations rolled back")
+	}
+
+	response.NewSuccess(batchTaskResponse{
+		Committed: output.Committed,
+		Results:   results,
+	}, status).Send(w)
+}
+
+func (t BatchTaskAction) validateInput(ctx context.Context, input usecase.BatchTaskInput) []validator.Violation {
+	return t.validator.Validate(ctx, input)
+}
+
+// 単体のエンドポイントと同じ成功時のステータスを返却する
+func successStatus(op string) int {
+	switch op {
+	case usecase.BatchOpCreate:
+		return http.StatusCreated
+	case usecase.BatchOpDelete:
+		return http.StatusNoContent
+	}
+
+	return http.StatusOK
+}
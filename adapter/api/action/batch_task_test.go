@@ -0,0 +1,137 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/infrastructure/validation"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type mockBatchTask struct {
+	result usecase.BatchTaskOutput
+	err    error
+}
+
+func (m mockBatchTask) Execute(_ context.Context, _ usecase.BatchTaskInput) (usecase.BatchTaskOutput, error) {
+	return m.result, m.err
+}
+
+func TestBatchTaskAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	var payload = []byte(`{
+		"operations": [
+			{"op": "create", "task": {"title": "Task_1"}},
+			{"op": "delete", "task_id": 2}
+		]
+	}`)
+
+	tests := []struct {
+		name               string
+		rawPayload         []byte
+		ucMock             usecase.BatchTaskUseCase
+		expectedBody       string
+		expectedStatusCode int
+	}{
+		// 正常値
+		{
+			name:       "BatchTaskAction success",
+			rawPayload: payload,
+			ucMock: mockBatchTask{
+				result: usecase.BatchTaskOutput{
+					Committed: true,
+					Results: []usecase.BatchOperationOutput{
+						{Op: "create", TaskID: 1, Task: &usecase.UpdateTaskOutput{ID: 1, Title: "Task_1", Tags: []string{}}},
+						{Op: "delete", TaskID: 2},
+					},
+				},
+			},
+			expectedBody:       `{"committed":true,"results":[{"index":0,"op":"create","status":201,"task_id":1,"task":{"id":1,"title":"Task_1","tags":[],"completed":false,"created_at":"","updated_at":""}},{"index":1,"op":"delete","status":204,"task_id":2}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:       "BatchTaskAction continue on error",
+			rawPayload: payload,
+			ucMock: mockBatchTask{
+				result: usecase.BatchTaskOutput{
+					Committed: true,
+					Results: []usecase.BatchOperationOutput{
+						{Op: "create", TaskID: 1, Task: &usecase.UpdateTaskOutput{ID: 1, Title: "Task_1", Tags: []string{}}},
+						{Op: "delete", TaskID: 2, Err: domain.ErrTaskNotFound},
+					},
+				},
+			},
+			expectedBody:       `{"committed":true,"results":[{"index":0,"op":"create","status":201,"task_id":1,"task":{"id":1,"title":"Task_1","tags":[],"completed":false,"created_at":"","updated_at":""}},{"index":1,"op":"delete","status":404,"task_id":2,"error":{"type":"/problems/not-found","title":"Not Found","status":404,"detail":"task not found"}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+
+		// 異常値
+		{
+			name:       "BatchTaskAction rolled back",
+			rawPayload: payload,
+			ucMock: mockBatchTask{
+				result: usecase.BatchTaskOutput{
+					Committed: false,
+					Results: []usecase.BatchOperationOutput{
+						{Op: "create"},
+						{Op: "delete", TaskID: 2, Err: domain.ErrTaskNotFound},
+					},
+				},
+			},
+			expectedBody:       `{"committed":false,"results":[{"index":0,"op":"create","status":424},{"index":1,"op":"delete","status":404,"task_id":2,"error":{"type":"/problems/not-found","title":"Not Found","status":404,"detail":"task not found"}}]}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "BatchTaskAction error invalid operation",
+			rawPayload:         []byte(`{"operations": [{"op": "update", "task_id": 1}]}`),
+			ucMock:             mockBatchTask{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"operations[0].task","reason":"task is a required field","rule":"required_if","param":"Op update"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "BatchTaskAction error create with past due date",
+			rawPayload:         []byte(`{"operations": [{"op": "create", "task": {"title": "Task_1", "due_date": "2000-01-01T00:00:00Z"}}, {"op": "update", "task_id": 1, "task": {"title": "Task_1", "due_date": "2000-01-01T00:00:00Z"}}]}`),
+			ucMock:             mockBatchTask{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"operations[0].task.due_date","reason":"task.due_date must not be in the past unless the task is completed","rule":"notpast"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "BatchTaskAction error empty operations",
+			rawPayload:         []byte(`{"operations": []}`),
+			ucMock:             mockBatchTask{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"operations","reason":"operations must contain at least 1 item","rule":"gte","param":"1"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/tasks:batch", bytes.NewReader(tt.rawPayload))
+
+			var (
+				w      = httptest.NewRecorder()
+				action = NewBatchTaskAction(tt.ucMock, log.LoggerMock{}, validator)
+			)
+
+			action.Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Status: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if result != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expectedBody)
+			}
+		})
+	}
+}
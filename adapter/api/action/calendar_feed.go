@@ -0,0 +1,118 @@
+package action
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/ical"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+const calendarProdID = "-//todo-api-go//tasks//EN"
+
+type CalendarFeedAction struct {
+	uc  usecase.CalendarFeedUseCase
+	log logger.Logger
+}
+
+func NewCalendarFeedAction(uc usecase.CalendarFeedUseCase, log logger.Logger) CalendarFeedAction {
+	return CalendarFeedAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+// タスクを iCalendar の VTODO として返却する
+// カレンダーアプリはヘッダーを指定できないため、token クエリパラメータで認証する
+// 内容が変わっていない場合は If-None-Match に対して 304 を返却する
+func (a CalendarFeedAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "calendar_feed"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	output, err := a.uc.Execute(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when rendering calendar feed")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	var calendar = ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   "Tasks",
+		Todos:  make([]ical.Todo, 0, len(output)),
+	}
+	for _, task := range output {
+		calendar.Todos = append(calendar.Todos, ical.Todo{
+			UID:          fmt.Sprintf("task-%d@todo-api-go", task.ID),
+			Summary:      task.Title,
+			Due:          task.DueDate,
+			Completed:    task.Completed,
+			CompletedAt:  task.CompletedAt,
+			Priority:     task.Priority,
+			RRule:        task.Recurrence,
+			Categories:   task.Tags,
+			Created:      task.CreatedAt,
+			LastModified: task.UpdatedAt,
+		})
+	}
+
+	var body bytes.Buffer
+	if err := ical.Encode(&body, calendar); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log("error when encoding calendar feed")
+
+		response.NewError(err, http.StatusInternalServerError).Send(w)
+		return
+	}
+
+	var sum = sha256.Sum256(body.Bytes())
+	var etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		logging.NewInfo(a.log, logKey, http.StatusNotModified).Log("calendar feed not modified")
+
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success rendering calendar feed")
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body.Bytes())
+}
+
+// If-None-Match のいずれかのエンティティタグと一致するか (弱い比較, RFC 9110 13.1.2)
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
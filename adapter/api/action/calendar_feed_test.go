@@ -0,0 +1,104 @@
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type mockCalendarFeed struct {
+	result []usecase.CalendarTaskOutput
+	err    error
+}
+
+func (m mockCalendarFeed) Execute(_ context.Context, _ string) ([]usecase.CalendarTaskOutput, error) {
+	return m.result, m.err
+}
+
+func TestCalendarFeedAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		due  = time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+		at   = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		feed = mockCalendarFeed{result: []usecase.CalendarTaskOutput{
+			{ID: 1, Title: "Task_1", DueDate: &due, Priority: 3, CreatedAt: at, UpdatedAt: at},
+		}}
+	)
+
+	// 最初のレスポンスの ETag を取得する
+	var first = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/calendar.ics?token=secret", nil)
+	NewCalendarFeedAction(feed, log.LoggerMock{}).Execute(first, req)
+
+	var etag = first.Header().Get("ETag")
+
+	tests := []struct {
+		name               string
+		ucMock             usecase.CalendarFeedUseCase
+		ifNoneMatch        string
+		expectedBody       string
+		expectedStatusCode int
+	}{
+		// 正常値
+		{
+			name:               "CalendarFeedAction success",
+			ucMock:             feed,
+			expectedBody:       "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//todo-api-go//tasks//EN\r\nCALSCALE:GREGORIAN\r\nX-WR-CALNAME:Tasks\r\nBEGIN:VTODO\r\nUID:task-1@todo-api-go\r\nDTSTAMP:20240101T090000Z\r\nCREATED:20240101T090000Z\r\nLAST-MODIFIED:20240101T090000Z\r\nSUMMARY:Task_1\r\nDUE:20240131T180000Z\r\nSTATUS:NEEDS-ACTION\r\nPRIORITY:3\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "CalendarFeedAction not modified",
+			ucMock:             feed,
+			ifNoneMatch:        `"other", W/` + etag,
+			expectedBody:       "",
+			expectedStatusCode: http.StatusNotModified,
+		},
+		{
+			name:               "CalendarFeedAction modified",
+			ucMock:             mockCalendarFeed{result: []usecase.CalendarTaskOutput{}},
+			ifNoneMatch:        etag,
+			expectedBody:       "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//todo-api-go//tasks//EN\r\nCALSCALE:GREGORIAN\r\nX-WR-CALNAME:Tasks\r\nEND:VCALENDAR\r\n",
+			expectedStatusCode: http.StatusOK,
+		},
+
+		// 異常値
+		{
+			name:               "CalendarFeedAction invalid token",
+			ucMock:             mockCalendarFeed{err: domain.ErrCalendarTokenInvalid},
+			expectedBody:       `{"type":"/problems/forbidden","title":"Forbidden","status":403,"detail":"invalid calendar feed token"}` + "\n",
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/calendar.ics?token=secret", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			var w = httptest.NewRecorder()
+			NewCalendarFeedAction(tt.ucMock, log.LoggerMock{}).Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Status: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+
+			if w.Body.String() != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%q' | Expected: '%q'", tt.name, w.Body.String(), tt.expectedBody)
+			}
+
+			if tt.expectedStatusCode == http.StatusOK && !strings.HasPrefix(w.Header().Get("ETag"), `"`) {
+				t.Errorf("[TestCase '%s'] missing ETag header", tt.name)
+			}
+		})
+	}
+}
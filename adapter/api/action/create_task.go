@@ -1,6 +1,7 @@
 package action
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
@@ -28,6 +29,9 @@ func NewCreateTaskAction(uc usecase.CreateTaskUseCase, log logger.Logger, v vali
 func (t CreateTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
 	const logKey = "create_task"
 
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
 	var input usecase.CreateTaskInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		logging.NewError(
@@ -42,7 +46,7 @@ func (t CreateTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	if errs := t.validateInput(input); len(errs) > 0 {
+	if errs := t.validateInput(r.Context(), input); len(errs) > 0 {
 		logging.NewError(
 			t.log,
 			response.ErrInvalidInput,
@@ -50,20 +54,21 @@ func (t CreateTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
 			http.StatusBadRequest,
 		).Log("invalid input")
 
-		response.NewErrorMessage(errs, http.StatusBadRequest).Send(w)
+		response.NewValidationError(errs, http.StatusBadRequest).Send(w)
 		return
 	}
 
 	output, err := t.uc.Execute(r.Context(), input)
 	if err != nil {
+		var status = response.StatusCode(err)
 		logging.NewError(
 			t.log,
 			err,
 			logKey,
-			http.StatusInternalServerError,
+			status,
 		).Log("error when creating a new task")
 
-		response.NewError(err, http.StatusInternalServerError).Send(w)
+		response.NewError(err, status).Send(w)
 		return
 	}
 
@@ -72,16 +77,6 @@ func (t CreateTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
 	response.NewSuccess(output, http.StatusCreated).Send(w)
 }
 
-func (t CreateTaskAction) validateInput(input usecase.CreateTaskInput) []string {
-	var msgs []string
-
-	err := t.validator.Validate(input)
-	if err != nil {
-		for _, msg := range t.validator.Messages() {
-			msg := msg
-			msgs = append(msgs, msg)
-		}
-	}
-
-	return msgs
+func (t CreateTaskAction) validateInput(ctx context.Context, input usecase.CreateTaskInput) []validator.Violation {
+	return t.validator.Validate(ctx, input)
 }
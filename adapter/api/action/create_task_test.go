@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/doglapping707/todo-api-go/domain"
 	"github.com/doglapping707/todo-api-go/infrastructure/log"
 	"github.com/doglapping707/todo-api-go/infrastructure/validation"
 	"github.com/doglapping707/todo-api-go/usecase"
@@ -56,7 +59,9 @@ func TestCreateTaskAction_Execute(t *testing.T) {
 			// output
 			ucMock: mockCreateTask{
 				result: usecase.CreateTaskOutput{
+					ID:        1,
 					Title:     "Test Task",
+					Tags:      []string{},
 					CreatedAt: time.Time{}.String(),
 					UpdatedAt: time.Time{}.String(),
 				},
@@ -64,7 +69,7 @@ func TestCreateTaskAction_Execute(t *testing.T) {
 			},
 
 			// 期待値
-			expectedBody:       `{"title":"Test Task","created_at":"0001-01-01 00:00:00 +0000 UTC","updated_at":"0001-01-01 00:00:00 +0000 UTC"}`,
+			expectedBody:       `{"id":1,"title":"Test Task","tags":[],"completed":false,"created_at":"0001-01-01 00:00:00 +0000 UTC","updated_at":"0001-01-01 00:00:00 +0000 UTC"}`,
 			expectedStatusCode: http.StatusCreated,
 		},
 
@@ -87,9 +92,30 @@ func TestCreateTaskAction_Execute(t *testing.T) {
 			},
 
 			// 期待値
-			expectedBody:       `{"errors":["error"]}`,
+			expectedBody:       `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 			expectedStatusCode: http.StatusInternalServerError,
 		},
+		{
+			// input
+			name: "CreateTaskAction error unavailable",
+			args: args{
+				rawPayload: []byte(
+					`{
+						"title": "Test Task"
+					}`,
+				),
+			},
+
+			// output
+			ucMock: mockCreateTask{
+				result: usecase.CreateTaskOutput{},
+				err:    domain.NewError(domain.KindUnavailable, "deadlock detected"),
+			},
+
+			// 期待値
+			expectedBody:       `{"type":"/problems/unavailable","title":"Service Unavailable","status":503}`,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
 		{
 			// input
 			name: "CreateTaskAction error invalid title",
@@ -108,7 +134,7 @@ func TestCreateTaskAction_Execute(t *testing.T) {
 			},
 
 			// 期待値
-			expectedBody:       `{"errors":["Title is a required field"]}`,
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title is a required field","rule":"required"}]}`,
 			expectedStatusCode: http.StatusBadRequest,
 		},
 		{
@@ -129,7 +155,7 @@ func TestCreateTaskAction_Execute(t *testing.T) {
 			},
 
 			// 期待値
-			expectedBody:       `{"errors":["Title must be at maximum 15 characters in length"]}`,
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title must be at maximum 15 characters in length","rule":"lte","param":"15"}]}`,
 			expectedStatusCode: http.StatusBadRequest,
 		},
 		{
@@ -150,7 +176,7 @@ func TestCreateTaskAction_Execute(t *testing.T) {
 			},
 
 			// 期待値
-			expectedBody:       `{"errors":["Title is a required field"]}`,
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title is a required field","rule":"required"}]}`,
 			expectedStatusCode: http.StatusBadRequest,
 		},
 		{
@@ -171,7 +197,7 @@ func TestCreateTaskAction_Execute(t *testing.T) {
 			},
 
 			// 期待値
-			expectedBody:       `{"errors":["invalid character '}' looking for beginning of value"]}`,
+			expectedBody:       `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid character '}' looking for beginning of value"}`,
 			expectedStatusCode: http.StatusBadRequest,
 		},
 	}
@@ -216,3 +242,76 @@ func TestCreateTaskAction_Execute(t *testing.T) {
 		})
 	}
 }
+
+// 1つのバリデーターを共有したまま並行にリクエストを処理しても、
+// 他のリクエストの検証結果が混ざらないことを確認する (go test -race で実行する)
+func TestCreateTaskAction_Execute_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	var (
+		ucMock = mockCreateTask{
+			result: usecase.CreateTaskOutput{ID: 1, Title: "Test Task", Tags: []string{}},
+		}
+		action = NewCreateTaskAction(ucMock, log.LoggerMock{}, validator)
+	)
+
+	tests := []struct {
+		rawPayload         string
+		expectedBody       string
+		expectedStatusCode int
+	}{
+		{
+			rawPayload:         `{"title": "Test Task"}`,
+			expectedBody:       `{"id":1,"title":"Test Task","tags":[],"completed":false,"created_at":"","updated_at":""}`,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			rawPayload:         `{"title": ""}`,
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title is a required field","rule":"required"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			rawPayload:         `{"title": "aaaabbbbccccdddd"}`,
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title must be at maximum 15 characters in length","rule":"lte","param":"15"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	const workers = 50
+	const iterations = 20
+
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan string, workers*iterations)
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			for j := 0; j < iterations; j++ {
+				var tt = tests[(worker+j)%len(tests)]
+
+				req, _ := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(tt.rawPayload))
+				var w = httptest.NewRecorder()
+
+				action.Execute(w, req)
+
+				var result = strings.TrimSpace(w.Body.String())
+				if w.Code != tt.expectedStatusCode || result != tt.expectedBody {
+					errs <- fmt.Sprintf("payload '%s' | Status: '%v' Result: '%v'", tt.rawPayload, w.Code, result)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,82 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type CreateWebhookAction struct {
+	uc        usecase.CreateWebhookUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewCreateWebhookAction(uc usecase.CreateWebhookUseCase, log logger.Logger, v validator.Validator) CreateWebhookAction {
+	return CreateWebhookAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a CreateWebhookAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "create_webhook"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	var input usecase.CreateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	if errs := a.validateInput(r.Context(), input); len(errs) > 0 {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewValidationError(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when creating a new webhook")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success creating webhook")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a CreateWebhookAction) validateInput(ctx context.Context, input usecase.CreateWebhookInput) []validator.Violation {
+	return a.validator.Validate(ctx, input)
+}
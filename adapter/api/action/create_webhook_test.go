@@ -0,0 +1,115 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/infrastructure/validation"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type mockCreateWebhook struct {
+	result usecase.WebhookOutput
+	err    error
+}
+
+func (m mockCreateWebhook) Execute(_ context.Context, _ usecase.CreateWebhookInput) (usecase.WebhookOutput, error) {
+	return m.result, m.err
+}
+
+func TestCreateWebhookAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	tests := []struct {
+		name               string
+		rawPayload         string
+		ucMock             usecase.CreateWebhookUseCase
+		expectedBody       string
+		expectedStatusCode int
+	}{
+		{
+			name:       "CreateWebhookAction success",
+			rawPayload: `{"url": "https://example.com/hooks", "events": ["task.created", "task.deleted"]}`,
+			ucMock: mockCreateWebhook{
+				result: usecase.WebhookOutput{
+					ID:        1,
+					URL:       "https://example.com/hooks",
+					Events:    []string{"task.created", "task.deleted"},
+					Secret:    "s3cr3t",
+					Active:    true,
+					CreatedAt: "2024-01-04T10:02:14Z",
+					UpdatedAt: "2024-01-04T10:02:14Z",
+				},
+			},
+			expectedBody:       `{"id":1,"url":"https://example.com/hooks","events":["task.created","task.deleted"],"secret":"s3cr3t","active":true,"consecutive_failures":0,"created_at":"2024-01-04T10:02:14Z","updated_at":"2024-01-04T10:02:14Z"}`,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "CreateWebhookAction error invalid url",
+			rawPayload:         `{"url": "example.com/hooks", "events": ["task.created"]}`,
+			ucMock:             mockCreateWebhook{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"url","reason":"url must be an absolute http or https URL","rule":"httpurl"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "CreateWebhookAction error no events",
+			rawPayload:         `{"url": "https://example.com/hooks", "events": []}`,
+			ucMock:             mockCreateWebhook{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"events","reason":"events must contain at least 1 item","rule":"gte","param":"1"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "CreateWebhookAction error short secret",
+			rawPayload:         `{"url": "https://example.com/hooks", "events": ["task.updated"], "secret": "short"}`,
+			ucMock:             mockCreateWebhook{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"secret","reason":"secret must be at least 16 characters in length","rule":"gte","param":"16"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "CreateWebhookAction generic error",
+			rawPayload:         `{"url": "https://example.com/hooks", "events": ["task.created"]}`,
+			ucMock:             mockCreateWebhook{err: errors.New("error")},
+			expectedBody:       `{"type":"about:blank","title":"Internal Server Error","status":500}`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.rawPayload))
+
+			var (
+				w      = httptest.NewRecorder()
+				action = NewCreateWebhookAction(tt.ucMock, log.LoggerMock{}, validator)
+			)
+
+			action.Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf(
+					"[TestCase '%s'] Result: '%v' | Expected: '%v'",
+					tt.name,
+					w.Code,
+					tt.expectedStatusCode,
+				)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if result != tt.expectedBody {
+				t.Errorf(
+					"[TestCase '%s'] Result: '%v' | Expected: '%v'",
+					tt.name,
+					result,
+					tt.expectedBody,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package action
+
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type DeleteWebhookAction struct {
+	uc  usecase.DeleteWebhookUseCase
+	log logger.Logger
+}
+
+func NewDeleteWebhookAction(uc usecase.DeleteWebhookUseCase, log logger.Logger) DeleteWebhookAction {
+	return DeleteWebhookAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a DeleteWebhookAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "delete_webhook"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	webhookID, err := parseWebhookID(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid parameter")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	if err := a.uc.Execute(r.Context(), webhookID); err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when deleting webhook")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusNoContent).Log("success deleting webhook")
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,72 @@
+package action
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type EnableWebhookAction struct {
+	uc  usecase.EnableWebhookUseCase
+	log logger.Logger
+}
+
+func NewEnableWebhookAction(uc usecase.EnableWebhookUseCase, log logger.Logger) EnableWebhookAction {
+	return EnableWebhookAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a EnableWebhookAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "enable_webhook"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	webhookID, err := parseWebhookID(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid parameter")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), webhookID)
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when enabling webhook")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success enabling webhook")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+// ルーターがクエリに設定した購読のIDを読み取る
+func parseWebhookID(r *http.Request) (domain.WebhookID, error) {
+	id, err := strconv.ParseUint(r.URL.Query().Get("webhook_id"), 10, 64)
+	if err != nil {
+		return 0, response.ErrParameterInvalid
+	}
+
+	return domain.WebhookID(id), nil
+}
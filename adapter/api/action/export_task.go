@@ -0,0 +1,124 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/taskfile"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+// タスクを書き出すごとに延長する書き込み期限
+const exportWriteTimeout = 30 * time.Second
+
+type ExportTaskAction struct {
+	uc  usecase.ExportTaskUseCase
+	log logger.Logger
+}
+
+func NewExportTaskAction(uc usecase.ExportTaskUseCase, log logger.Logger) ExportTaskAction {
+	return ExportTaskAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+// タスクを format クエリパラメータの形式 (csv, json, ndjson) で書き出す
+// 全件をメモリに読み込まず、読み取った順にレスポンスへ書き出す
+func (a ExportTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "export_task"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	var format = taskfile.FormatJSON
+	if v := r.URL.Query().Get("format"); v != "" {
+		f, err := taskfile.ParseFormat(v)
+		if err != nil {
+			var err = response.ErrParameterInvalid
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("invalid parameter")
+
+			response.NewError(err, http.StatusBadRequest).Send(w)
+			return
+		}
+		format = f
+	}
+
+	enc, err := taskfile.NewEncoder(w, format)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log("error when creating encoder")
+
+		response.NewError(err, http.StatusInternalServerError).Send(w)
+		return
+	}
+
+	// 最初のタスクを書き出すまではエラーレスポンスを返却できる
+	var started bool
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format.Extension()))
+		w.WriteHeader(http.StatusOK)
+	}
+
+	var rc = response.Controller(w, r)
+
+	var count int
+	err = a.uc.Execute(r.Context(), func(task usecase.UpdateTaskOutput) error {
+		// http.Server の WriteTimeout はレスポンス全体に適用されるため、タスクが多い場合も途中で切断されないよう期限を延長する
+		if err := rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+
+		start()
+		count++
+		return enc.Encode(task)
+	})
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when exporting tasks")
+
+		// 書き出し途中の場合はステータスを変更できないため、レスポンスを途中で終える
+		if !started {
+			response.NewError(err, status).Send(w)
+		}
+		return
+	}
+
+	start()
+	if err := enc.Close(); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusOK,
+		).Log("error when finishing export")
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log(fmt.Sprintf("success exporting %d tasks", count))
+}
@@ -0,0 +1,156 @@
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type mockExportTask struct {
+	result []usecase.UpdateTaskOutput
+	err    error
+}
+
+func (m mockExportTask) Execute(_ context.Context, fn func(usecase.UpdateTaskOutput) error) error {
+	for _, task := range m.result {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return m.err
+}
+
+func TestExportTaskAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	var tasks = []usecase.UpdateTaskOutput{
+		{ID: 1, Title: "Task_1", Tags: []string{"work"}, CreatedAt: "2024-01-01T00:00:00Z", UpdatedAt: "2024-01-01T00:00:00Z"},
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		ucMock              usecase.ExportTaskUseCase
+		expectedBody        string
+		expectedContentType string
+		expectedStatusCode  int
+	}{
+		// 正常値
+		{
+			name:                "ExportTaskAction csv",
+			query:               "?format=csv",
+			ucMock:              mockExportTask{result: tasks},
+			expectedBody:        "id,title,due_date,tags,completed,priority,recurrence,contexts,extensions,created_at,updated_at\n1,Task_1,,work,false,0,,,,2024-01-01T00:00:00Z,2024-01-01T00:00:00Z",
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "ExportTaskAction default json",
+			ucMock:              mockExportTask{result: tasks},
+			expectedBody:        `[{"id":1,"title":"Task_1","tags":["work"],"completed":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}]`,
+			expectedContentType: "application/json",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "ExportTaskAction empty ndjson",
+			query:               "?format=ndjson",
+			ucMock:              mockExportTask{},
+			expectedBody:        "",
+			expectedContentType: "application/x-ndjson",
+			expectedStatusCode:  http.StatusOK,
+		},
+
+		// 異常値
+		{
+			name:                "ExportTaskAction invalid format",
+			query:               "?format=xml",
+			ucMock:              mockExportTask{},
+			expectedBody:        `{"type":"about:blank","title":"Bad Request","status":400,"detail":"parameter invalid"}`,
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name:                "ExportTaskAction error before first row",
+			query:               "?format=csv",
+			ucMock:              mockExportTask{err: domain.NewError(domain.KindUnavailable, "query canceled")},
+			expectedBody:        `{"type":"/problems/unavailable","title":"Service Unavailable","status":503}`,
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/tasks/export"+tt.query, nil)
+
+			var (
+				w      = httptest.NewRecorder()
+				action = NewExportTaskAction(tt.ucMock, log.LoggerMock{})
+			)
+
+			action.Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Status: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != tt.expectedContentType {
+				t.Errorf("[TestCase '%s'] Content-Type: '%v' | Expected: '%v'", tt.name, ct, tt.expectedContentType)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if result != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expectedBody)
+			}
+		})
+	}
+}
+
+// 書き込み期限を記録する ResponseWriter (http.ResponseController から呼び出される)
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines []time.Time
+}
+
+func (d *deadlineRecorder) SetWriteDeadline(deadline time.Time) error {
+	d.deadlines = append(d.deadlines, deadline)
+	return nil
+}
+
+// タスクを書き出すごとに書き込み期限を延長する
+func TestExportTaskAction_Execute_WriteDeadline(t *testing.T) {
+	t.Parallel()
+
+	var tasks = []usecase.UpdateTaskOutput{
+		{ID: 1, Title: "Task_1", Tags: []string{}},
+		{ID: 2, Title: "Task_2", Tags: []string{}},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/export?format=ndjson", nil)
+
+	var (
+		w      = &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+		action = NewExportTaskAction(mockExportTask{result: tasks}, log.LoggerMock{})
+		before = time.Now()
+	)
+
+	action.Execute(w, req)
+
+	if len(w.deadlines) != len(tasks) {
+		t.Fatalf("Result: '%v' | Expected: '%v'", len(w.deadlines), len(tasks))
+	}
+
+	for _, deadline := range w.deadlines {
+		if deadline.Before(before.Add(exportWriteTimeout)) {
+			t.Errorf("Result: '%v' | Expected: after '%v'", deadline, before.Add(exportWriteTimeout))
+		}
+	}
+}
@@ -24,16 +24,20 @@ func NewFindAllTaskAction(uc usecase.FindAllTaskUseCase, log logger.Logger) Find
 func (a FindAllTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
 	const logKey = "find_all_task"
 
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
 	output, err := a.uc.Execute(r.Context())
 	if err != nil {
+		var status = response.StatusCode(err)
 		logging.NewError(
 			a.log,
 			err,
 			logKey,
-			http.StatusInternalServerError,
+			status,
 		).Log("error when returning task list")
 
-		response.NewError(err, http.StatusInternalServerError).Send(w)
+		response.NewError(err, status).Send(w)
 		return
 	}
 	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success when returning task list")
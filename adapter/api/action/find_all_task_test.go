@@ -37,11 +37,12 @@ func TestFindAllTaskAction_Execute(t *testing.T) {
 					{
 						ID:    1,
 						Title: "Task_1",
+						Tags:  []string{},
 					},
 				},
 				err: nil,
 			},
-			expectedBody:       `[{"id":1,"title":"Task_1"}]`,
+			expectedBody:       `[{"id":1,"title":"Task_1","tags":[],"completed":false}]`,
 			expectedStatusCode: http.StatusOK,
 		},
 		{
@@ -58,7 +59,7 @@ func TestFindAllTaskAction_Execute(t *testing.T) {
 			ucMock: mockFindAllTask{
 				err: errors.New("error"),
 			},
-			expectedBody:       `{"errors":["error"]}`,
+			expectedBody:       `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 			expectedStatusCode: http.StatusInternalServerError,
 		},
 	}
@@ -0,0 +1,47 @@
+package action
+
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type FindAllWebhookAction struct {
+	uc  usecase.FindAllWebhookUseCase
+	log logger.Logger
+}
+
+func NewFindAllWebhookAction(uc usecase.FindAllWebhookUseCase, log logger.Logger) FindAllWebhookAction {
+	return FindAllWebhookAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a FindAllWebhookAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "find_all_webhook"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	output, err := a.uc.Execute(r.Context())
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when returning webhook list")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success when returning webhook list")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
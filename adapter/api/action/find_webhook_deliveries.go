@@ -0,0 +1,85 @@
+package action
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+const (
+	// 配信の一覧で返却する件数の既定値と上限
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+type FindWebhookDeliveriesAction struct {
+	uc  usecase.FindWebhookDeliveriesUseCase
+	log logger.Logger
+}
+
+func NewFindWebhookDeliveriesAction(uc usecase.FindWebhookDeliveriesUseCase, log logger.Logger) FindWebhookDeliveriesAction {
+	return FindWebhookDeliveriesAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a FindWebhookDeliveriesAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "find_webhook_deliveries"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	webhookID, err := parseWebhookID(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid parameter")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	var limit = defaultDeliveriesLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxDeliveriesLimit {
+			var err = response.ErrParameterInvalid
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("invalid parameter")
+
+			response.NewError(err, http.StatusBadRequest).Send(w)
+			return
+		}
+		limit = n
+	}
+
+	output, err := a.uc.Execute(r.Context(), webhookID, limit)
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when returning webhook deliveries")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success when returning webhook deliveries")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
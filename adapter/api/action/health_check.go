@@ -1,7 +1,53 @@
 package action
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/health"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+)
 
 func HealthCheck(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
+
+// プロセスが応答できることだけを返却する (依存先は確認しない)
+func Liveness(w http.ResponseWriter, _ *http.Request) {
+	response.NewSuccess(health.Report{Status: health.StatusPass, Checks: []health.CheckResult{}}, http.StatusOK).Send(w)
+}
+
+type ReadinessAction struct {
+	registry *health.Registry
+	log      logger.Logger
+}
+
+func NewReadinessAction(registry *health.Registry, log logger.Logger) ReadinessAction {
+	return ReadinessAction{
+		registry: registry,
+		log:      log,
+	}
+}
+
+// 登録された確認をすべて実行し、結果を返却する
+// 重要な確認が1つでも失敗した場合は 503 を返却する
+func (a ReadinessAction) Execute(w http.ResponseWriter, r *http.Request) {
+	var (
+		report = a.registry.Run(r.Context())
+		status = http.StatusOK
+	)
+	if report.Status == health.StatusFail {
+		status = http.StatusServiceUnavailable
+	}
+
+	for _, res := range report.Checks {
+		if res.Status != health.StatusPass {
+			a.log.WithFields(logger.Fields{
+				"check":    res.Name,
+				"critical": res.Critical,
+			}).Warnf("health check failed: %s", res.Error)
+		}
+	}
+
+	response.NewSuccess(report, status).Send(w)
+}
@@ -1,9 +1,15 @@
 package action
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/doglapping707/todo-api-go/adapter/health"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
 )
 
 func TestHealthCheck(t *testing.T) {
@@ -29,3 +35,81 @@ func TestHealthCheck(t *testing.T) {
 		)
 	}
 }
+
+func TestReadinessAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pass    = func(context.Context) error { return nil }
+		failure = func(context.Context) error { return errors.New("connection refused") }
+	)
+
+	tests := []struct {
+		name               string
+		checks             []health.Check
+		expectedStatus     string
+		expectedStatusCode int
+	}{
+		{
+			name: "All checks pass",
+			checks: []health.Check{
+				{Name: "database", Critical: true, Probe: pass},
+				{Name: "disk_space", Probe: pass},
+			},
+			expectedStatus:     health.StatusPass,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "Non critical check fails",
+			checks: []health.Check{
+				{Name: "database", Critical: true, Probe: pass},
+				{Name: "disk_space", Probe: failure},
+			},
+			expectedStatus:     health.StatusWarn,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "Critical check fails",
+			checks: []health.Check{
+				{Name: "database", Critical: true, Probe: failure},
+				{Name: "disk_space", Probe: pass},
+			},
+			expectedStatus:     health.StatusFail,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var registry = health.NewRegistry()
+			for _, c := range tt.checks {
+				registry.Register(c)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+
+			var rr = httptest.NewRecorder()
+			NewReadinessAction(registry, log.LoggerMock{}).Execute(rr, req)
+
+			if rr.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, rr.Code, tt.expectedStatusCode)
+			}
+
+			var report health.Report
+			if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+
+			if report.Status != tt.expectedStatus {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, report.Status, tt.expectedStatus)
+			}
+
+			if len(report.Checks) != len(tt.checks) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, len(report.Checks), len(tt.checks))
+			}
+		})
+	}
+}
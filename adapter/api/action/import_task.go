@@ -0,0 +1,229 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/taskfile"
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/usecase"
+	"github.com/pkg/errors"
+)
+
+const (
+	// 1回のINSERTで作成するタスクの件数
+	importBatchSize = 100
+
+	// アップロードできるファイルの最大サイズ
+	MaxImportSize = 10 << 20
+)
+
+type ImportTaskAction struct {
+	uc        usecase.ImportTaskUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewImportTaskAction(uc usecase.ImportTaskUseCase, log logger.Logger, v validator.Validator) ImportTaskAction {
+	return ImportTaskAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// 取り込みの結果
+type importTaskResponse struct {
+	Imported int               `json:"imported"`
+	Failed   int               `json:"failed"`
+	Errors   []importLineError `json:"errors"`
+}
+
+// 取り込めなかった行
+type importLineError struct {
+	Line  int             `json:"line"`
+	Error *response.Error `json:"error"`
+}
+
+// 取り込む1行
+type importLine struct {
+	line int
+	task usecase.UpdateTaskInput
+}
+
+// アップロードされたファイルのタスクを作成する
+// ファイルはリクエストボディ (Content-Type で形式を指定) か、multipart/form-data の file フィールドで受け取る
+// 不正な行は作成せずに行番号とエラーを返却し、残りの行を作成する
+func (t ImportTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "import_task"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxImportSize)
+	defer r.Body.Close()
+
+	file, format, err := importFile(r)
+	if err != nil {
+		var status = http.StatusBadRequest
+		if errors.Is(err, taskfile.ErrUnsupportedFormat) {
+			err, status = response.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType
+		}
+
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			status,
+		).Log("error when reading uploaded file")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	var result = importTaskResponse{Errors: []importLineError{}}
+
+	// ファイル全体を読み取れることを確認してから作成する
+	lines, err := t.decode(r.Context(), w, file, format, &result)
+	if err != nil {
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when parsing uploaded file")
+
+		response.NewError(fmt.Errorf("%w: %v", response.ErrInvalidInput, err), http.StatusBadRequest).Send(w)
+		return
+	}
+
+	for start := 0; start < len(lines); start += importBatchSize {
+		var batch = lines[start:min(start+importBatchSize, len(lines))]
+
+		var inputs = make([]usecase.UpdateTaskInput, 0, len(batch))
+		for _, l := range batch {
+			inputs = append(inputs, l.task)
+		}
+
+		ids, err := t.uc.Execute(r.Context(), inputs)
+		if err != nil {
+			var status = response.StatusCode(err)
+			logging.NewError(
+				t.log,
+				err,
+				logKey,
+				status,
+			).Log("error when importing tasks")
+
+			for _, l := range batch {
+				t.fail(w, &result, l.line, response.NewError(err, status))
+			}
+			continue
+		}
+
+		result.Imported += len(ids)
+	}
+
+	logging.NewInfo(t.log, logKey, http.StatusOK).Log(
+		fmt.Sprintf("imported %d tasks, %d failed", result.Imported, result.Failed),
+	)
+
+	response.NewSuccess(result, http.StatusOK).Send(w)
+}
+
+// ファイルを読み取り、検証に成功した行を返却する
+// 検証に失敗した行は result に記録する
+func (t ImportTaskAction) decode(
+	ctx context.Context,
+	w http.ResponseWriter,
+	file io.Reader,
+	format taskfile.Format,
+	result *importTaskResponse,
+) ([]importLine, error) {
+	dec, err := taskfile.NewDecoder(file, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []importLine
+	for {
+		record, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if record.Err != nil {
+			t.fail(w, result, record.Line, response.NewError(
+				fmt.Errorf("%w: %v", response.ErrInvalidInput, record.Err),
+				http.StatusBadRequest,
+			))
+			continue
+		}
+
+		// 取り込んだ行はタスクを作成するため、作成時のルール (期日が過去でないことなど) で検証する
+		if violations := t.validator.Validate(ctx, usecase.CreateTaskInput(record.Task)); len(violations) > 0 {
+			t.fail(w, result, record.Line, response.NewValidationError(violations, http.StatusBadRequest))
+			continue
+		}
+
+		lines = append(lines, importLine{line: record.Line, task: record.Task})
+	}
+}
+
+func (t ImportTaskAction) fail(w http.ResponseWriter, result *importTaskResponse, line int, err *response.Error) {
+	var problem = err.Embed(w)
+
+	result.Failed++
+	result.Errors = append(result.Errors, importLineError{Line: line, Error: &problem})
+}
+
+// リクエストからファイルとその形式を取り出す
+func importFile(r *http.Request) (io.Reader, taskfile.Format, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, "", taskfile.ErrUnsupportedFormat
+	}
+
+	if mediaType != "multipart/form-data" {
+		format, err := taskfile.FormatFromContentType(mediaType)
+		return r.Body, format, err
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, "", errMissingImportFile
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		if part.FormName() != "file" {
+			continue
+		}
+
+		// ファイルの Content-Type が汎用の場合は拡張子で判定する
+		format, err := taskfile.FormatFromContentType(part.Header.Get("Content-Type"))
+		if err != nil {
+			format, err = taskfile.FormatFromFilename(part.FileName())
+		}
+
+		return part, format, err
+	}
+}
+
+var errMissingImportFile = errors.New("multipart form must contain a file field")
@@ -0,0 +1,145 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/infrastructure/validation"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type mockImportTask struct {
+	err error
+}
+
+func (m mockImportTask) Execute(_ context.Context, inputs []usecase.UpdateTaskInput) ([]domain.TaskID, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var ids = make([]domain.TaskID, len(inputs))
+	for i := range inputs {
+		ids[i] = domain.TaskID(i + 1)
+	}
+
+	return ids, nil
+}
+
+func TestImportTaskAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	// multipart/form-data のリクエストボディを作成する
+	var multipartBody = func(filename, content string) (string, []byte) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		fw, _ := mw.CreateFormFile("file", filename)
+		_, _ = fw.Write([]byte(content))
+		_ = mw.Close()
+		return mw.FormDataContentType(), buf.Bytes()
+	}
+
+	var (
+		csvContentType, csvBody = multipartBody("tasks.csv", "title,tags\nTask_1,work\n,\nTask_3,Bad Tag\n")
+	)
+
+	tests := []struct {
+		name               string
+		contentType        string
+		rawPayload         []byte
+		ucMock             usecase.ImportTaskUseCase
+		expectedBody       string
+		expectedStatusCode int
+	}{
+		// 正常値
+		{
+			name:               "ImportTaskAction multipart csv with invalid rows",
+			contentType:        csvContentType,
+			rawPayload:         csvBody,
+			ucMock:             mockImportTask{},
+			expectedBody:       `{"imported":1,"failed":2,"errors":[{"line":3,"error":{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title is a required field","rule":"required"}]}},{"line":4,"error":{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"tags[0]","reason":"tags[0] must contain only lowercase letters, numbers and hyphens","rule":"slug"}]}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "ImportTaskAction ndjson with past due date",
+			contentType:        "application/x-ndjson",
+			rawPayload:         []byte("{\"title\":\"Task_1\",\"due_date\":\"2000-01-01T00:00:00Z\"}\n{\"title\":\"Task_2\",\"due_date\":\"2000-01-01T00:00:00Z\",\"completed\":true}\n"),
+			ucMock:             mockImportTask{},
+			expectedBody:       `{"imported":1,"failed":1,"errors":[{"line":1,"error":{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"due_date","reason":"due_date must not be in the past unless the task is completed","rule":"notpast"}]}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "ImportTaskAction todo.txt with CamelCase projects",
+			contentType:        "text/plain",
+			rawPayload:         []byte("Task_1 +WorkProject @HomeOffice\nTask_2 +Bad_Project\n"),
+			ucMock:             mockImportTask{},
+			expectedBody:       `{"imported":1,"failed":1,"errors":[{"line":2,"error":{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"tags[0]","reason":"tags[0] must contain only lowercase letters, numbers and hyphens","rule":"slug"}]}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "ImportTaskAction ndjson with malformed line",
+			contentType:        "application/x-ndjson",
+			rawPayload:         []byte("{\"title\":\"Task_1\"}\n{\"title\":1}\n"),
+			ucMock:             mockImportTask{},
+			expectedBody:       `{"imported":1,"failed":1,"errors":[{"line":2,"error":{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input: json: cannot unmarshal number into Go struct field UpdateTaskInput.title of type string"}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "ImportTaskAction insert error",
+			contentType:        "application/json",
+			rawPayload:         []byte(`[{"title":"Task_1"}]`),
+			ucMock:             mockImportTask{err: domain.NewError(domain.KindConflict, "task already exists")},
+			expectedBody:       `{"imported":0,"failed":1,"errors":[{"line":1,"error":{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"task already exists"}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+
+		// 異常値
+		{
+			name:               "ImportTaskAction unsupported media type",
+			contentType:        "application/xml",
+			rawPayload:         []byte(`<tasks/>`),
+			ucMock:             mockImportTask{},
+			expectedBody:       `{"type":"about:blank","title":"Unsupported Media Type","status":415,"detail":"unsupported media type"}`,
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:               "ImportTaskAction malformed file",
+			contentType:        "application/json",
+			rawPayload:         []byte(`{"title":"Task_1"}`),
+			ucMock:             mockImportTask{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input: json file must contain an array of tasks"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/tasks/import", bytes.NewReader(tt.rawPayload))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			var (
+				w      = httptest.NewRecorder()
+				action = NewImportTaskAction(tt.ucMock, log.LoggerMock{}, validator)
+			)
+
+			action.Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Status: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if result != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package action
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type IssueCalendarTokenAction struct {
+	uc  usecase.IssueCalendarTokenUseCase
+	log logger.Logger
+}
+
+func NewIssueCalendarTokenAction(uc usecase.IssueCalendarTokenUseCase, log logger.Logger) IssueCalendarTokenAction {
+	return IssueCalendarTokenAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+// フィードトークンと、カレンダーアプリに登録するURLのパス
+type issueCalendarTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+func (a IssueCalendarTokenAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "issue_calendar_token"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	output, err := a.uc.Execute(r.Context())
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when issuing calendar token")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success issuing calendar token")
+
+	response.NewSuccess(issueCalendarTokenResponse{
+		Token: output.Token,
+		URL:   "/v1/calendar.ics?token=" + url.QueryEscape(output.Token),
+	}, http.StatusCreated).Send(w)
+}
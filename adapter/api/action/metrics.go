@@ -0,0 +1,31 @@
+package action
+
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/metrics"
+)
+
+type MetricsAction struct {
+	registry *metrics.Registry
+	log      logger.Logger
+}
+
+func NewMetricsAction(registry *metrics.Registry, log logger.Logger) MetricsAction {
+	return MetricsAction{
+		registry: registry,
+		log:      log,
+	}
+}
+
+// Prometheus のテキスト形式でメトリクスを返却する
+// 一部のメトリクスを読み取れなかった場合も、読み取れたメトリクスを返却する
+func (a MetricsAction) Execute(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.WriteHeader(http.StatusOK)
+
+	if err := a.registry.Write(r.Context(), w); err != nil {
+		a.log.WithError(err).Warnf("error collecting metrics")
+	}
+}
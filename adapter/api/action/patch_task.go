@@ -0,0 +1,197 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RFC 7396
+	mediaTypeMergePatch = "application/merge-patch+json"
+	// RFC 6902
+	mediaTypeJSONPatch = "application/json-patch+json"
+)
+
+type PatchTaskAction struct {
+	uc        usecase.PatchTaskUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewPatchTaskAction(uc usecase.PatchTaskUseCase, log logger.Logger, v validator.Validator) PatchTaskAction {
+	return PatchTaskAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (t PatchTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "patch_task"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	var taskID, err = strconv.ParseUint(r.URL.Query().Get("task_id"), 10, 64)
+	if err != nil {
+		var err = response.ErrParameterInvalid
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid parameter")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when reading body")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	apply, err := newPatchApplier(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		var status = http.StatusBadRequest
+		if errors.Is(err, response.ErrUnsupportedMediaType) {
+			status = http.StatusUnsupportedMediaType
+		}
+
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			status,
+		).Log("error when decoding patch")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	var violations []validator.Violation
+	output, err := t.uc.Execute(r.Context(), domain.TaskID(taskID), func(current usecase.UpdateTaskInput) (usecase.UpdateTaskInput, error) {
+		var input usecase.UpdateTaskInput
+
+		doc, err := json.Marshal(current)
+		if err != nil {
+			return input, err
+		}
+
+		patched, err := apply(doc)
+		if err != nil {
+			return input, fmt.Errorf("%w: %v", response.ErrPatchNotApplicable, err)
+		}
+
+		var dec = json.NewDecoder(bytes.NewReader(patched))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&input); err != nil {
+			return input, fmt.Errorf("%w: %v", response.ErrInvalidInput, err)
+		}
+
+		if violations = t.validateInput(r.Context(), input); len(violations) > 0 {
+			return input, response.ErrInvalidInput
+		}
+
+		return input, nil
+	})
+
+	switch {
+	case err == nil:
+	case errors.Is(err, response.ErrInvalidInput):
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		if len(violations) > 0 {
+			response.NewValidationError(violations, http.StatusBadRequest).Send(w)
+		} else {
+			response.NewError(err, http.StatusBadRequest).Send(w)
+		}
+		return
+	case errors.Is(err, response.ErrPatchNotApplicable):
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			http.StatusConflict,
+		).Log("error when applying patch")
+
+		response.NewError(err, http.StatusConflict).Send(w)
+		return
+	default:
+		var status = response.StatusCode(err)
+		logging.NewError(
+			t.log,
+			err,
+			logKey,
+			status,
+		).Log("error when patching a task")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	logging.NewInfo(t.log, logKey, http.StatusOK).Log("success patching task")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (t PatchTaskAction) validateInput(ctx context.Context, input usecase.UpdateTaskInput) []validator.Violation {
+	return t.validator.Validate(ctx, input)
+}
+
+// Content-Typeに応じて、JSONドキュメントにパッチを適用する関数を返却する
+func newPatchApplier(contentType string, body []byte) (func([]byte) ([]byte, error), error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, response.ErrUnsupportedMediaType
+	}
+
+	switch mediaType {
+	case mediaTypeMergePatch:
+		if !json.Valid(body) {
+			return nil, errors.New("invalid merge patch document")
+		}
+
+		return func(doc []byte) ([]byte, error) {
+			return jsonpatch.MergePatch(doc, body)
+		}, nil
+	case mediaTypeJSONPatch:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid json patch document")
+		}
+
+		return patch.Apply, nil
+	default:
+		return nil, response.ErrUnsupportedMediaType
+	}
+}
@@ -0,0 +1,257 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/infrastructure/validation"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type mockPatchTask struct {
+	current usecase.UpdateTaskInput
+	err     error
+}
+
+func (m mockPatchTask) Execute(_ context.Context, taskID domain.TaskID, patch usecase.PatchTaskFunc) (usecase.UpdateTaskOutput, error) {
+	input, err := patch(m.current)
+	if err != nil {
+		return usecase.UpdateTaskOutput{}, err
+	}
+
+	if m.err != nil {
+		return usecase.UpdateTaskOutput{}, m.err
+	}
+
+	var dueDate string
+	if input.DueDate != nil {
+		dueDate = input.DueDate.Format(time.RFC3339)
+	}
+
+	return usecase.UpdateTaskOutput{
+		ID:        taskID,
+		Title:     input.Title,
+		DueDate:   dueDate,
+		Tags:      input.Tags,
+		Completed: input.Completed,
+		CreatedAt: time.Time{}.Format(time.RFC3339),
+		UpdatedAt: time.Time{}.Format(time.RFC3339),
+	}, nil
+}
+
+func TestPatchTaskAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	type args struct {
+		taskID      string
+		contentType string
+		rawPayload  []byte
+	}
+
+	tests := []struct {
+		name               string
+		args               args
+		ucMock             usecase.PatchTaskUseCase
+		expectedBody       string
+		expectedStatusCode int
+	}{
+		{
+			name: "PatchTaskAction merge patch success",
+			args: args{
+				taskID:      "1",
+				contentType: "application/merge-patch+json",
+				rawPayload:  []byte(`{"title": "Task_2"}`),
+			},
+			ucMock: mockPatchTask{
+				current: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{}},
+			},
+			expectedBody:       `{"id":1,"title":"Task_2","tags":[],"completed":false,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "PatchTaskAction json patch success",
+			args: args{
+				taskID:      "1",
+				contentType: "application/json-patch+json; charset=utf-8",
+				rawPayload:  []byte(`[{"op": "test", "path": "/title", "value": "Task_1"}, {"op": "replace", "path": "/title", "value": "Task_2"}]`),
+			},
+			ucMock: mockPatchTask{
+				current: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{}},
+			},
+			expectedBody:       `{"id":1,"title":"Task_2","tags":[],"completed":false,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "PatchTaskAction json patch add tag and due date",
+			args: args{
+				taskID:      "1",
+				contentType: "application/json-patch+json",
+				rawPayload:  []byte(`[{"op": "add", "path": "/tags/-", "value": "work"}, {"op": "replace", "path": "/due_date", "value": "2030-01-02T15:04:05Z"}]`),
+			},
+			ucMock: mockPatchTask{
+				current: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{}},
+			},
+			expectedBody:       `{"id":1,"title":"Task_1","due_date":"2030-01-02T15:04:05Z","tags":["work"],"completed":false,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "PatchTaskAction merge patch invalid tag",
+			args: args{
+				taskID:      "1",
+				contentType: "application/merge-patch+json",
+				rawPayload:  []byte(`{"tags": ["Not A Slug"]}`),
+			},
+			ucMock: mockPatchTask{
+				current: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{}},
+			},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"tags[0]","reason":"tags[0] must contain only lowercase letters, numbers and hyphens","rule":"slug"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PatchTaskAction json patch test failed",
+			args: args{
+				taskID:      "1",
+				contentType: "application/json-patch+json",
+				rawPayload:  []byte(`[{"op": "test", "path": "/title", "value": "Task_9"}]`),
+			},
+			ucMock: mockPatchTask{
+				current: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{}},
+			},
+			expectedBody:       `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"patch could not be applied: testing value /title failed: test failed"}`,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name: "PatchTaskAction error invalid title",
+			args: args{
+				taskID:      "1",
+				contentType: "application/merge-patch+json",
+				rawPayload:  []byte(`{"title": null}`),
+			},
+			ucMock: mockPatchTask{
+				current: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{}},
+			},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title is a required field","rule":"required"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PatchTaskAction error unknown field",
+			args: args{
+				taskID:      "1",
+				contentType: "application/merge-patch+json",
+				rawPayload:  []byte(`{"title1234": "Task_2"}`),
+			},
+			ucMock: mockPatchTask{
+				current: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{}},
+			},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input: json: unknown field \"title1234\""}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PatchTaskAction error unsupported media type",
+			args: args{
+				taskID:      "1",
+				contentType: "application/json",
+				rawPayload:  []byte(`{"title": "Task_2"}`),
+			},
+			ucMock:             mockPatchTask{},
+			expectedBody:       `{"type":"about:blank","title":"Unsupported Media Type","status":415,"detail":"unsupported media type"}`,
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "PatchTaskAction error invalid JSON",
+			args: args{
+				taskID:      "1",
+				contentType: "application/merge-patch+json",
+				rawPayload:  []byte(`{"title":`),
+			},
+			ucMock:             mockPatchTask{},
+			expectedBody:       `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid merge patch document"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PatchTaskAction error invalid parameter",
+			args: args{
+				taskID:      "abc",
+				contentType: "application/merge-patch+json",
+				rawPayload:  []byte(`{"title": "Task_2"}`),
+			},
+			ucMock:             mockPatchTask{},
+			expectedBody:       `{"type":"about:blank","title":"Bad Request","status":400,"detail":"parameter invalid"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PatchTaskAction error task not found",
+			args: args{
+				taskID:      "1",
+				contentType: "application/merge-patch+json",
+				rawPayload:  []byte(`{"title": "Task_2"}`),
+			},
+			ucMock: mockPatchTask{
+				err: domain.ErrTaskNotFound,
+			},
+			expectedBody:       `{"type":"/problems/not-found","title":"Not Found","status":404,"detail":"task not found"}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "PatchTaskAction generic error",
+			args: args{
+				taskID:      "1",
+				contentType: "application/merge-patch+json",
+				rawPayload:  []byte(`{"title": "Task_2"}`),
+			},
+			ucMock: mockPatchTask{
+				current: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{}},
+				err:     errors.New("error"),
+			},
+			expectedBody:       `{"type":"about:blank","title":"Internal Server Error","status":500}`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodPatch,
+				"/tasks?task_id="+tt.args.taskID,
+				bytes.NewReader(tt.args.rawPayload),
+			)
+			req.Header.Set("Content-Type", tt.args.contentType)
+
+			var (
+				w      = httptest.NewRecorder()
+				action = NewPatchTaskAction(tt.ucMock, log.LoggerMock{}, validator)
+			)
+
+			action.Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf(
+					"[TestCase '%s'] O handler retornou um HTTP status code inesperado: retornado '%v' esperado '%v'",
+					tt.name,
+					w.Code,
+					tt.expectedStatusCode,
+				)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if !strings.EqualFold(result, tt.expectedBody) {
+				t.Errorf(
+					"[TestCase '%s'] Result: '%v' | Expected: '%v'",
+					tt.name,
+					result,
+					tt.expectedBody,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package action
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+const (
+	// 1回の同期で返却する変更の件数の既定値と上限
+	defaultSyncLimit = 500
+	maxSyncLimit     = 1000
+)
+
+type PullTaskChangesAction struct {
+	uc  usecase.PullTaskChangesUseCase
+	log logger.Logger
+}
+
+func NewPullTaskChangesAction(uc usecase.PullTaskChangesUseCase, log logger.Logger) PullTaskChangesAction {
+	return PullTaskChangesAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+// since を省略した場合は最初からすべての変更を返却する
+func (a PullTaskChangesAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "pull_task_changes"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	since, limit, err := parseSyncQuery(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid parameter")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), since, limit)
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when returning task changes")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success when returning task changes")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+// クエリパラメータから開始位置と件数を取得する
+func parseSyncQuery(r *http.Request) (domain.ChangeSeq, int, error) {
+	var (
+		query = r.URL.Query()
+		since domain.ChangeSeq
+		limit = defaultSyncLimit
+	)
+
+	if v := query.Get("since"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, 0, response.ErrParameterInvalid
+		}
+		since = domain.ChangeSeq(n)
+	}
+
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSyncLimit {
+			return 0, 0, response.ErrParameterInvalid
+		}
+		limit = n
+	}
+
+	return since, limit, nil
+}
@@ -0,0 +1,140 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type PushTaskChangesAction struct {
+	uc        usecase.PushTaskChangesUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewPushTaskChangesAction(uc usecase.PushTaskChangesUseCase, log logger.Logger, v validator.Validator) PushTaskChangesAction {
+	return PushTaskChangesAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// 同期のレスポンス
+type pushTaskChangesResponse struct {
+	Results []taskMutationResult `json:"results"`
+}
+
+// 変更ごとの結果
+type taskMutationResult struct {
+	Index     int                           `json:"index"`
+	Op        string                        `json:"op"`
+	Status    int                           `json:"status"`
+	ClientRef string                        `json:"client_ref,omitempty"`
+	TaskID    domain.TaskID                 `json:"task_id,omitempty"`
+	Task      *usecase.UpdateTaskOutput     `json:"task,omitempty"`
+	Conflicts []usecase.FieldConflictOutput `json:"conflicts,omitempty"`
+	Error     *response.Error               `json:"error,omitempty"`
+}
+
+// 変更を反映した後のタスクが検証で不合格だったことを表すエラー
+type taskViolations []validator.Violation
+
+func (v taskViolations) Error() string {
+	return response.ErrInvalidInput.Error()
+}
+
+// 個々の変更の失敗はレスポンス全体のステータスにせず、結果ごとのステータスとエラーで返却する
+func (a PushTaskChangesAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "push_task_changes"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	var input usecase.PushTaskChangesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	if errs := a.validateInput(r.Context(), input); len(errs) > 0 {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewValidationError(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input, func(task interface{}) error {
+		if errs := a.validator.Validate(r.Context(), task); len(errs) > 0 {
+			return taskViolations(errs)
+		}
+		return nil
+	})
+	if err != nil {
+		var status = response.StatusCode(err)
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			status,
+		).Log("error when pushing task changes")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	var results = make([]taskMutationResult, len(output.Results))
+	for i, res := range output.Results {
+		results[i] = taskMutationResult{
+			Index:     i,
+			Op:        res.Op,
+			ClientRef: res.ClientRef,
+			TaskID:    res.TaskID,
+			Task:      res.Task,
+			Conflicts: res.Conflicts,
+			Status:    successStatus(res.Op),
+		}
+
+		if res.Err == nil {
+			continue
+		}
+
+		var problem response.Error
+		if violations, ok := res.Err.(taskViolations); ok {
+			results[i].Status = http.StatusBadRequest
+			problem = response.NewValidationError(violations, http.StatusBadRequest).Embed(w)
+		} else {
+			results[i].Status = response.StatusCode(res.Err)
+			problem = response.NewError(res.Err, results[i].Status).Embed(w)
+		}
+		results[i].Error = &problem
+	}
+
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success pushing task changes")
+
+	response.NewSuccess(pushTaskChangesResponse{Results: results}, http.StatusOK).Send(w)
+}
+
+func (a PushTaskChangesAction) validateInput(ctx context.Context, input usecase.PushTaskChangesInput) []validator.Violation {
+	return a.validator.Validate(ctx, input)
+}
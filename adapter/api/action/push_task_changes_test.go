@@ -0,0 +1,149 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/infrastructure/validation"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+// 変更後のタスクを検証関数に渡し、その結果を返却する
+type mockPushTaskChanges struct {
+	task   interface{}
+	result usecase.PushTaskChangesOutput
+}
+
+func (m mockPushTaskChanges) Execute(
+	_ context.Context,
+	_ usecase.PushTaskChangesInput,
+	validate usecase.ValidateTaskFunc,
+) (usecase.PushTaskChangesOutput, error) {
+	var result = m.result
+	result.Results = append([]usecase.TaskMutationOutput(nil), m.result.Results...)
+	if err := validate(m.task); err != nil {
+		result.Results[0].Err = err
+		result.Results[0].Task = nil
+	}
+
+	return result, nil
+}
+
+func TestPushTaskChangesAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	var past = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var payload = []byte(`{
+		"since": "42",
+		"mutations": [
+			{"op": "create", "client_ref": "local-1", "updated_at": "2024-01-01T11:00:00Z", "fields": {"title": "Task_1"}},
+			{"op": "update", "task_id": 2, "updated_at": "2024-01-01T11:00:00Z", "fields": {"priority": 3}},
+			{"op": "delete", "task_id": 3, "updated_at": "2024-01-01T11:00:00Z"}
+		]
+	}`)
+
+	var results = []usecase.TaskMutationOutput{
+		{Op: "create", ClientRef: "local-1", TaskID: 1, Task: &usecase.UpdateTaskOutput{ID: 1, Title: "Task_1", Tags: []string{}}},
+		{
+			Op:     "update",
+			TaskID: 2,
+			Task:   &usecase.UpdateTaskOutput{ID: 2, Title: "Task_2", Tags: []string{}, Priority: 1},
+			Conflicts: []usecase.FieldConflictOutput{
+				{Field: "priority", Winner: usecase.SyncWinnerServer, ClientValue: json.RawMessage(`3`), ServerValue: json.RawMessage(`1`)},
+			},
+		},
+		{Op: "delete", TaskID: 3, Err: domain.ErrTaskModifiedAfterDelete, Task: &usecase.UpdateTaskOutput{ID: 3, Title: "Task_3", Tags: []string{}}},
+	}
+
+	tests := []struct {
+		name               string
+		rawPayload         []byte
+		ucMock             usecase.PushTaskChangesUseCase
+		expectedBody       string
+		expectedStatusCode int
+	}{
+		// 正常値
+		{
+			name:       "PushTaskChangesAction success",
+			rawPayload: payload,
+			ucMock: mockPushTaskChanges{
+				task:   usecase.UpdateTaskInput{Title: "Task_1"},
+				result: usecase.PushTaskChangesOutput{Results: results},
+			},
+			expectedBody:       `{"results":[{"index":0,"op":"create","status":201,"client_ref":"local-1","task_id":1,"task":{"id":1,"title":"Task_1","tags":[],"completed":false,"created_at":"","updated_at":""}},{"index":1,"op":"update","status":200,"task_id":2,"task":{"id":2,"title":"Task_2","tags":[],"completed":false,"priority":1,"created_at":"","updated_at":""},"conflicts":[{"field":"priority","winner":"server","client_value":3,"server_value":1}]},{"index":2,"op":"delete","status":409,"task_id":3,"task":{"id":3,"title":"Task_3","tags":[],"completed":false,"created_at":"","updated_at":""},"error":{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"task was modified after it was deleted"}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:       "PushTaskChangesAction invalid task",
+			rawPayload: payload,
+			ucMock: mockPushTaskChanges{
+				task:   usecase.UpdateTaskInput{Title: "Task_with_a_long_title"},
+				result: usecase.PushTaskChangesOutput{Results: results[:1]},
+			},
+			expectedBody:       `{"results":[{"index":0,"op":"create","status":400,"client_ref":"local-1","task_id":1,"error":{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title must be at maximum 15 characters in length","rule":"lte","param":"15"}]}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:       "PushTaskChangesAction create with past due date",
+			rawPayload: payload,
+			ucMock: mockPushTaskChanges{
+				task:   usecase.CreateTaskInput{Title: "Task_1", DueDate: &past},
+				result: usecase.PushTaskChangesOutput{Results: results[:1]},
+			},
+			expectedBody:       `{"results":[{"index":0,"op":"create","status":400,"client_ref":"local-1","task_id":1,"error":{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"due_date","reason":"due_date must not be in the past unless the task is completed","rule":"notpast"}]}}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+
+		// 異常値
+		{
+			name:               "PushTaskChangesAction error missing updated at",
+			rawPayload:         []byte(`{"mutations": [{"op": "update", "task_id": 2, "fields": {}}]}`),
+			ucMock:             mockPushTaskChanges{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"mutations[0].updated_at","reason":"updated_at is a required field","rule":"required"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "PushTaskChangesAction error invalid token",
+			rawPayload:         []byte(`{"since": "abc", "mutations": []}`),
+			ucMock:             mockPushTaskChanges{},
+			expectedBody:       `{"type":"about:blank","title":"Bad Request","status":400,"detail":"json: cannot unmarshal number abc into Go struct field PushTaskChangesInput.since of type domain.ChangeSeq"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := http.NewRequest(http.MethodPost, "/sync", bytes.NewReader(tt.rawPayload))
+
+			var (
+				w      = httptest.NewRecorder()
+				action = NewPushTaskChangesAction(tt.ucMock, log.LoggerMock{}, validator)
+			)
+
+			action.Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Status: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if result != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expectedBody)
+			}
+		})
+	}
+}
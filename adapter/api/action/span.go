@@ -0,0 +1,14 @@
+package action
+
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// アクションの区間を開始し、区間をセットしたリクエストを返却する
+// ユースケースやクエリの区間は、この区間の子として記録される
+func startSpan(r *http.Request, logKey string) (*http.Request, domain.Span) {
+	ctx, span := domain.StartSpan(r.Context(), "action."+logKey)
+	return r.WithContext(ctx), span
+}
@@ -0,0 +1,172 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+	"github.com/pkg/errors"
+)
+
+// 再接続したクライアントが最後に受け取ったイベントのIDを送るヘッダー
+const HeaderLastEventID = "Last-Event-ID"
+
+const (
+	// 接続を維持するためにコメントを送る間隔
+	defaultHeartbeatInterval = 15 * time.Second
+	// 書き込みごとに延長する書き込み期限 (ハートビートの間隔より長くする)
+	streamWriteTimeout = 2 * defaultHeartbeatInterval
+	// 切断されたクライアントが再接続するまでの時間 (ミリ秒)
+	streamRetryMillis = 3000
+)
+
+type StreamTaskEventAction struct {
+	uc        usecase.StreamTaskEventUseCase
+	log       logger.Logger
+	heartbeat time.Duration
+}
+
+func NewStreamTaskEventAction(uc usecase.StreamTaskEventUseCase, log logger.Logger) StreamTaskEventAction {
+	return StreamTaskEventAction{
+		uc:        uc,
+		log:       log,
+		heartbeat: defaultHeartbeatInterval,
+	}
+}
+
+// タスクの変更を Server-Sent Events で送り続ける
+// Last-Event-ID ヘッダーがあれば、そのIDより後のイベントから再開する
+func (a StreamTaskEventAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "stream_task_event"
+
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
+	var input usecase.StreamTaskEventInput
+	if v := r.Header.Get(HeaderLastEventID); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			var err = response.ErrParameterInvalid
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("invalid last event id")
+
+			response.NewError(err, http.StatusBadRequest).Send(w)
+			return
+		}
+
+		var lastEventID = domain.EventID(id)
+		input.LastEventID = &lastEventID
+	}
+
+	var rc = response.Controller(w, r)
+
+	// http.Server の WriteTimeout は接続全体に適用されるため、書き込みのたびに期限を延長する
+	write := func(message string) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+
+		if _, err := fmt.Fprint(w, message); err != nil {
+			return err
+		}
+
+		return rc.Flush()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// リバースプロキシでバッファリングさせない
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := write(fmt.Sprintf("retry: %d\n\n", streamRetryMillis)); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusOK,
+		).Log("error when starting task event stream")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// レスポンスへの書き込みはこのゴルーチンだけで行う
+	var (
+		events = make(chan usecase.TaskEventOutput)
+		done   = make(chan error, 1)
+	)
+	go func() {
+		done <- a.uc.Execute(ctx, input, func(event usecase.TaskEventOutput) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	var heartbeat = time.NewTicker(a.heartbeat)
+	defer heartbeat.Stop()
+
+	var count int
+	for {
+		var err error
+		select {
+		case event := <-events:
+			var message string
+			if message, err = formatEvent(event); err == nil {
+				err = write(message)
+				count++
+			}
+		case <-heartbeat.C:
+			err = write(": heartbeat\n\n")
+		case err := <-done:
+			// ヘッダーは送信済みのため、エラーの場合も接続を閉じてクライアントに再接続させる
+			if err != nil {
+				logging.NewError(
+					a.log,
+					err,
+					logKey,
+					http.StatusOK,
+				).Log("error when streaming task events")
+				return
+			}
+
+			logging.NewInfo(a.log, logKey, http.StatusOK).Log(fmt.Sprintf("task event stream closed after %d events", count))
+			return
+		}
+
+		if err != nil {
+			cancel()
+			<-done
+
+			logging.NewInfo(a.log, logKey, http.StatusOK).Log(fmt.Sprintf("task event stream disconnected after %d events: %v", count, err))
+			return
+		}
+	}
+}
+
+// イベントを text/event-stream の1件のメッセージにする
+func formatEvent(event usecase.TaskEventOutput) (string, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return "", errors.Wrap(err, "error encoding task event")
+	}
+
+	return fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, b), nil
+}
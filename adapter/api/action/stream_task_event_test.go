@@ -0,0 +1,106 @@
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type mockStreamTaskEvent struct {
+	result []usecase.TaskEventOutput
+	input  *usecase.StreamTaskEventInput
+}
+
+func (m mockStreamTaskEvent) Execute(_ context.Context, input usecase.StreamTaskEventInput, fn func(usecase.TaskEventOutput) error) error {
+	*m.input = input
+	for _, event := range m.result {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestStreamTaskEventAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	var events = []usecase.TaskEventOutput{
+		{ID: "7", Type: usecase.EventStreamReset, CreatedAt: "2024-01-10T09:00:00Z"},
+		{ID: "8", Type: domain.EventTaskDeleted, CreatedAt: "2024-01-10T09:00:01Z", Data: map[string]int{"id": 1}},
+	}
+
+	tests := []struct {
+		name                string
+		lastEventID         string
+		expectedLastEventID *domain.EventID
+		expectedBody        string
+		expectedStatusCode  int
+	}{
+		{
+			name:               "StreamTaskEventAction success",
+			expectedBody:       "retry: 3000\n\nid: 7\nevent: stream.reset\ndata: {\"id\":\"7\",\"type\":\"stream.reset\",\"created_at\":\"2024-01-10T09:00:00Z\",\"data\":null}\n\nid: 8\nevent: task.deleted\ndata: {\"id\":\"8\",\"type\":\"task.deleted\",\"created_at\":\"2024-01-10T09:00:01Z\",\"data\":{\"id\":1}}\n\n",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:                "StreamTaskEventAction resume",
+			lastEventID:         "6",
+			expectedLastEventID: func() *domain.EventID { var id domain.EventID = 6; return &id }(),
+			expectedBody:        "retry: 3000\n\nid: 7\nevent: stream.reset\ndata: {\"id\":\"7\",\"type\":\"stream.reset\",\"created_at\":\"2024-01-10T09:00:00Z\",\"data\":null}\n\nid: 8\nevent: task.deleted\ndata: {\"id\":\"8\",\"type\":\"task.deleted\",\"created_at\":\"2024-01-10T09:00:01Z\",\"data\":{\"id\":1}}\n\n",
+			expectedStatusCode:  http.StatusOK,
+		},
+		// 異常値
+		{
+			name:               "StreamTaskEventAction invalid last event id",
+			lastEventID:        "abc",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var input usecase.StreamTaskEventInput
+
+			req, _ := http.NewRequest(http.MethodGet, "/events", nil)
+			if tt.lastEventID != "" {
+				req.Header.Set(HeaderLastEventID, tt.lastEventID)
+			}
+
+			var (
+				w      = httptest.NewRecorder()
+				action = NewStreamTaskEventAction(mockStreamTaskEvent{result: events, input: &input}, log.LoggerMock{})
+			)
+
+			action.Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+
+			if tt.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			if w.Header().Get("Content-Type") != "text/event-stream" || !w.Flushed {
+				t.Errorf("[TestCase '%s'] Result: '%v' (flushed %v) | Expected: 'text/event-stream'", tt.name, w.Header().Get("Content-Type"), w.Flushed)
+			}
+
+			if w.Body.String() != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%q' | Expected: '%q'", tt.name, w.Body.String(), tt.expectedBody)
+			}
+
+			if (input.LastEventID == nil) != (tt.expectedLastEventID == nil) ||
+				(input.LastEventID != nil && *input.LastEventID != *tt.expectedLastEventID) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, input.LastEventID, tt.expectedLastEventID)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package action
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -30,6 +31,9 @@ func NewUpdateTaskAction(uc usecase.UpdateTaskUseCase, log logger.Logger, v vali
 func (t UpdateTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
 	var logKey = "update_task"
 
+	r, span := startSpan(r, logKey)
+	defer span.End()
+
 	var taskID, err = strconv.ParseUint(r.URL.Query().Get("task_id"), 10, 64)
 	if err != nil {
 		var err = response.ErrParameterInvalid
@@ -58,7 +62,7 @@ func (t UpdateTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	if errs := t.validateInput(input); len(errs) > 0 {
+	if errs := t.validateInput(r.Context(), input); len(errs) > 0 {
 		logging.NewError(
 			t.log,
 			response.ErrInvalidInput,
@@ -66,36 +70,29 @@ func (t UpdateTaskAction) Execute(w http.ResponseWriter, r *http.Request) {
 			http.StatusBadRequest,
 		).Log("invalid input")
 
-		response.NewErrorMessage(errs, http.StatusBadRequest).Send(w)
+		response.NewValidationError(errs, http.StatusBadRequest).Send(w)
 		return
 	}
 
-	if err := t.uc.Execute(r.Context(), input, domain.TaskID(taskID)); err != nil {
+	output, err := t.uc.Execute(r.Context(), input, domain.TaskID(taskID))
+	if err != nil {
+		var status = response.StatusCode(err)
 		logging.NewError(
 			t.log,
 			err,
 			logKey,
-			http.StatusInternalServerError,
+			status,
 		).Log("error when updating a new task")
 
-		response.NewError(err, http.StatusInternalServerError).Send(w)
+		response.NewError(err, status).Send(w)
 		return
 	}
 
-	logging.NewInfo(t.log, logKey, http.StatusNoContent).Log("success updating task")
+	logging.NewInfo(t.log, logKey, http.StatusOK).Log("success updating task")
 
-	response.NewSuccess(nil, http.StatusNoContent).Send(w)
+	response.NewSuccess(output, http.StatusOK).Send(w)
 }
 
-func (t UpdateTaskAction) validateInput(input usecase.UpdateTaskInput) []string {
-	var msgs []string
-
-	if err := t.validator.Validate(input); err != nil {
-		for _, msg := range t.validator.Messages() {
-			msg := msg
-			msgs = append(msgs, msg)
-		}
-	}
-
-	return msgs
+func (t UpdateTaskAction) validateInput(ctx context.Context, input usecase.UpdateTaskInput) []validator.Violation {
+	return t.validator.Validate(ctx, input)
 }
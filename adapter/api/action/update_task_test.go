@@ -0,0 +1,143 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+	"github.com/doglapping707/todo-api-go/infrastructure/validation"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type mockUpdateTask struct {
+	result usecase.UpdateTaskOutput
+	err    error
+}
+
+func (m mockUpdateTask) Execute(_ context.Context, _ usecase.UpdateTaskInput, _ domain.TaskID) (usecase.UpdateTaskOutput, error) {
+	return m.result, m.err
+}
+
+func TestUpdateTaskAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	type args struct {
+		taskID     string
+		rawPayload []byte
+	}
+
+	tests := []struct {
+		name               string
+		args               args
+		ucMock             usecase.UpdateTaskUseCase
+		expectedBody       string
+		expectedStatusCode int
+	}{
+		{
+			name: "UpdateTaskAction success",
+			args: args{
+				taskID:     "1",
+				rawPayload: []byte(`{"title": "Task_2"}`),
+			},
+			ucMock: mockUpdateTask{
+				result: usecase.UpdateTaskOutput{
+					ID:        1,
+					Title:     "Task_2",
+					Tags:      []string{},
+					CreatedAt: "2024-01-04T10:02:14Z",
+					UpdatedAt: "2024-01-05T10:02:14Z",
+				},
+			},
+			expectedBody:       `{"id":1,"title":"Task_2","tags":[],"completed":false,"created_at":"2024-01-04T10:02:14Z","updated_at":"2024-01-05T10:02:14Z"}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "UpdateTaskAction error task not found",
+			args: args{
+				taskID:     "99",
+				rawPayload: []byte(`{"title": "Task_2"}`),
+			},
+			ucMock: mockUpdateTask{
+				err: domain.ErrTaskNotFound,
+			},
+			expectedBody:       `{"type":"/problems/not-found","title":"Not Found","status":404,"detail":"task not found"}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "UpdateTaskAction generic error",
+			args: args{
+				taskID:     "1",
+				rawPayload: []byte(`{"title": "Task_2"}`),
+			},
+			ucMock: mockUpdateTask{
+				err: errors.New("error"),
+			},
+			expectedBody:       `{"type":"about:blank","title":"Internal Server Error","status":500}`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "UpdateTaskAction error invalid title",
+			args: args{
+				taskID:     "1",
+				rawPayload: []byte(`{"title": ""}`),
+			},
+			ucMock:             mockUpdateTask{},
+			expectedBody:       `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title is a required field","rule":"required"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "UpdateTaskAction error invalid parameter",
+			args: args{
+				taskID:     "abc",
+				rawPayload: []byte(`{"title": "Task_2"}`),
+			},
+			ucMock:             mockUpdateTask{},
+			expectedBody:       `{"type":"about:blank","title":"Bad Request","status":400,"detail":"parameter invalid"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodPut,
+				"/tasks?task_id="+tt.args.taskID,
+				bytes.NewReader(tt.args.rawPayload),
+			)
+
+			var (
+				w      = httptest.NewRecorder()
+				action = NewUpdateTaskAction(tt.ucMock, log.LoggerMock{}, validator)
+			)
+
+			action.Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf(
+					"[TestCase '%s'] O handler retornou um HTTP status code inesperado: retornado '%v' esperado '%v'",
+					tt.name,
+					w.Code,
+					tt.expectedStatusCode,
+				)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if !strings.EqualFold(result, tt.expectedBody) {
+				t.Errorf(
+					"[TestCase '%s'] Result: '%v' | Expected: '%v'",
+					tt.name,
+					result,
+					tt.expectedBody,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type loggingContextKey string
+
+const (
+	keyRequestID loggingContextKey = "RequestIDContextKey"
+	keyRoute     loggingContextKey = "RouteContextKey"
+)
+
+// コンテキストにリクエストIDをセットし返却する
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, keyRequestID, id)
+}
+
+// コンテキストにセットされたリクエストIDを返却する (セットされていない場合は空文字)
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(keyRequestID).(string)
+	return id
+}
+
+// コンテキストにリクエストが一致したルートをセットし返却する
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, keyRoute, route)
+}
+
+// リクエストID・ルート・アカウントIDのうち、コンテキストにセットされている項目を返却する
+// logger.RegisterContextFields に登録し、リクエストの処理中に出力するすべてのログに付ける
+func ContextFields(ctx context.Context) logger.Fields {
+	var fields = logger.Fields{}
+
+	if id := RequestIDFromContext(ctx); id != "" {
+		fields["request_id"] = id
+	}
+
+	if route, ok := ctx.Value(keyRoute).(string); ok {
+		fields["route"] = route
+	}
+
+	if accountID, ok := domain.AccountIDFromContext(ctx); ok {
+		fields["account_id"] = accountID
+	}
+
+	return fields
+}
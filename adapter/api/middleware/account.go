@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// リクエストを行ったアカウントを示すヘッダー
+// 認証を導入するまでは、このヘッダーでアカウントを識別する (未指定の場合は 0)
+const HeaderAccountID = "X-Account-ID"
+
+type Account struct {
+	log logger.Logger
+}
+
+func NewAccount(log logger.Logger) Account {
+	return Account{log: log}
+}
+
+// リクエストを行ったアカウントをコンテキストにセットする
+func (m Account) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	const logKey = "account_middleware"
+
+	m.log = m.log.WithContext(r.Context())
+
+	var accountID uint64
+	if raw := r.Header.Get(HeaderAccountID); raw != "" {
+		var err error
+		if accountID, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			logging.NewError(
+				m.log,
+				response.ErrAccountInvalid,
+				logKey,
+				http.StatusBadRequest,
+			).Log("invalid account header")
+
+			response.NewError(response.ErrAccountInvalid, http.StatusBadRequest).Send(w)
+			return
+		}
+	}
+
+	next.ServeHTTP(w, r.WithContext(domain.WithAccountID(r.Context(), domain.AccountID(accountID))))
+}
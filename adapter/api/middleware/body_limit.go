@@ -0,0 +1,19 @@
+package middleware
+
+import "net/http"
+
+type BodyLimit struct {
+	// 読み取れるリクエストボディの最大のバイト数
+	size int64
+}
+
+func NewBodyLimit(size int64) BodyLimit {
+	return BodyLimit{size: size}
+}
+
+// リクエストボディを size バイトまでしか読み取れないようにする
+// 超えた分を読み取ると *http.MaxBytesError を返却する
+func (m BodyLimit) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	r.Body = http.MaxBytesReader(w, r.Body, m.size)
+	next(w, r)
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLimit_Execute(t *testing.T) {
+	t.Parallel()
+
+	req, _ := http.NewRequest(http.MethodPost, "/v1/tasks/import", strings.NewReader("0123456789"))
+
+	var err error
+	NewBodyLimit(5).Execute(httptest.NewRecorder(), req, func(_ http.ResponseWriter, r *http.Request) {
+		_, err = io.ReadAll(r.Body)
+	})
+
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", t.Name(), err, "http: request body too large")
+	}
+}
@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// 保存済みのレスポンスを再送したことを示すヘッダー
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// 処理中の予約の有効期限
+	// 処理中のプロセスが停止した場合も、この時間が経てば同じキーで再試行できる
+	// リクエストの処理にかかる時間 (コンテキストのタイムアウトや WriteTimeout) より長くする
+	idempotencyLockTTL = time.Minute
+)
+
+type Idempotency struct {
+	repo domain.IdempotencyRepository
+	log  logger.Logger
+	ttl  time.Duration
+}
+
+func NewIdempotency(repo domain.IdempotencyRepository, log logger.Logger, ttl time.Duration) Idempotency {
+	return Idempotency{
+		repo: repo,
+		log:  log,
+		ttl:  ttl,
+	}
+}
+
+// Idempotency-Key ヘッダーが指定されたリクエストを1度だけ処理する
+// 2回目以降は保存済みのレスポンスを再送し、処理中の場合は 409 を返却する
+// 処理中の予約は短い期限で保持し、レスポンスを保存した時に ttl まで延長する
+func (m Idempotency) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	const logKey = "idempotency_middleware"
+
+	m.log = m.log.WithContext(r.Context())
+
+	var key = r.Header.Get(HeaderIdempotencyKey)
+	if key == "" {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		m.fail(w, response.ErrIdempotencyKeyInvalid, logKey, http.StatusBadRequest)
+		return
+	}
+
+	fingerprint, err := requestFingerprint(r)
+	if err != nil {
+		m.fail(w, err, logKey, http.StatusBadRequest)
+		return
+	}
+
+	var accountID, _ = domain.AccountIDFromContext(r.Context())
+	record, reserved, err := m.repo.Reserve(r.Context(), domain.IdempotencyRecord{
+		AccountID:   accountID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(m.lockTTL()),
+	})
+	if err != nil {
+		m.fail(w, err, logKey, response.StatusCode(err))
+		return
+	}
+
+	if !reserved {
+		switch {
+		case record.Fingerprint != fingerprint:
+			m.fail(w, response.ErrIdempotencyKeyMismatch, logKey, http.StatusUnprocessableEntity)
+		case !record.Completed():
+			m.fail(w, response.ErrIdempotencyKeyInUse, logKey, http.StatusConflict)
+		default:
+			replay(w, record)
+		}
+		return
+	}
+
+	// クライアントが切断しても結果を保存できるように、キャンセルされないコンテキストを使用する
+	var ctx = context.WithoutCancel(r.Context())
+
+	// ハンドラーが panic した場合も再試行できるように予約を取り消し、panic は外側の Recovery に伝える
+	defer func() {
+		if p := recover(); p != nil {
+			if err := m.repo.Release(ctx, record); err != nil {
+				logging.NewError(m.log, err, logKey, http.StatusInternalServerError).Log("error when releasing idempotency key")
+			}
+			panic(p)
+		}
+	}()
+
+	var rec = &responseRecorder{ResponseWriter: w}
+	next.ServeHTTP(rec, r)
+
+	// サーバーエラーの場合は再試行できるように予約を取り消す
+	if rec.statusCode() >= http.StatusInternalServerError {
+		if err := m.repo.Release(ctx, record); err != nil {
+			logging.NewError(m.log, err, logKey, rec.statusCode()).Log("error when releasing idempotency key")
+		}
+		return
+	}
+
+	record.StatusCode = rec.statusCode()
+	record.Header = storedHeader(w.Header())
+	record.Body = rec.body.Bytes()
+	record.ExpiresAt = time.Now().Add(m.ttl)
+	if err := m.repo.Complete(ctx, record); err != nil {
+		logging.NewError(m.log, err, logKey, rec.statusCode()).Log("error when saving idempotent response")
+	}
+}
+
+// 保存したレスポンスの保持期間より長くはしない
+func (m Idempotency) lockTTL() time.Duration {
+	if m.ttl < idempotencyLockTTL {
+		return m.ttl
+	}
+
+	return idempotencyLockTTL
+}
+
+func (m Idempotency) fail(w http.ResponseWriter, err error, logKey string, status int) {
+	logging.NewError(m.log, err, logKey, status).Log("idempotency check failed")
+
+	response.NewError(err, status).Send(w)
+}
+
+// 保存済みのレスポンスを再送する
+func replay(w http.ResponseWriter, record domain.IdempotencyRecord) {
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(HeaderIdempotentReplayed, "true")
+
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// リクエストごとに異なるヘッダーを除いて保存する
+func storedHeader(header http.Header) map[string][]string {
+	var stored = make(map[string][]string, len(header))
+	for name, values := range header {
+		if name == response.HeaderRequestID {
+			continue
+		}
+
+		stored[name] = values
+	}
+
+	return stored
+}
+
+// メソッド、パス、ボディからリクエストのハッシュを計算する
+func requestFingerprint(r *http.Request) (string, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewBuffer(body))
+	}
+
+	var h = sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// 書き込まれたステータスコードとボディを記録する
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+
+	return r.status
+}
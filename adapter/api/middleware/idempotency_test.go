@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+)
+
+type idempotencyKey struct {
+	accountID domain.AccountID
+	key       string
+}
+
+type mockIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[idempotencyKey]domain.IdempotencyRecord
+}
+
+func newMockIdempotencyRepository() *mockIdempotencyRepository {
+	return &mockIdempotencyRepository{records: map[idempotencyKey]domain.IdempotencyRecord{}}
+}
+
+func (m *mockIdempotencyRepository) Reserve(_ context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var k = idempotencyKey{record.AccountID, record.Key}
+	if existing, ok := m.records[k]; ok && existing.ExpiresAt.After(time.Now()) {
+		return existing, false, nil
+	}
+
+	m.records[k] = record
+	return record, true, nil
+}
+
+func (m *mockIdempotencyRepository) Complete(_ context.Context, record domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var k = idempotencyKey{record.AccountID, record.Key}
+	if !m.reserved(k, record.Fingerprint) {
+		return domain.ErrIdempotencyReservationLost
+	}
+
+	m.records[k] = record
+	return nil
+}
+
+func (m *mockIdempotencyRepository) Release(_ context.Context, record domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var k = idempotencyKey{record.AccountID, record.Key}
+	if !m.reserved(k, record.Fingerprint) {
+		return domain.ErrIdempotencyReservationLost
+	}
+
+	delete(m.records, k)
+	return nil
+}
+
+// 同じリクエストの予約が処理中のまま残っているか
+func (m *mockIdempotencyRepository) reserved(k idempotencyKey, fingerprint string) bool {
+	existing, ok := m.records[k]
+	return ok && existing.Fingerprint == fingerprint && !existing.Completed()
+}
+
+func (m *mockIdempotencyRepository) Prune(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotency_Execute(t *testing.T) {
+	t.Parallel()
+
+	var logger = log.LoggerMock{}
+
+	// 呼び出し回数を数えるハンドラーを作成する
+	newHandler := func(calls *int, status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			*calls++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}
+	}
+
+	newRequest := func(key, account, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/v1/tasks", bytes.NewBufferString(body))
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		if account != "" {
+			req.Header.Set(HeaderAccountID, account)
+		}
+		return req
+	}
+
+	serve := func(m Idempotency, req *http.Request, next http.HandlerFunc) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		NewAccount(logger).Execute(rr, req, func(w http.ResponseWriter, r *http.Request) {
+			m.Execute(w, r, next)
+		})
+		return rr
+	}
+
+	t.Run("replays the stored response", func(t *testing.T) {
+		var (
+			calls int
+			m     = NewIdempotency(newMockIdempotencyRepository(), logger, time.Hour)
+			next  = newHandler(&calls, http.StatusCreated)
+		)
+
+		first := serve(m, newRequest("key-1", "1", `{"title":"a"}`), next)
+		second := serve(m, newRequest("key-1", "1", `{"title":"a"}`), next)
+
+		if calls != 1 {
+			t.Errorf("handler called %d times, want 1", calls)
+		}
+		if second.Code != first.Code || second.Body.String() != first.Body.String() {
+			t.Errorf("replayed response = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+		}
+		if second.Header().Get(HeaderIdempotentReplayed) != "true" {
+			t.Errorf("replayed response is missing the %s header", HeaderIdempotentReplayed)
+		}
+		if second.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("replayed Content-Type = %q", second.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("keys are scoped per account", func(t *testing.T) {
+		var (
+			calls int
+			m     = NewIdempotency(newMockIdempotencyRepository(), logger, time.Hour)
+			next  = newHandler(&calls, http.StatusCreated)
+		)
+
+		serve(m, newRequest("key-1", "1", `{"title":"a"}`), next)
+		serve(m, newRequest("key-1", "2", `{"title":"a"}`), next)
+
+		if calls != 2 {
+			t.Errorf("handler called %d times, want 2", calls)
+		}
+	})
+
+	t.Run("rejects a key reused with a different body", func(t *testing.T) {
+		var (
+			calls int
+			m     = NewIdempotency(newMockIdempotencyRepository(), logger, time.Hour)
+			next  = newHandler(&calls, http.StatusCreated)
+		)
+
+		serve(m, newRequest("key-1", "", `{"title":"a"}`), next)
+		rr := serve(m, newRequest("key-1", "", `{"title":"b"}`), next)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+		}
+	})
+
+	t.Run("rejects a retry while in progress", func(t *testing.T) {
+		var (
+			repo = newMockIdempotencyRepository()
+			m    = NewIdempotency(repo, logger, time.Hour)
+			rr   *httptest.ResponseRecorder
+		)
+
+		serve(m, newRequest("key-1", "", `{"title":"a"}`), func(w http.ResponseWriter, _ *http.Request) {
+			rr = serve(m, newRequest("key-1", "", `{"title":"a"}`), newHandler(new(int), http.StatusCreated))
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		if rr.Code != http.StatusConflict {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("releases the key on server errors", func(t *testing.T) {
+		var (
+			calls int
+			m     = NewIdempotency(newMockIdempotencyRepository(), logger, time.Hour)
+			next  = newHandler(&calls, http.StatusInternalServerError)
+		)
+
+		serve(m, newRequest("key-1", "", `{"title":"a"}`), next)
+		serve(m, newRequest("key-1", "", `{"title":"a"}`), next)
+
+		if calls != 2 {
+			t.Errorf("handler called %d times, want 2", calls)
+		}
+	})
+
+	t.Run("releases the key when the handler panics", func(t *testing.T) {
+		var (
+			calls int
+			m     = NewIdempotency(newMockIdempotencyRepository(), logger, time.Hour)
+		)
+
+		func() {
+			defer func() {
+				if p := recover(); p == nil {
+					t.Errorf("panic was not propagated")
+				}
+			}()
+			serve(m, newRequest("key-1", "", `{"title":"a"}`), func(http.ResponseWriter, *http.Request) {
+				calls++
+				panic("boom")
+			})
+		}()
+
+		serve(m, newRequest("key-1", "", `{"title":"a"}`), newHandler(&calls, http.StatusCreated))
+
+		if calls != 2 {
+			t.Errorf("handler called %d times, want 2", calls)
+		}
+	})
+
+	t.Run("keeps a reservation taken over by another request", func(t *testing.T) {
+		for _, status := range []int{http.StatusCreated, http.StatusInternalServerError} {
+			var (
+				repo  = newMockIdempotencyRepository()
+				m     = NewIdempotency(repo, logger, time.Hour)
+				other = domain.IdempotencyRecord{Key: "key-1", Fingerprint: "other", ExpiresAt: time.Now().Add(time.Minute)}
+			)
+
+			// 処理中に予約の期限が切れ、別のリクエストが予約し直した場合
+			serve(m, newRequest("key-1", "", `{"title":"a"}`), func(w http.ResponseWriter, _ *http.Request) {
+				repo.records[idempotencyKey{0, "key-1"}] = other
+				w.WriteHeader(status)
+			})
+
+			if got := repo.records[idempotencyKey{0, "key-1"}]; got.Fingerprint != other.Fingerprint || got.Completed() {
+				t.Errorf("status %d: record = %+v, want %+v", status, got, other)
+			}
+		}
+	})
+
+	t.Run("reserves briefly and keeps the response for the ttl", func(t *testing.T) {
+		var (
+			repo    = newMockIdempotencyRepository()
+			m       = NewIdempotency(repo, logger, 24*time.Hour)
+			reserve time.Time
+		)
+
+		serve(m, newRequest("key-1", "", `{"title":"a"}`), func(w http.ResponseWriter, _ *http.Request) {
+			reserve = repo.records[idempotencyKey{0, "key-1"}].ExpiresAt
+			w.WriteHeader(http.StatusCreated)
+		})
+		var stored = repo.records[idempotencyKey{0, "key-1"}].ExpiresAt
+
+		if limit := time.Now().Add(idempotencyLockTTL); reserve.After(limit) {
+			t.Errorf("reservation expires at %v, want before %v", reserve, limit)
+		}
+		if limit := time.Now().Add(23 * time.Hour); stored.Before(limit) {
+			t.Errorf("stored response expires at %v, want after %v", stored, limit)
+		}
+	})
+
+	t.Run("rejects an invalid account", func(t *testing.T) {
+		var calls int
+		rr := serve(
+			NewIdempotency(newMockIdempotencyRepository(), logger, time.Hour),
+			newRequest("key-1", "abc", `{"title":"a"}`),
+			newHandler(&calls, http.StatusCreated),
+		)
+
+		if rr.Code != http.StatusBadRequest || calls != 0 {
+			t.Errorf("status = %d, calls = %d, want %d, 0", rr.Code, calls, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+)
+
+type LegacyError struct {
+	enabled bool
+}
+
+func NewLegacyError(enabled bool) LegacyError {
+	return LegacyError{enabled: enabled}
+}
+
+// 有効な場合、問題詳細のレスポンス (application/problem+json) を旧形式 {"errors": [...]} に書き換える
+// 既存クライアントの移行が終わるまでの互換用
+// すべてのエラーレスポンスを書き換えるため、他のミドルウェアより外側で実行する
+func (m LegacyError) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if !m.enabled {
+		next(w, r)
+		return
+	}
+
+	var lw = &legacyErrorWriter{ResponseWriter: w}
+	next(lw, r)
+	_ = lw.finish()
+}
+
+// 問題詳細だけを書き込み終わるまで保持し、それ以外はそのまま書き込む
+type legacyErrorWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	status      int
+	// 問題詳細を書き込んでいる場合だけ nil 以外
+	problem *bytes.Buffer
+}
+
+// 書き込んだステータスコードを返却する (Metrics・Tracing 用)
+// 旧形式に書き換えるレスポンスも、書き換えた後と同じステータスコードを返却する
+func (lw *legacyErrorWriter) Status() int {
+	return lw.status
+}
+
+func (lw *legacyErrorWriter) WriteHeader(status int) {
+	if lw.wroteHeader {
+		return
+	}
+	lw.wroteHeader = true
+	lw.status = status
+
+	if lw.Header().Get("Content-Type") == response.ContentTypeProblem {
+		lw.problem = &bytes.Buffer{}
+		return
+	}
+
+	lw.ResponseWriter.WriteHeader(status)
+}
+
+func (lw *legacyErrorWriter) Write(b []byte) (int, error) {
+	if !lw.wroteHeader {
+		lw.WriteHeader(http.StatusOK)
+	}
+
+	if lw.problem != nil {
+		return lw.problem.Write(b)
+	}
+
+	return lw.ResponseWriter.Write(b)
+}
+
+// http.ResponseController が元の ResponseWriter を使えるようにする
+func (lw *legacyErrorWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
+// 保持した問題詳細を旧形式で書き込む
+// 問題詳細として読み取れない場合は、そのまま書き込む
+func (lw *legacyErrorWriter) finish() error {
+	if lw.problem == nil {
+		return nil
+	}
+
+	var e response.Error
+	if err := json.Unmarshal(lw.problem.Bytes(), &e); err != nil {
+		lw.ResponseWriter.WriteHeader(lw.status)
+		_, err := lw.ResponseWriter.Write(lw.problem.Bytes())
+		return err
+	}
+
+	e.Status = lw.status
+	return e.SendLegacy(lw.ResponseWriter)
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/metrics"
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+)
+
+func TestLegacyError_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		enabled             bool
+		lang                string
+		handler             http.HandlerFunc
+		expectedStatus      int
+		expectedBody        string
+		expectedContentType string
+	}{
+		{
+			name:    "Rewrites a validation error",
+			enabled: true,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_ = response.NewValidationError([]validator.Violation{{Field: "title", Rule: "required", Message: "title is a required field"}}, http.StatusBadRequest).Send(w)
+			},
+			expectedStatus:      http.StatusBadRequest,
+			expectedBody:        `{"errors":["title is a required field"]}`,
+			expectedContentType: "application/json",
+		},
+		{
+			name:    "Rewrites a localized error",
+			enabled: true,
+			lang:    "ja",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_ = response.NewError(errors.New("error creating task: pq: connection refused"), http.StatusInternalServerError).Send(w)
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedBody:        `{"errors":["サーバー内部でエラーが発生しました"]}`,
+			expectedContentType: "application/json",
+		},
+		{
+			name:    "Keeps a successful response",
+			enabled: true,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_ = response.NewSuccess(map[string]int{"id": 1}, http.StatusCreated).Send(w)
+			},
+			expectedStatus:      http.StatusCreated,
+			expectedBody:        `{"id":1}`,
+			expectedContentType: "application/json",
+		},
+		{
+			name:    "Keeps the problem format when disabled",
+			enabled: false,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_ = response.NewError(response.ErrParameterInvalid, http.StatusBadRequest).Send(w)
+			},
+			expectedStatus:      http.StatusBadRequest,
+			expectedBody:        `{"type":"about:blank","title":"Bad Request","status":400,"detail":"parameter invalid"}`,
+			expectedContentType: response.ContentTypeProblem,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := http.NewRequest(http.MethodPost, "/v1/tasks", nil)
+
+			var w = httptest.NewRecorder()
+			if tt.lang != "" {
+				w.Header().Set("Content-Language", tt.lang)
+			}
+
+			NewLegacyError(tt.enabled).Execute(w, req, tt.handler)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("[TestCase '%s'] Status: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatus)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.expectedContentType {
+				t.Errorf("[TestCase '%s'] Content-Type: '%v' | Expected: '%v'", tt.name, got, tt.expectedContentType)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if result != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expectedBody)
+			}
+		})
+	}
+}
+
+// 旧形式に書き換えるレスポンスも、書き換える前のステータスコードでメトリクスに記録する
+func TestLegacyError_Execute_Metrics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		registry    = metrics.NewRegistry()
+		httpMetrics = NewMetrics(registry, func(*http.Request) string { return "/v1/tasks" })
+		handler     = func(w http.ResponseWriter, r *http.Request) {
+			httpMetrics.Execute(w, r, func(w http.ResponseWriter, _ *http.Request) {
+				_ = response.NewError(errors.New("pq: connection refused"), http.StatusInternalServerError).Send(w)
+			})
+		}
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/tasks", nil)
+	var w = httptest.NewRecorder()
+
+	NewLegacyError(true).Execute(w, req, handler)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("[TestCase '%s'] Status: '%v' | Expected: '%v'", t.Name(), w.Code, http.StatusInternalServerError)
+	}
+
+	var buf bytes.Buffer
+	if err := registry.Write(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = `http_requests_total{method="GET",route="/v1/tasks",status="500"} 1`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", t.Name(), buf.String(), expected)
+	}
+}
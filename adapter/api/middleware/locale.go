@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/locale"
+)
+
+type Locale struct{}
+
+func NewLocale() Locale {
+	return Locale{}
+}
+
+// Accept-Language ヘッダーから応答する言語を決定し、コンテキストとレスポンスヘッダーにセットする
+func (m Locale) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	var lang = locale.Negotiate(r.Header.Get("Accept-Language"))
+
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+
+	next.ServeHTTP(w, r.WithContext(locale.WithLanguage(r.Context(), lang)))
+}
@@ -15,45 +15,62 @@ import (
 
 type Logger struct {
 	log logger.Logger
+	// リクエストボディをログに出力するか
+	payload bool
 }
 
 func NewLogger(log logger.Logger) Logger {
-	return Logger{log: log}
+	return Logger{log: log, payload: true}
+}
+
+// リクエストボディを読み取らず、ログにも出力しない Logger を返却する
+// 大きなファイルや秘密の値を受け取るルートで使用する
+func (l Logger) WithoutPayload() Logger {
+	l.payload = false
+	return l
 }
 
 func (l Logger) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	start := time.Now()
 
+	// リクエストのコンテキストの項目 (トレースのIDなど) をすべてのログに付ける
+	var log = l.log.WithContext(r.Context())
+
 	const (
 		logKey      = "logger_middleware"
 		requestKey  = "api_request"
 		responseKey = "api_response"
 	)
 
-	body, err := getRequestPayload(r)
-	if err != nil {
-		logging.NewError(
-			l.log,
-			err,
-			logKey,
-			http.StatusBadRequest,
-		).Log("error when getting payload")
-
-		return
-	}
-
-	l.log.WithFields(logger.Fields{
+	var fields = logger.Fields{
 		"key":         requestKey,
-		"payload":     body,
 		"url":         r.URL.Path,
 		"http_method": r.Method,
-	}).Infof("started handling request")
+	}
+
+	if l.payload {
+		body, err := getRequestPayload(r)
+		if err != nil {
+			logging.NewError(
+				log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when getting payload")
+
+			return
+		}
+
+		fields["payload"] = body
+	}
+
+	log.WithFields(fields).Infof("started handling request")
 
 	next.ServeHTTP(w, r)
 
 	end := time.Since(start).Seconds()
 	res := w.(negroni.ResponseWriter)
-	l.log.WithFields(logger.Fields{
+	log.WithFields(logger.Fields{
 		"key":           responseKey,
 		"url":           r.URL.Path,
 		"http_method":   r.Method,
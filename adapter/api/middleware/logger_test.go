@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/urfave/negroni"
+)
+
+// 出力した項目を記録するロガー
+type fieldsRecorder struct {
+	mu     *sync.Mutex
+	fields *[]logger.Fields
+}
+
+func newFieldsRecorder() fieldsRecorder {
+	return fieldsRecorder{mu: &sync.Mutex{}, fields: &[]logger.Fields{}}
+}
+
+func (l fieldsRecorder) Infof(_ string, _ ...interface{})  {}
+func (l fieldsRecorder) Warnf(_ string, _ ...interface{})  {}
+func (l fieldsRecorder) Errorf(_ string, _ ...interface{}) {}
+func (l fieldsRecorder) Fatalln(_ ...interface{})          {}
+func (l fieldsRecorder) WithFields(fields logger.Fields) logger.Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	*l.fields = append(*l.fields, fields)
+	return l
+}
+func (l fieldsRecorder) WithError(_ error) logger.Logger             { return l }
+func (l fieldsRecorder) WithContext(_ context.Context) logger.Logger { return l }
+
+func TestLogger_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		withoutPayload  bool
+		expectedPayload interface{}
+	}{
+		{
+			name:            "Logs the payload",
+			expectedPayload: `{"title":"Task_1"}`,
+		},
+		{
+			name:            "Does not log the payload",
+			withoutPayload:  true,
+			expectedPayload: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				log = newFieldsRecorder()
+				m   = NewLogger(log)
+			)
+			if tt.withoutPayload {
+				m = m.WithoutPayload()
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "/v1/tasks", strings.NewReader(`{"title":"Task_1"}`))
+
+			var body string
+			m.Execute(negroni.NewResponseWriter(httptest.NewRecorder()), req, func(_ http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+			})
+
+			// ハンドラーはボディを読み取れる
+			if body != `{"title":"Task_1"}` {
+				t.Errorf("[TestCase '%s'] Body: '%v' | Expected: '%v'", tt.name, body, `{"title":"Task_1"}`)
+			}
+
+			var result = (*log.fields)[0]["payload"]
+			if result != tt.expectedPayload {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expectedPayload)
+			}
+		})
+	}
+}
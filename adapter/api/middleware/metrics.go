@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/metrics"
+)
+
+// リクエストの件数・レイテンシー・処理中の件数をルートごとに記録する
+// メトリクスを登録するため、サーバーごとに1つだけ作成する
+type Metrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	inFlight *metrics.GaugeVec
+	// リクエストのルートの名前
+	// ラベルの値が増え続けないよう、パスパラメータを含まない名前 (/v1/tasks/{task_id} など) を返却する
+	route func(*http.Request) string
+}
+
+func NewMetrics(registry *metrics.Registry, route func(*http.Request) string) Metrics {
+	return Metrics{
+		requests: registry.Counter(
+			"http_requests_total",
+			"Total number of HTTP requests by route and status code.",
+			"method", "route", "status",
+		),
+		duration: registry.Histogram(
+			"http_request_duration_seconds",
+			"Latency of HTTP requests by route.",
+			metrics.DefaultBuckets,
+			"method", "route",
+		),
+		inFlight: registry.Gauge(
+			"http_requests_in_flight",
+			"Number of HTTP requests currently being served by route.",
+			"method", "route",
+		),
+		route: route,
+	}
+}
+
+// negroni.ResponseWriter など、ステータスコードを記録する ResponseWriter でラップされた後に実行する
+func (m Metrics) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	var (
+		start = time.Now()
+		route = m.route(r)
+	)
+
+	m.inFlight.Inc(r.Method, route)
+	defer m.inFlight.Dec(r.Method, route)
+
+	next.ServeHTTP(w, r)
+
+	var status = responseStatus(w)
+
+	m.requests.Inc(r.Method, route, strconv.Itoa(status))
+	m.duration.Observe(time.Since(start).Seconds(), r.Method, route)
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+)
+
+// クライアントから受け付けるリクエストIDの最大長
+const maxRequestIDLength = 128
+
+type RequestID struct{}
+
+func NewRequestID() RequestID {
+	return RequestID{}
+}
+
+// リクエストIDをコンテキストとレスポンスヘッダーに設定する
+// クライアントから指定されていない場合や、ログに書き込めない値が指定された場合は新しく採番する
+func (m RequestID) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	var id = r.Header.Get(response.HeaderRequestID)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+
+	w.Header().Set(response.HeaderRequestID, id)
+
+	next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+}
+
+// 英数字と - _ . : だけからなる値を受け付ける
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	var b = make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
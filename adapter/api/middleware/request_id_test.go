@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+func TestRequestID_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		header    string
+		generated bool
+	}{
+		{
+			name:   "Accepts the client request id",
+			header: "client-req_1.2:3",
+		},
+		{
+			name:      "Generates a request id",
+			generated: true,
+		},
+		{
+			name:      "Replaces a request id that cannot be logged",
+			header:    "id\nforged=1",
+			generated: true,
+		},
+		{
+			name:      "Replaces a request id that is too long",
+			header:    strings.Repeat("a", maxRequestIDLength+1),
+			generated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := http.NewRequest(http.MethodGet, "/v1/tasks", nil)
+			if tt.header != "" {
+				req.Header.Set(response.HeaderRequestID, tt.header)
+			}
+
+			var (
+				w      = httptest.NewRecorder()
+				fields logger.Fields
+			)
+
+			NewRequestID().Execute(w, req, func(_ http.ResponseWriter, r *http.Request) {
+				var ctx = logging.WithRoute(domain.WithAccountID(r.Context(), 7), "/v1/tasks")
+				fields = logging.ContextFields(ctx)
+			})
+
+			var id = w.Header().Get(response.HeaderRequestID)
+			if tt.generated && (id == tt.header || len(id) != 32) || !tt.generated && id != tt.header {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v' (generated: %v)", tt.name, id, tt.header, tt.generated)
+			}
+
+			// ハンドラーの中で出力するログには、レスポンスと同じリクエストIDを付ける
+			var expected = logger.Fields{"request_id": id, "route": "/v1/tasks", "account_id": domain.AccountID(7)}
+			if !reflect.DeepEqual(fields, expected) {
+				t.Errorf("[TestCase '%s'] Fields: '%v' | Expected: '%v'", tt.name, fields, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/response"
+)
+
+type ResponseController struct{}
+
+func NewResponseController() ResponseController {
+	return ResponseController{}
+}
+
+// negroni にラップされる前の ResponseWriter から ResponseController を作成し、コンテキストにセットする
+// negroni のミドルウェアより外側で実行する (response.Controller を参照)
+func (m ResponseController) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	var ctx = response.WithController(r.Context(), http.NewResponseController(w))
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+)
+
+type Route struct {
+	// リクエストのルートの名前 (Metrics と同じ)
+	route func(*http.Request) string
+}
+
+func NewRoute(route func(*http.Request) string) Route {
+	return Route{route: route}
+}
+
+// リクエストが一致したルートをコンテキストにセットする (ログに付ける)
+func (m Route) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	next.ServeHTTP(w, r.WithContext(logging.WithRoute(r.Context(), m.route(r))))
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import "net/http"
+
+// 書き込んだステータスコードを返却する ResponseWriter (negroni.ResponseWriter・legacyErrorWriter)
+type statusWriter interface {
+	Status() int
+}
+
+// 書き込んだステータスコードを返却する
+// 何も書き込まなかった場合は 200 が返却される
+func responseStatus(w http.ResponseWriter) int {
+	if res, ok := w.(statusWriter); ok && res.Status() != 0 {
+		return res.Status()
+	}
+
+	return http.StatusOK
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/adapter/tracing"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type Tracing struct {
+	// リクエストのルートの名前 (Metrics と同じ)
+	route func(*http.Request) string
+}
+
+func NewTracing(route func(*http.Request) string) Tracing {
+	return Tracing{route: route}
+}
+
+// リクエストの区間を開始し、コンテキストにセットする
+// traceparent ヘッダーがあれば、呼び出し元のトレースの続きとして記録する
+// negroni.ResponseWriter など、ステータスコードを記録する ResponseWriter でラップされた後に実行する
+func (m Tracing) Execute(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	var ctx = r.Context()
+	if sc, ok := tracing.ParseTraceparent(r.Header.Get(tracing.HeaderTraceparent)); ok {
+		ctx = tracing.WithRemoteSpanContext(ctx, sc)
+	}
+
+	var route = m.route(r)
+	ctx, span := domain.StartSpan(ctx, r.Method+" "+route)
+	defer span.End()
+
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("http.route", route)
+	span.SetAttribute("http.target", r.URL.Path)
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+
+	var status = responseStatus(w)
+
+	span.SetAttribute("http.status_code", status)
+	if status >= http.StatusInternalServerError {
+		span.RecordError(errors.New(http.StatusText(status)))
+	}
+}
@@ -0,0 +1,26 @@
+package response
+
+import (
+	"context"
+	"net/http"
+)
+
+type controllerContextKey string
+
+const keyController controllerContextKey = "ResponseControllerContextKey"
+
+// コンテキストにサーバーから渡された ResponseWriter の ResponseController をセットし返却する
+func WithController(ctx context.Context, rc *http.ResponseController) context.Context {
+	return context.WithValue(ctx, keyController, rc)
+}
+
+// レスポンスの書き込み期限の変更やフラッシュに使用する ResponseController を返却する
+// negroni の ResponseWriter は Unwrap を実装しておらず、ラップした後では書き込み期限を変更できないため、
+// コンテキストにセットされていればそれを、なければ w から作成したものを返却する
+func Controller(w http.ResponseWriter, r *http.Request) *http.ResponseController {
+	if rc, ok := r.Context().Value(keyController).(*http.ResponseController); ok {
+		return rc
+	}
+
+	return http.NewResponseController(w)
+}
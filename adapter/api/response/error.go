@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/domain"
 	"github.com/pkg/errors"
 )
 
@@ -11,29 +13,171 @@ var (
 	ErrParameterInvalid = errors.New("parameter invalid")
 
 	ErrInvalidInput = errors.New("invalid input")
+
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	ErrPatchNotApplicable = errors.New("patch could not be applied")
+
+	ErrAccountInvalid = errors.New("account invalid")
+
+	ErrBatchRolledBack = errors.New("batch operations rolled back")
+
+	ErrIdempotencyKeyInvalid = errors.New("idempotency key invalid")
+
+	ErrIdempotencyKeyInUse = errors.New("a request with the same idempotency key is being processed")
+
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was used with a different request")
+)
+
+const (
+	// RFC 7807
+	ContentTypeProblem = "application/problem+json"
+
+	// リクエストIDを伝搬するヘッダー
+	HeaderRequestID = "X-Request-ID"
 )
 
+// 問題の種別を表すURI
+const (
+	ProblemTypeDefault     = "about:blank"
+	ProblemTypeValidation  = "/problems/validation-error"
+	ProblemTypeNotFound    = "/problems/not-found"
+	ProblemTypeConflict    = "/problems/conflict"
+	ProblemTypeForbidden   = "/problems/forbidden"
+	ProblemTypeUnavailable = "/problems/unavailable"
+)
+
+// 不正なパラメータ
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Rule   string `json:"rule,omitempty"`
+	Param  string `json:"param,omitempty"`
+}
+
+// RFC 7807 の問題詳細
 type Error struct {
-	statusCode int
-	Errors     []string `json:"errors"`
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
 }
 
+// エラーから問題詳細を生成する
+// サーバーエラーの場合は内部のエラーメッセージをクライアントに返却しない
 func NewError(err error, status int) *Error {
-	return &Error{
-		statusCode: status,
-		Errors:     []string{err.Error()},
+	var e = &Error{
+		Type:   problemType(err),
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+
+	var domainErr *domain.Error
+	switch {
+	case status >= http.StatusInternalServerError:
+	case errors.As(err, &domainErr):
+		e.Detail = domainErr.Message()
+	default:
+		e.Detail = err.Error()
 	}
+
+	return e
 }
 
-func NewErrorMessage(messages []string, status int) *Error {
-	return &Error{
-		statusCode: status,
-		Errors:     messages,
+// フィールド単位のバリデーションエラーから問題詳細を生成する
+func NewValidationError(violations []validator.Violation, status int) *Error {
+	var e = &Error{
+		Type:   ProblemTypeValidation,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: ErrInvalidInput.Error(),
 	}
+
+	for _, v := range violations {
+		e.InvalidParams = append(e.InvalidParams, InvalidParam{
+			Name:   v.Field,
+			Reason: v.Message,
+			Rule:   v.Rule,
+			Param:  v.Param,
+		})
+	}
+
+	return e
 }
 
 func (e Error) Send(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(e.statusCode)
+	e = e.Embed(w)
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(e.Status)
 	return json.NewEncoder(w).Encode(e)
 }
+
+// レスポンスのリクエストIDと言語を反映した問題詳細を返却する
+// 一括操作の結果のように、他のレスポンスの本文に埋め込む場合に使用する
+func (e Error) Embed(w http.ResponseWriter) Error {
+	if e.Instance == "" {
+		e.Instance = w.Header().Get(HeaderRequestID)
+	}
+
+	e.localize(w.Header().Get("Content-Language"))
+
+	return e
+}
+
+// 旧形式 {"errors": [...]} で返却する
+// 既存クライアントの移行が終わるまでの互換用 (middleware.LegacyError を参照)
+func (e Error) SendLegacy(w http.ResponseWriter) error {
+	e.localize(w.Header().Get("Content-Language"))
+
+	var messages []string
+	switch {
+	case len(e.InvalidParams) > 0:
+		for _, p := range e.InvalidParams {
+			messages = append(messages, p.Reason)
+		}
+	case e.Detail != "":
+		messages = []string{e.Detail}
+	default:
+		messages = []string{e.Title}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	return json.NewEncoder(w).Encode(struct {
+		Errors []string `json:"errors"`
+	}{Errors: messages})
+}
+
+// タイトルと詳細をレスポンスの言語に翻訳する
+// バリデーションエラーのメッセージはバリデーターで翻訳済み
+func (e *Error) localize(lang string) {
+	e.Title = translate(lang, e.Title)
+	e.Detail = translate(lang, e.Detail)
+}
+
+func problemType(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		return ProblemTypeValidation
+	case errors.Is(err, ErrPatchNotApplicable), errors.Is(err, ErrIdempotencyKeyInUse):
+		return ProblemTypeConflict
+	}
+
+	switch domain.KindOf(err) {
+	case domain.KindNotFound:
+		return ProblemTypeNotFound
+	case domain.KindConflict:
+		return ProblemTypeConflict
+	case domain.KindValidation:
+		return ProblemTypeValidation
+	case domain.KindForbidden:
+		return ProblemTypeForbidden
+	case domain.KindUnavailable:
+		return ProblemTypeUnavailable
+	default:
+		return ProblemTypeDefault
+	}
+}
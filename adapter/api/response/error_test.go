@@ -0,0 +1,96 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+func TestError_Send(t *testing.T) {
+	tests := []struct {
+		name                string
+		err                 *Error
+		legacy              bool
+		requestID           string
+		lang                string
+		expectedBody        string
+		expectedContentType string
+	}{
+		{
+			name:                "Problem server error hides detail",
+			err:                 NewError(errors.New("error creating task: pq: connection refused"), http.StatusInternalServerError),
+			requestID:           "abc123",
+			expectedBody:        `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"abc123"}`,
+			expectedContentType: ContentTypeProblem,
+		},
+		{
+			name:                "Problem domain error uses domain message",
+			err:                 NewError(domain.WrapError(domain.KindConflict, errors.New("pq: duplicate key"), "task already exists"), http.StatusConflict),
+			expectedBody:        `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"task already exists"}`,
+			expectedContentType: ContentTypeProblem,
+		},
+		{
+			name:                "Problem invalid params",
+			err:                 NewValidationError([]validator.Violation{{Field: "title", Rule: "required", Message: "title is a required field"}}, http.StatusBadRequest),
+			expectedBody:        `{"type":"/problems/validation-error","title":"Bad Request","status":400,"detail":"invalid input","invalid_params":[{"name":"title","reason":"title is a required field","rule":"required"}]}`,
+			expectedContentType: ContentTypeProblem,
+		},
+		{
+			name:                "Problem localized in Japanese",
+			err:                 NewError(fmt.Errorf("%w: test failed", ErrPatchNotApplicable), http.StatusConflict),
+			lang:                "ja",
+			expectedBody:        `{"type":"/problems/conflict","title":"競合が発生しました","status":409,"detail":"パッチを適用できませんでした: test failed"}`,
+			expectedContentType: ContentTypeProblem,
+		},
+		{
+			name:                "Legacy server error",
+			err:                 NewError(errors.New("error creating task: pq: connection refused"), http.StatusInternalServerError),
+			legacy:              true,
+			expectedBody:        `{"errors":["Internal Server Error"]}`,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "Legacy invalid params",
+			err:                 NewValidationError([]validator.Violation{{Field: "title", Rule: "required", Message: "title is a required field"}}, http.StatusBadRequest),
+			legacy:              true,
+			expectedBody:        `{"errors":["title is a required field"]}`,
+			expectedContentType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			if tt.requestID != "" {
+				w.Header().Set(HeaderRequestID, tt.requestID)
+			}
+			if tt.lang != "" {
+				w.Header().Set("Content-Language", tt.lang)
+			}
+
+			var send = tt.err.Send
+			if tt.legacy {
+				send = tt.err.SendLegacy
+			}
+
+			if err := send(w); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.expectedContentType {
+				t.Errorf("[TestCase '%s'] Content-Type: '%v' | Expected: '%v'", tt.name, got, tt.expectedContentType)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if result != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package response
+
+import (
+	"strings"
+
+	"github.com/doglapping707/todo-api-go/adapter/locale"
+)
+
+// 言語ごとのエラーメッセージ
+// キーは英語のメッセージで、カタログにないメッセージは翻訳せずにそのまま返却する
+var messages = map[string]map[string]string{
+	locale.Japanese: {
+		// タイトル (http.StatusText)
+		"Bad Request":            "不正なリクエストです",
+		"Forbidden":              "アクセスが拒否されました",
+		"Not Found":              "見つかりません",
+		"Conflict":               "競合が発生しました",
+		"Unsupported Media Type": "サポートされていないメディアタイプです",
+		"Unprocessable Entity":   "処理できないリクエストです",
+		"Internal Server Error":  "サーバー内部でエラーが発生しました",
+		"Service Unavailable":    "サービスを一時的に利用できません",
+
+		// 詳細
+		"parameter invalid":            "パラメータが不正です",
+		"invalid input":                "入力値が不正です",
+		"unsupported media type":       "サポートされていないメディアタイプです",
+		"patch could not be applied":   "パッチを適用できませんでした",
+		"invalid merge patch document": "マージパッチのドキュメントが不正です",
+		"invalid json patch document":  "JSONパッチのドキュメントが不正です",
+		"task not found":               "タスクが見つかりません",
+		"account invalid":              "アカウントが不正です",
+		"idempotency key invalid":      "Idempotency-Key が不正です",
+		"invalid calendar feed token":  "カレンダーフィードのトークンが不正です",
+		"webhook not found":            "Webhook が見つかりません",
+		"unknown task field":           "タスクの項目が不正です",
+		"invalid task field":           "タスクの項目の値が不正です",
+
+		"task was modified after it was deleted": "タスクは削除された後に変更されています",
+
+		"a request with the same idempotency key is being processed": "同じ Idempotency-Key のリクエストを処理中です",
+		"idempotency key was used with a different request":          "Idempotency-Key が異なるリクエストで使用されています",
+	},
+}
+
+// メッセージを指定された言語に翻訳する
+// "patch could not be applied: ..." のように原因が続く場合は、先頭のメッセージのみ翻訳する
+func translate(lang, msg string) string {
+	catalog, ok := messages[lang]
+	if !ok {
+		return msg
+	}
+
+	if t, ok := catalog[msg]; ok {
+		return t
+	}
+
+	if prefix, cause, found := strings.Cut(msg, ": "); found {
+		if t, ok := catalog[prefix]; ok {
+			return t + ": " + cause
+		}
+	}
+
+	return msg
+}
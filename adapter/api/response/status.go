@@ -0,0 +1,25 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// エラーの種別に応じたHTTPステータスコードを返却する
+func StatusCode(err error) int {
+	switch domain.KindOf(err) {
+	case domain.KindNotFound:
+		return http.StatusNotFound
+	case domain.KindConflict:
+		return http.StatusConflict
+	case domain.KindValidation:
+		return http.StatusBadRequest
+	case domain.KindForbidden:
+		return http.StatusForbidden
+	case domain.KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,59 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+func TestStatusCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{
+			name:     "Not found",
+			err:      domain.ErrTaskNotFound,
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "Conflict wrapped",
+			err:      fmt.Errorf("outer: %w", domain.WrapError(domain.KindConflict, errors.New("pq"), "error creating task")),
+			expected: http.StatusConflict,
+		},
+		{
+			name:     "Validation",
+			err:      domain.NewError(domain.KindValidation, "title too long"),
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Forbidden",
+			err:      domain.NewError(domain.KindForbidden, "forbidden"),
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "Unavailable",
+			err:      domain.NewError(domain.KindUnavailable, "deadlock"),
+			expected: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "Unknown",
+			err:      errors.New("error"),
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusCode(tt.err); got != tt.expected {
+				t.Errorf("[TestCase '%s'] Got: '%v' | Want: '%v'", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
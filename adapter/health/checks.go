@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doglapping707/todo-api-go/adapter/repository"
+)
+
+// DBとの接続を確認する
+type Pinger interface {
+	PingContext(context.Context) error
+}
+
+func Ping(p Pinger) Probe {
+	return p.PingContext
+}
+
+// DBのスキーマのバージョンが、アプリケーションが前提とするバージョン以上か確認する
+// (_scripts/postgres/schema_version.sql を参照)
+func SchemaVersion(db repository.SQL, expected int) Probe {
+	return func(ctx context.Context) error {
+		var version int
+		if err := db.QueryRowContext(ctx, "SELECT version FROM schema_version").Scan(&version); err != nil {
+			return fmt.Errorf("error reading schema version: %w", err)
+		}
+
+		if version < expected {
+			return fmt.Errorf("schema version %d is older than %d", version, expected)
+		}
+
+		return nil
+	}
+}
+
+// ディレクトリのあるファイルシステムの空き容量が minFree バイト以上か確認する
+func DiskSpace(path string, minFree uint64) Probe {
+	return func(context.Context) error {
+		free, err := freeSpace(path)
+		if err != nil {
+			return err
+		}
+
+		if free < minFree {
+			return fmt.Errorf("%d bytes free on %s, less than %d", free, path, minFree)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 確認の結果
+const (
+	StatusPass = "pass"
+	// 重要でない確認が失敗した (準備はできているとみなす)
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// 確認ごとのタイムアウトの既定値
+const DefaultTimeout = 2 * time.Second
+
+type (
+	// 依存先の状態を確認し、利用できない場合はエラーを返却する
+	Probe func(context.Context) error
+
+	// 名前を付けた確認
+	Check struct {
+		Name string
+		// 失敗した場合に準備ができていないとみなすか
+		Critical bool
+		// 0 の場合は DefaultTimeout とする
+		Timeout time.Duration
+		Probe   Probe
+	}
+
+	// すべての確認の結果
+	Report struct {
+		Status string        `json:"status"`
+		Checks []CheckResult `json:"checks"`
+	}
+
+	CheckResult struct {
+		Name      string  `json:"name"`
+		Status    string  `json:"status"`
+		Critical  bool    `json:"critical"`
+		LatencyMS float64 `json:"latency_ms"`
+		Error     string  `json:"error,omitempty"`
+	}
+)
+
+// 確認を登録し、まとめて実行する
+type Registry struct {
+	mu     sync.Mutex
+	checks []Check
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// 登録した順に結果を返却する
+func (r *Registry) Register(c Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	r.checks = append(r.checks, c)
+}
+
+// すべての確認を並行して実行する
+// 重要な確認が1つでも失敗した場合は fail、重要でない確認だけが失敗した場合は warn とする
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.Lock()
+	var checks = append([]Check(nil), r.checks...)
+	r.mu.Unlock()
+
+	var (
+		report = Report{Status: StatusPass, Checks: make([]CheckResult, len(checks))}
+		wg     sync.WaitGroup
+	)
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c Check) {
+			defer wg.Done()
+			report.Checks[i] = run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, res := range report.Checks {
+		switch {
+		case res.Status == StatusFail:
+			report.Status = StatusFail
+		case res.Status == StatusWarn && report.Status == StatusPass:
+			report.Status = StatusWarn
+		}
+	}
+
+	return report
+}
+
+// タイムアウトまでに結果を返さない確認は、終了を待たずに失敗とする
+func run(ctx context.Context, c Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	var (
+		start = time.Now()
+		done  = make(chan error, 1)
+		err   error
+	)
+	go func() { done <- c.Probe(ctx) }()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	var res = CheckResult{
+		Name:      c.Name,
+		Status:    StatusPass,
+		Critical:  c.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Error = err.Error()
+		res.Status = StatusWarn
+		if c.Critical {
+			res.Status = StatusFail
+		}
+	}
+
+	return res
+}
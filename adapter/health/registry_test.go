@@ -0,0 +1,191 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func pass(context.Context) error { return nil }
+
+func failure(context.Context) error { return errors.New("connection refused") }
+
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+	time.Sleep(time.Second)
+	return nil
+}
+
+func TestRegistry_Run(t *testing.T) {
+	t.Parallel()
+
+	type check struct {
+		critical bool
+		probe    Probe
+	}
+
+	tests := []struct {
+		name           string
+		checks         []check
+		expectedStatus string
+		expectedChecks []string
+	}{
+		{
+			name:           "No checks",
+			expectedStatus: StatusPass,
+			expectedChecks: []string{},
+		},
+		{
+			name:           "All checks pass",
+			checks:         []check{{critical: true, probe: pass}, {probe: pass}},
+			expectedStatus: StatusPass,
+			expectedChecks: []string{StatusPass, StatusPass},
+		},
+		{
+			name:           "Non critical check fails",
+			checks:         []check{{critical: true, probe: pass}, {probe: failure}},
+			expectedStatus: StatusWarn,
+			expectedChecks: []string{StatusPass, StatusWarn},
+		},
+		{
+			name:           "Critical check fails",
+			checks:         []check{{probe: failure}, {critical: true, probe: failure}},
+			expectedStatus: StatusFail,
+			expectedChecks: []string{StatusWarn, StatusFail},
+		},
+		{
+			name:           "Critical check times out",
+			checks:         []check{{critical: true, probe: hang}},
+			expectedStatus: StatusFail,
+			expectedChecks: []string{StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var registry = NewRegistry()
+			for _, c := range tt.checks {
+				registry.Register(Check{Name: tt.name, Critical: c.critical, Timeout: 50 * time.Millisecond, Probe: c.probe})
+			}
+
+			var start = time.Now()
+			report := registry.Run(context.Background())
+
+			if report.Status != tt.expectedStatus {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, report.Status, tt.expectedStatus)
+			}
+
+			if len(report.Checks) != len(tt.expectedChecks) {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, len(report.Checks), len(tt.expectedChecks))
+			}
+			for i, res := range report.Checks {
+				if res.Status != tt.expectedChecks[i] {
+					t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, res.Status, tt.expectedChecks[i])
+				}
+				if (res.Status == StatusPass) != (res.Error == "") {
+					t.Errorf("[TestCase '%s'] Result: '%v' | Expected: error only for failed checks", tt.name, res.Error)
+				}
+			}
+
+			// タイムアウトした確認の終了は待たない
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: less than '%v'", tt.name, elapsed, 500*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestWorkers_Probe(t *testing.T) {
+	t.Parallel()
+
+	var (
+		workers     = NewWorkers()
+		ctx, cancel = context.WithCancel(context.Background())
+		started     = make(chan struct{})
+		done        = make(chan struct{})
+	)
+	defer cancel()
+
+	go func() {
+		workers.Run(ctx, "relay", time.Minute, func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+		})
+		close(done)
+	}()
+	<-started
+
+	if err := workers.Probe(ctx); err != nil {
+		t.Errorf("[TestCase 'Running'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	workers.Run(ctx, "worker", time.Minute, func(context.Context) {})
+
+	var expected = "workers stopped: worker"
+	if err := workers.Probe(ctx); err == nil || err.Error() != expected {
+		t.Errorf("[TestCase 'Stopped'] Result: '%v' | Expected: '%v'", err, expected)
+	}
+
+	cancel()
+	<-done
+
+	expected = "workers stopped: relay, worker"
+	if err := workers.Probe(context.Background()); err == nil || err.Error() != expected {
+		t.Errorf("[TestCase 'All stopped'] Result: '%v' | Expected: '%v'", err, expected)
+	}
+}
+
+// 進捗を記録する間隔の3倍を過ぎても進捗がなければ止まっているとみなす
+func TestWorkers_Probe_Stalled(t *testing.T) {
+	t.Parallel()
+
+	var (
+		workers     = NewWorkers()
+		clock       atomic.Int64
+		ctx, cancel = context.WithCancel(context.Background())
+		progress    = make(chan struct{})
+		progressed  = make(chan struct{})
+	)
+	defer cancel()
+
+	clock.Store(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	workers.now = func() time.Time { return time.Unix(0, clock.Load()) }
+
+	var started = make(chan struct{})
+	go workers.Run(ctx, "relay", time.Minute, func(ctx context.Context) {
+		close(started)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-progress:
+				Progress(ctx)
+				progressed <- struct{}{}
+			}
+		}
+	})
+	<-started
+
+	clock.Add(int64(3 * time.Minute))
+	if err := workers.Probe(ctx); err != nil {
+		t.Errorf("[TestCase 'Within three intervals'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	clock.Add(int64(time.Minute))
+	var expected = "workers stalled: relay (no progress for 4m0s)"
+	if err := workers.Probe(ctx); err == nil || err.Error() != expected {
+		t.Errorf("[TestCase 'Stalled'] Result: '%v' | Expected: '%v'", err, expected)
+	}
+
+	progress <- struct{}{}
+	<-progressed
+
+	if err := workers.Probe(ctx); err != nil {
+		t.Errorf("[TestCase 'Progressed'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+}
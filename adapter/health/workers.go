@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 進捗を記録する間隔の何倍の間、進捗がなければ止まっているとみなすか
+const stalledIntervals = 3
+
+type progressContextKey string
+
+const keyProgress progressContextKey = "WorkerProgressContextKey"
+
+// バックグラウンドで動くワーカーが停止したり、止まったりしていないか記録する
+type Workers struct {
+	mu      sync.Mutex
+	workers map[string]*workerState
+	now     func() time.Time
+}
+
+type workerState struct {
+	running bool
+	// 最後に進捗を記録した日時と、進捗を記録する最長の間隔
+	progressAt time.Time
+	interval   time.Duration
+}
+
+func NewWorkers() *Workers {
+	return &Workers{workers: make(map[string]*workerState), now: time.Now}
+}
+
+// ワーカーを実行し、戻るまで動いているとみなす
+// ワーカーは interval 以内の間隔で Progress を呼び出し、処理が進んでいることを記録する
+// ゴルーチンで呼び出す
+func (w *Workers) Run(ctx context.Context, name string, interval time.Duration, run func(context.Context)) {
+	w.mu.Lock()
+	var state = &workerState{running: true, progressAt: w.now(), interval: interval}
+	w.workers[name] = state
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		state.running = false
+	}()
+
+	run(context.WithValue(ctx, keyProgress, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		state.progressAt = w.now()
+	}))
+}
+
+// ワーカーの処理が進んだことを記録する
+// Workers.Run で実行したワーカーのコンテキストでなければ何もしない
+func Progress(ctx context.Context) {
+	if progress, ok := ctx.Value(keyProgress).(func()); ok {
+		progress()
+	}
+}
+
+// 停止したワーカーか、進捗を記録する間隔の stalledIntervals 倍を過ぎても進捗がないワーカーがあればエラーを返却する
+func (w *Workers) Probe(context.Context) error {
+	w.mu.Lock()
+	var (
+		now              = w.now()
+		stopped, stalled []string
+	)
+	for name, state := range w.workers {
+		switch {
+		case !state.running:
+			stopped = append(stopped, name)
+		case now.Sub(state.progressAt) > stalledIntervals*state.interval:
+			stalled = append(stalled, fmt.Sprintf("%s (no progress for %s)", name, now.Sub(state.progressAt).Round(time.Second)))
+		}
+	}
+	w.mu.Unlock()
+
+	var problems []string
+	if len(stopped) > 0 {
+		sort.Strings(stopped)
+		problems = append(problems, "workers stopped: "+strings.Join(stopped, ", "))
+	}
+	if len(stalled) > 0 {
+		sort.Strings(stalled)
+		problems = append(problems, "workers stalled: "+strings.Join(stalled, ", "))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", strings.Join(problems, "; "))
+}
@@ -0,0 +1,182 @@
+// タスクを iCalendar (RFC 5545) の VTODO として書き出す
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	// 1行の最大オクテット数 (改行を除く)
+	maxLineOctets = 75
+
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// VCALENDAR
+type Calendar struct {
+	ProdID string
+	// カレンダーアプリに表示する名前 (X-WR-CALNAME)
+	Name  string
+	Todos []Todo
+}
+
+// VTODO
+type Todo struct {
+	UID       string
+	Summary   string
+	Due       *time.Time
+	Completed bool
+	// 完了日時 (COMPLETED)
+	CompletedAt *time.Time
+	Priority    int
+	RRule       string
+	Categories  []string
+	Created     time.Time
+	// DTSTAMP にも使用し、同じタスクからは常に同じ出力になるようにする
+	LastModified time.Time
+}
+
+// カレンダーを書き出す
+func Encode(w io.Writer, c Calendar) error {
+	var lw = &lineWriter{w: bufio.NewWriter(w)}
+
+	lw.line("BEGIN", "VCALENDAR")
+	lw.line("VERSION", "2.0")
+	lw.line("PRODID", c.ProdID)
+	lw.line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME", escapeText(c.Name))
+	}
+
+	for _, todo := range c.Todos {
+		encodeTodo(lw, todo)
+	}
+
+	lw.line("END", "VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+
+	return lw.w.Flush()
+}
+
+func encodeTodo(lw *lineWriter, t Todo) {
+	lw.line("BEGIN", "VTODO")
+	lw.line("UID", t.UID)
+	lw.line("DTSTAMP", formatDateTime(t.LastModified))
+	lw.line("CREATED", formatDateTime(t.Created))
+	lw.line("LAST-MODIFIED", formatDateTime(t.LastModified))
+	lw.line("SUMMARY", escapeText(t.Summary))
+
+	if t.Due != nil {
+		// 繰り返しの起点として DTSTART が必要なため、期日と同じ日時をセットする
+		if t.RRule != "" {
+			lw.line("DTSTART", formatDateTime(*t.Due))
+		}
+		lw.line("DUE", formatDateTime(*t.Due))
+	}
+
+	if t.Completed {
+		lw.line("STATUS", "COMPLETED")
+		if t.CompletedAt != nil {
+			lw.line("COMPLETED", formatDateTime(*t.CompletedAt))
+		}
+	} else {
+		lw.line("STATUS", "NEEDS-ACTION")
+	}
+
+	if t.Priority > 0 {
+		lw.line("PRIORITY", strconv.Itoa(t.Priority))
+	}
+
+	// 起点となる日時がない繰り返しは解釈できないため出力しない
+	if t.RRule != "" && t.Due != nil {
+		lw.line("RRULE", t.RRule)
+	}
+
+	if len(t.Categories) > 0 {
+		var categories = make([]string, 0, len(t.Categories))
+		for _, c := range t.Categories {
+			categories = append(categories, escapeText(c))
+		}
+		lw.line("CATEGORIES", strings.Join(categories, ","))
+	}
+
+	lw.line("END", "VTODO")
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// TEXT 型の値をエスケープする (RFC 5545 3.3.11)
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case ';':
+			b.WriteString(`\;`)
+		case ',':
+			b.WriteString(`\,`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// 1行ずつ折り返して書き出す
+// 最初に発生したエラーを保持し、以降の書き出しは行わない
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(name, value string) {
+	if lw.err != nil {
+		return
+	}
+
+	_, lw.err = lw.w.WriteString(fold(name + ":" + value))
+}
+
+// 75オクテットを超える行を折り返す (RFC 5545 3.1)
+// 継続行は空白で始まるため、2行目以降は74オクテットずつ区切る
+// マルチバイト文字の途中では区切らない
+func fold(line string) string {
+	var (
+		b     strings.Builder
+		limit = maxLineOctets
+	)
+
+	for len(line) > limit {
+		var cut = limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+
+	return b.String()
+}
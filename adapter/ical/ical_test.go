@@ -0,0 +1,132 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	var (
+		created  = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		modified = time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+		due      = time.Date(2024, 1, 31, 18, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	)
+
+	var calendar = Calendar{
+		ProdID: "-//todo-api-go//tasks//EN",
+		Name:   "Tasks",
+		Todos: []Todo{
+			{
+				UID:          "1@todo-api-go",
+				Summary:      "Buy milk, eggs; bread\\butter\nsoon",
+				Due:          &due,
+				Priority:     1,
+				RRule:        "FREQ=WEEKLY;BYDAY=MO",
+				Categories:   []string{"home", "a,b"},
+				Created:      created,
+				LastModified: modified,
+			},
+			{
+				UID:          "2@todo-api-go",
+				Summary:      "Done",
+				Completed:    true,
+				CompletedAt:  &modified,
+				RRule:        "FREQ=DAILY",
+				Created:      created,
+				LastModified: modified,
+			},
+		},
+	}
+
+	var expected = strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//todo-api-go//tasks//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Tasks",
+		"BEGIN:VTODO",
+		"UID:1@todo-api-go",
+		"DTSTAMP:20240102T090000Z",
+		"CREATED:20240101T090000Z",
+		"LAST-MODIFIED:20240102T090000Z",
+		`SUMMARY:Buy milk\, eggs\; bread\\butter\nsoon`,
+		"DTSTART:20240131T090000Z",
+		"DUE:20240131T090000Z",
+		"STATUS:NEEDS-ACTION",
+		"PRIORITY:1",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		`CATEGORIES:home,a\,b`,
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:2@todo-api-go",
+		"DTSTAMP:20240102T090000Z",
+		"CREATED:20240101T090000Z",
+		"LAST-MODIFIED:20240102T090000Z",
+		"SUMMARY:Done",
+		"STATUS:COMPLETED",
+		"COMPLETED:20240102T090000Z",
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, calendar); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != expected {
+		t.Errorf("Result: '%q' | Expected: '%q'", buf.String(), expected)
+	}
+}
+
+func TestFold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "short line", line: "SUMMARY:short"},
+		{name: "exactly 75 octets", line: "SUMMARY:" + strings.Repeat("a", 67)},
+		{name: "long ascii line", line: "SUMMARY:" + strings.Repeat("a", 200)},
+		{name: "long multibyte line", line: "SUMMARY:" + strings.Repeat("買い物", 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var folded = fold(tt.line)
+
+			if !strings.HasSuffix(folded, "\r\n") {
+				t.Fatalf("[TestCase '%s'] line is not terminated by CRLF: %q", tt.name, folded)
+			}
+
+			var lines = strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+			var unfolded strings.Builder
+			for i, l := range lines {
+				if len(l) > maxLineOctets {
+					t.Errorf("[TestCase '%s'] line %d has %d octets", tt.name, i, len(l))
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("[TestCase '%s'] line %d splits a character: %q", tt.name, i, l)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(l, " ") {
+						t.Errorf("[TestCase '%s'] continuation line %d does not start with a space", tt.name, i)
+					}
+					l = l[1:]
+				}
+				unfolded.WriteString(l)
+			}
+
+			if unfolded.String() != tt.line {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, unfolded.String(), tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+var frequencies = map[string]bool{
+	"SECONDLY": true,
+	"MINUTELY": true,
+	"HOURLY":   true,
+	"DAILY":    true,
+	"WEEKLY":   true,
+	"MONTHLY":  true,
+	"YEARLY":   true,
+}
+
+var weekdays = map[string]bool{
+	"SU": true,
+	"MO": true,
+	"TU": true,
+	"WE": true,
+	"TH": true,
+	"FR": true,
+	"SA": true,
+}
+
+// RRULE の値として解釈できるか (RFC 5545 3.3.10)
+// FREQ が必須で、各規則は1回まで、UNTIL と COUNT は同時に指定できない
+func ValidRRule(s string) bool {
+	var seen = map[string]bool{}
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" || seen[name] {
+			return false
+		}
+		seen[name] = true
+
+		if !validRulePart(name, value) {
+			return false
+		}
+	}
+
+	return seen["FREQ"] && !(seen["UNTIL"] && seen["COUNT"])
+}
+
+func validRulePart(name, value string) bool {
+	switch name {
+	case "FREQ":
+		return frequencies[value]
+	case "UNTIL":
+		if _, err := time.Parse(dateTimeFormat, value); err == nil {
+			return true
+		}
+		_, err := time.Parse("20060102", value)
+		return err == nil
+	case "COUNT", "INTERVAL":
+		n, err := strconv.Atoi(value)
+		return err == nil && n > 0
+	case "WKST":
+		return weekdays[value]
+	case "BYDAY":
+		return eachValue(value, validWeekdayNum)
+	case "BYSECOND":
+		return eachValue(value, intRange(0, 60, false))
+	case "BYMINUTE":
+		return eachValue(value, intRange(0, 59, false))
+	case "BYHOUR":
+		return eachValue(value, intRange(0, 23, false))
+	case "BYMONTHDAY":
+		return eachValue(value, intRange(1, 31, true))
+	case "BYYEARDAY", "BYSETPOS":
+		return eachValue(value, intRange(1, 366, true))
+	case "BYWEEKNO":
+		return eachValue(value, intRange(1, 53, true))
+	case "BYMONTH":
+		return eachValue(value, intRange(1, 12, false))
+	}
+
+	return false
+}
+
+func eachValue(value string, valid func(string) bool) bool {
+	for _, v := range strings.Split(value, ",") {
+		if !valid(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// min から max までの整数か (signed の場合は負の値も許可する)
+func intRange(min, max int, signed bool) func(string) bool {
+	return func(s string) bool {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return false
+		}
+		if signed && n < 0 {
+			n = -n
+		}
+
+		return n >= min && n <= max
+	}
+}
+
+// "MO"、"1MO"、"-1FR" のような曜日
+func validWeekdayNum(s string) bool {
+	if len(s) < 2 || !weekdays[s[len(s)-2:]] {
+		return false
+	}
+
+	if prefix := s[:len(s)-2]; prefix != "" {
+		return intRange(1, 53, true)(strings.TrimPrefix(prefix, "+"))
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package ical
+
+import "testing"
+
+func TestValidRRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rrule    string
+		expected bool
+	}{
+		{rrule: "FREQ=DAILY", expected: true},
+		{rrule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR", expected: true},
+		{rrule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=10", expected: true},
+		{rrule: "FREQ=YEARLY;BYMONTH=1;BYMONTHDAY=-1;UNTIL=20301231T000000Z", expected: true},
+		{rrule: "FREQ=MONTHLY;UNTIL=20301231", expected: true},
+		{rrule: "", expected: false},
+		{rrule: "INTERVAL=2", expected: false},
+		{rrule: "FREQ=FORTNIGHTLY", expected: false},
+		{rrule: "FREQ=DAILY;FREQ=WEEKLY", expected: false},
+		{rrule: "FREQ=DAILY;COUNT=3;UNTIL=20301231", expected: false},
+		{rrule: "FREQ=DAILY;COUNT=0", expected: false},
+		{rrule: "FREQ=WEEKLY;BYDAY=XX", expected: false},
+		{rrule: "FREQ=YEARLY;BYMONTH=13", expected: false},
+		{rrule: "FREQ=DAILY;X-NAME=1", expected: false},
+		{rrule: "FREQ=DAILY\r\nSUMMARY:injected", expected: false},
+	}
+
+	for _, tt := range tests {
+		if result := ValidRRule(tt.rrule); result != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.rrule, result, tt.expected)
+		}
+	}
+}
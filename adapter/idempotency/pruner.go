@@ -0,0 +1,67 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/health"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// 期限切れのキーを削除する間隔
+const defaultPruneInterval = time.Hour
+
+// 期限切れの Idempotency-Key を定期的に削除する
+// 期限切れのキーは同じキーのリクエストが来るまで上書きされず、テーブルに残り続けるため
+type Pruner struct {
+	repo     domain.IdempotencyRepository
+	log      logger.Logger
+	interval time.Duration
+}
+
+func NewPruner(repo domain.IdempotencyRepository, log logger.Logger) Pruner {
+	return Pruner{
+		repo:     repo,
+		log:      log,
+		interval: defaultPruneInterval,
+	}
+}
+
+// コンテキストがキャンセルされるまで、間隔を空けて期限切れのキーを削除し続ける
+func (p Pruner) Run(ctx context.Context) {
+	p.log.Infof("Starting idempotency key pruner")
+
+	var ticker = time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		health.Progress(ctx)
+
+		// 失敗しても次の間隔で再試行するため、ログを出力するだけにする
+		n, err := p.RunOnce(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			p.log.WithError(err).Errorf("error pruning idempotency keys")
+		case n > 0:
+			p.log.WithFields(logger.Fields{"count": n}).Infof("pruned idempotency keys")
+		}
+
+		select {
+		case <-ctx.Done():
+			p.log.Infof("Idempotency key pruner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run が進捗を記録する最長の間隔
+func (p Pruner) ProgressInterval() time.Duration {
+	return p.interval
+}
+
+// 期限切れのキーを削除し、削除した件数を返却する
+func (p Pruner) RunOnce(ctx context.Context) (int64, error) {
+	return p.repo.Prune(ctx, time.Now())
+}
@@ -0,0 +1,90 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+)
+
+type mockIdempotencyRepo struct {
+	domain.IdempotencyRepository
+	expiresAt []time.Time
+	err       error
+}
+
+func (m *mockIdempotencyRepo) Prune(_ context.Context, before time.Time) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	var (
+		n    int64
+		kept []time.Time
+	)
+	for _, expiresAt := range m.expiresAt {
+		if expiresAt.Before(before) {
+			n++
+			continue
+		}
+		kept = append(kept, expiresAt)
+	}
+	m.expiresAt = kept
+
+	return n, nil
+}
+
+func TestPruner_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	var now = time.Now()
+
+	tests := []struct {
+		name          string
+		repo          *mockIdempotencyRepo
+		expected      int64
+		expectedKept  int
+		expectedError bool
+	}{
+		{
+			name:         "Deletes expired keys",
+			repo:         &mockIdempotencyRepo{expiresAt: []time.Time{now.Add(-time.Hour), now.Add(-time.Minute), now.Add(time.Hour)}},
+			expected:     2,
+			expectedKept: 1,
+		},
+		{
+			name:         "Nothing expired",
+			repo:         &mockIdempotencyRepo{expiresAt: []time.Time{now.Add(time.Hour)}},
+			expected:     0,
+			expectedKept: 1,
+		},
+		{
+			name:          "Repository error",
+			repo:          &mockIdempotencyRepo{err: errors.New("connection refused")},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			n, err := NewPruner(tt.repo, log.LoggerMock{}).RunOnce(context.Background())
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected error: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if n != tt.expected {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, n, tt.expected)
+			}
+
+			if len(tt.repo.expiresAt) != tt.expectedKept {
+				t.Errorf("[TestCase '%s'] Kept: '%v' | Expected: '%v'", tt.name, len(tt.repo.expiresAt), tt.expectedKept)
+			}
+		})
+	}
+}
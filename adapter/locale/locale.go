@@ -0,0 +1,71 @@
+package locale
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+)
+
+type contextKey string
+
+const keyLanguage contextKey = "LanguageContextKey"
+
+// 対応している言語
+const (
+	English  = "en"
+	Japanese = "ja"
+)
+
+// 対応している言語の一覧
+// 先頭の言語が、Accept-Language に一致する言語がない場合のフォールバックになる
+// 言語を追加する場合はここに追加し、バリデーターとレスポンスの翻訳を登録する
+var Supported = []string{
+	English,
+	Japanese,
+}
+
+var matcher = newMatcher(Supported)
+
+func newMatcher(supported []string) language.Matcher {
+	var tags = make([]language.Tag, 0, len(supported))
+	for _, lang := range supported {
+		tags = append(tags, language.Make(lang))
+	}
+
+	return language.NewMatcher(tags)
+}
+
+// Accept-Language ヘッダーから、対応している言語の中で最も優先度の高いものを返却する
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default()
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default()
+	}
+
+	return Supported[index]
+}
+
+// フォールバックに使用する言語を返却する
+func Default() string {
+	return Supported[0]
+}
+
+// コンテキストに言語をセットし返却する
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, keyLanguage, lang)
+}
+
+// コンテキストにセットされた言語を返却する
+// セットされていない場合はフォールバックの言語を返却する
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(keyLanguage).(string); ok && lang != "" {
+		return lang
+	}
+
+	return Default()
+}
@@ -0,0 +1,72 @@
+package locale
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{
+			name:           "Empty header falls back to default",
+			acceptLanguage: "",
+			expected:       English,
+		},
+		{
+			name:           "Japanese",
+			acceptLanguage: "ja",
+			expected:       Japanese,
+		},
+		{
+			name:           "Japanese with region",
+			acceptLanguage: "ja-JP,ja;q=0.9,en-US;q=0.8",
+			expected:       Japanese,
+		},
+		{
+			name:           "Quality values are respected",
+			acceptLanguage: "ja;q=0.3,en;q=0.8",
+			expected:       English,
+		},
+		{
+			name:           "Unsupported language falls back to default",
+			acceptLanguage: "fr-FR",
+			expected:       English,
+		},
+		{
+			name:           "Unsupported language before a supported one",
+			acceptLanguage: "fr-FR,ja;q=0.5",
+			expected:       Japanese,
+		},
+		{
+			name:           "Malformed header falls back to default",
+			acceptLanguage: ";;;",
+			expected:       English,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.acceptLanguage); got != tt.expected {
+				t.Errorf("[TestCase '%s'] Got: '%v' | Want: '%v'", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	if got := FromContext(context.Background()); got != English {
+		t.Errorf("Got: '%v' | Want: '%v'", got, English)
+	}
+
+	if got := FromContext(WithLanguage(context.Background(), Japanese)); got != Japanese {
+		t.Errorf("Got: '%v' | Want: '%v'", got, Japanese)
+	}
+}
@@ -1,5 +1,10 @@
 package logger
 
+import (
+	"context"
+	"sync"
+)
+
 type Logger interface {
 	Infof(format string, args ...interface{})
 	Warnf(format string, args ...interface{})
@@ -7,6 +12,40 @@ type Logger interface {
 	Fatalln(args ...interface{})
 	WithFields(keyValues Fields) Logger
 	WithError(err error) Logger
+	// コンテキストから取り出した項目 (RegisterContextFields を参照) を付けたロガーを返却する
+	WithContext(ctx context.Context) Logger
 }
 
 type Fields map[string]interface{}
+
+// コンテキストからログに付ける項目を取り出す関数
+type ContextFields func(context.Context) Fields
+
+var (
+	contextFieldsMu sync.RWMutex
+	contextFields   []ContextFields
+)
+
+// WithContext で項目を取り出す関数を登録する
+// サーバーを起動する前に呼び出す
+func RegisterContextFields(fn ContextFields) {
+	contextFieldsMu.Lock()
+	defer contextFieldsMu.Unlock()
+
+	contextFields = append(contextFields, fn)
+}
+
+// 登録された関数でコンテキストから項目を取り出す
+func FieldsFromContext(ctx context.Context) Fields {
+	contextFieldsMu.RLock()
+	defer contextFieldsMu.RUnlock()
+
+	var fields = Fields{}
+	for _, fn := range contextFields {
+		for key, value := range fn(ctx) {
+			fields[key] = value
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+)
+
+// database/sql のコネクションプールの状態を出力する
+type dbStatsCollector struct {
+	stats func() sql.DBStats
+}
+
+func NewDBStatsCollector(stats func() sql.DBStats) Collector {
+	return dbStatsCollector{stats: stats}
+}
+
+func (c dbStatsCollector) Collect(_ context.Context) ([]Family, error) {
+	var s = c.stats()
+
+	var family = func(name, help, typ string, value float64) Family {
+		return Family{Name: name, Help: help, Type: typ, Samples: []Sample{{Value: value}}}
+	}
+
+	return []Family{
+		family("db_max_open_connections", "Maximum number of open connections to the database.", TypeGauge, float64(s.MaxOpenConnections)),
+		family("db_open_connections", "Number of established connections, both in use and idle.", TypeGauge, float64(s.OpenConnections)),
+		family("db_in_use_connections", "Number of connections currently in use.", TypeGauge, float64(s.InUse)),
+		family("db_idle_connections", "Number of idle connections.", TypeGauge, float64(s.Idle)),
+		family("db_wait_count_total", "Total number of connections waited for.", TypeCounter, float64(s.WaitCount)),
+		family("db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", TypeCounter, s.WaitDuration.Seconds()),
+		family("db_max_idle_closed_total", "Total number of connections closed due to the idle limit.", TypeCounter, float64(s.MaxIdleClosed)),
+		family("db_max_idle_time_closed_total", "Total number of connections closed due to the idle time limit.", TypeCounter, float64(s.MaxIdleTimeClosed)),
+		family("db_max_lifetime_closed_total", "Total number of connections closed due to the lifetime limit.", TypeCounter, float64(s.MaxLifetimeClosed)),
+	}, nil
+}
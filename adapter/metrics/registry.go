@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// メトリクスの種類 (Prometheus の TYPE)
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// Prometheus のテキスト形式の Content-Type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type (
+	// 出力する時にメトリクスの値を読み取る
+	Collector interface {
+		Collect(context.Context) ([]Family, error)
+	}
+
+	// 同じ名前のメトリクスの集まり
+	Family struct {
+		Name    string
+		Help    string
+		Type    string
+		Samples []Sample
+	}
+
+	Sample struct {
+		// 名前に付ける接尾辞 (ヒストグラムの "_bucket" など)
+		Suffix string
+		Labels []Label
+		Value  float64
+	}
+
+	Label struct {
+		Name  string
+		Value string
+	}
+)
+
+// メトリクスを登録し、Prometheus のテキスト形式で出力する
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// 登録した順に出力する
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors = append(r.collectors, c)
+}
+
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	var c = &CounterVec{vec: newVec(name, help, labels)}
+	r.Register(c)
+	return c
+}
+
+func (r *Registry) Gauge(name, help string, labels ...string) *GaugeVec {
+	var g = &GaugeVec{vec: newVec(name, help, labels)}
+	r.Register(g)
+	return g
+}
+
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	var h = &HistogramVec{vec: newVec(name, help, labels), buckets: buckets}
+	r.Register(h)
+	return h
+}
+
+// すべてのメトリクスを出力する
+// 読み取りに失敗したコレクターのメトリクスは出力せずに続け、最後にまとめてエラーを返却する
+func (r *Registry) Write(ctx context.Context, w io.Writer) error {
+	r.mu.Lock()
+	var collectors = append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	var (
+		bw   = bufio.NewWriter(w)
+		errs []error
+	)
+
+	for _, c := range collectors {
+		families, err := c.Collect(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, f := range families {
+			writeFamily(bw, f)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
+
+func writeFamily(w *bufio.Writer, f Family) {
+	w.WriteString("# HELP " + f.Name + " " + escapeHelp(f.Help) + "\n")
+	w.WriteString("# TYPE " + f.Name + " " + f.Type + "\n")
+
+	for _, s := range f.Samples {
+		w.WriteString(f.Name + s.Suffix)
+
+		if len(s.Labels) > 0 {
+			w.WriteByte('{')
+			for i, l := range s.Labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.WriteString(l.Name + `="` + escapeLabelValue(l.Value) + `"`)
+			}
+			w.WriteByte('}')
+		}
+
+		w.WriteString(" " + formatValue(s.Value) + "\n")
+	}
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
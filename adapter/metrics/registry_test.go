@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+type failingCollector struct{}
+
+func (f failingCollector) Collect(_ context.Context) ([]Family, error) {
+	return nil, errors.New("collect failed")
+}
+
+func TestRegistry_Write(t *testing.T) {
+	t.Parallel()
+
+	var registry = NewRegistry()
+
+	var requests = registry.Counter("http_requests_total", "Total number of HTTP requests.", "route", "status")
+	requests.Inc("/v1/tasks", "200")
+	requests.Inc("/v1/tasks", "200")
+	requests.Inc(`/v1/"quoted"`, "404")
+
+	var inFlight = registry.Gauge("http_requests_in_flight", "In-flight requests.\nPer route.", "route")
+	inFlight.Inc("/v1/events")
+	inFlight.Inc("/v1/events")
+	inFlight.Dec("/v1/events")
+
+	registry.Register(failingCollector{})
+
+	var duration = registry.Histogram("http_request_duration_seconds", "Latency.", []float64{0.1, 1}, "route")
+	duration.Observe(0.05, "/v1/tasks")
+	duration.Observe(0.5, "/v1/tasks")
+	duration.Observe(3, "/v1/tasks")
+
+	var b bytes.Buffer
+	err := registry.Write(context.Background(), &b)
+
+	var expected = `# HELP http_requests_total Total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{route="/v1/\"quoted\"",status="404"} 1
+http_requests_total{route="/v1/tasks",status="200"} 2
+# HELP http_requests_in_flight In-flight requests.\nPer route.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight{route="/v1/events"} 1
+# HELP http_request_duration_seconds Latency.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{route="/v1/tasks",le="0.1"} 1
+http_request_duration_seconds_bucket{route="/v1/tasks",le="1"} 2
+http_request_duration_seconds_bucket{route="/v1/tasks",le="+Inf"} 3
+http_request_duration_seconds_sum{route="/v1/tasks"} 3.55
+http_request_duration_seconds_count{route="/v1/tasks"} 3
+`
+
+	if result := b.String(); result != expected {
+		t.Errorf("[TestCase 'Write'] Result: '%v' | Expected: '%v'", result, expected)
+	}
+
+	// 読み取れなかったコレクターのエラーを返却する
+	if err == nil || err.Error() != "collect failed" {
+		t.Errorf("[TestCase 'Write error'] Result: '%v' | Expected: 'collect failed'", err)
+	}
+}
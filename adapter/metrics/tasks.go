@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// タスクの件数を出力する
+// 出力するたびに集計するため、クエリには ctxTimeout を適用する
+type taskCollector struct {
+	repo       domain.TaskRepository
+	ctxTimeout time.Duration
+}
+
+func NewTaskCollector(repo domain.TaskRepository, t time.Duration) Collector {
+	return taskCollector{
+		repo:       repo,
+		ctxTimeout: t,
+	}
+}
+
+func (c taskCollector) Collect(ctx context.Context) ([]Family, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.ctxTimeout)
+	defer cancel()
+
+	counts, err := c.repo.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Family{
+		{
+			Name:    "todo_tasks_open",
+			Help:    "Number of tasks that are not completed.",
+			Type:    TypeGauge,
+			Samples: []Sample{{Value: float64(counts.Open)}},
+		},
+		{
+			Name:    "todo_tasks_overdue",
+			Help:    "Number of tasks that are not completed and past their due date.",
+			Type:    TypeGauge,
+			Samples: []Sample{{Value: float64(counts.Overdue)}},
+		},
+	}, nil
+}
@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ラベルの値の組み合わせごとに値を保持する
+type vec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labels []Label
+	value  float64
+	// ヒストグラムのバケットごとの件数 (累積しない)
+	counts []uint64
+	count  uint64
+}
+
+func newVec(name, help string, labels []string) vec {
+	return vec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*series),
+	}
+}
+
+// ラベルの値に対応する系列を返却する (なければ作成する)
+// 呼び出し元で mu をロックする
+func (v *vec) get(values []string) *series {
+	if len(values) != len(v.labels) {
+		panic("metrics: " + v.name + " expects labels " + strings.Join(v.labels, ", "))
+	}
+
+	var key = strings.Join(values, "\xff")
+	if s, ok := v.series[key]; ok {
+		return s
+	}
+
+	var s = &series{labels: make([]Label, len(values))}
+	for i, value := range values {
+		s.labels[i] = Label{Name: v.labels[i], Value: value}
+	}
+	v.series[key] = s
+
+	return s
+}
+
+// ラベルの値の順に並べた系列を返却する
+// 呼び出し元で mu をロックする
+func (v *vec) sorted() []*series {
+	var keys = make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var s = make([]*series, 0, len(keys))
+	for _, key := range keys {
+		s = append(s, v.series[key])
+	}
+
+	return s
+}
+
+func (v *vec) collect(typ string) []Family {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var f = Family{Name: v.name, Help: v.help, Type: typ}
+	for _, s := range v.sorted() {
+		f.Samples = append(f.Samples, Sample{Labels: s.labels, Value: s.value})
+	}
+
+	return []Family{f}
+}
+
+// 増えるだけの値
+type CounterVec struct {
+	vec
+}
+
+func (c *CounterVec) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+func (c *CounterVec) Add(delta float64, labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.get(labels).value += delta
+}
+
+func (c *CounterVec) Collect(_ context.Context) ([]Family, error) {
+	return c.collect(TypeCounter), nil
+}
+
+// 増減する値
+type GaugeVec struct {
+	vec
+}
+
+func (g *GaugeVec) Inc(labels ...string) {
+	g.Add(1, labels...)
+}
+
+func (g *GaugeVec) Dec(labels ...string) {
+	g.Add(-1, labels...)
+}
+
+func (g *GaugeVec) Add(delta float64, labels ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.get(labels).value += delta
+}
+
+func (g *GaugeVec) Set(value float64, labels ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.get(labels).value = value
+}
+
+func (g *GaugeVec) Collect(_ context.Context) ([]Family, error) {
+	return g.collect(TypeGauge), nil
+}
+
+// 観測した値の分布
+// buckets は各バケットの上限を昇順に並べたもの (+Inf のバケットは自動で追加する)
+type HistogramVec struct {
+	vec
+	buckets []float64
+}
+
+func (h *HistogramVec) Observe(value float64, labels ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var s = h.get(labels)
+	if s.counts == nil {
+		s.counts = make([]uint64, len(h.buckets))
+	}
+
+	for i, upper := range h.buckets {
+		if value <= upper {
+			s.counts[i]++
+			break
+		}
+	}
+	s.value += value
+	s.count++
+}
+
+func (h *HistogramVec) Collect(_ context.Context) ([]Family, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var f = Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+	for _, s := range h.sorted() {
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			f.Samples = append(f.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: withLabel(s.labels, "le", formatValue(upper)),
+				Value:  float64(cumulative),
+			})
+		}
+
+		f.Samples = append(f.Samples,
+			Sample{Suffix: "_bucket", Labels: withLabel(s.labels, "le", formatValue(math.Inf(1))), Value: float64(s.count)},
+			Sample{Suffix: "_sum", Labels: s.labels, Value: s.value},
+			Sample{Suffix: "_count", Labels: s.labels, Value: float64(s.count)},
+		)
+	}
+
+	return []Family{f}, nil
+}
+
+func withLabel(labels []Label, name, value string) []Label {
+	var l = make([]Label, 0, len(labels)+1)
+	l = append(l, labels...)
+	return append(l, Label{Name: name, Value: value})
+}
+
+// HTTP のレイテンシーに使用するバケット (秒)
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
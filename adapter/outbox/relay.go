@@ -0,0 +1,177 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/health"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/pkg/errors"
+)
+
+const (
+	// 1回に読み取るイベントの件数
+	defaultBatchSize = 100
+	// イベントがない場合に次に確認するまでの間隔
+	defaultPollInterval = time.Second
+	// ハンドラーが失敗した場合に再試行するまでの最大の間隔
+	defaultMaxRetryInterval = time.Minute
+	// 発行済みのイベントを残しておく期間 (イベントストリームの再開に使用する)
+	defaultRetention = 7 * 24 * time.Hour
+	// 保持期間を過ぎたイベントを削除する間隔
+	defaultPruneInterval = time.Hour
+)
+
+// outbox のイベントを書き込んだ順に読み取り、登録されたハンドラーに渡す
+//
+// イベントはハンドラーの処理と同じトランザクションで発行済みにするため、
+// DBに書き込むだけのハンドラーは1回だけ実行したのと同じ結果になる
+// それ以外のハンドラーには、コミットの前に停止した場合に同じイベントが再度渡される (at-least-once)
+type Relay struct {
+	repo     domain.OutboxRepository
+	log      logger.Logger
+	handlers map[string][]domain.EventHandler
+
+	batchSize        int
+	pollInterval     time.Duration
+	maxRetryInterval time.Duration
+	retention        time.Duration
+	pruneInterval    time.Duration
+}
+
+func NewRelay(repo domain.OutboxRepository, log logger.Logger) Relay {
+	return Relay{
+		repo:     repo,
+		log:      log,
+		handlers: make(map[string][]domain.EventHandler),
+
+		batchSize:        defaultBatchSize,
+		pollInterval:     defaultPollInterval,
+		maxRetryInterval: defaultMaxRetryInterval,
+		retention:        defaultRetention,
+		pruneInterval:    defaultPruneInterval,
+	}
+}
+
+// イベントの種類ごとにハンドラーを登録する
+// 1つのイベントに複数のハンドラーがある場合は登録した順に実行する
+func (r Relay) Subscribe(handler domain.EventHandler, eventTypes ...string) Relay {
+	for _, eventType := range eventTypes {
+		r.handlers[eventType] = append(r.handlers[eventType], handler)
+	}
+
+	return r
+}
+
+// コンテキストがキャンセルされるまでイベントを発行し続ける
+// 保持期間を過ぎた発行済みのイベントも定期的に削除する
+func (r Relay) Run(ctx context.Context) {
+	r.log.Infof("Starting outbox relay")
+
+	var (
+		timer = time.NewTimer(0)
+		prune = time.NewTicker(r.pruneInterval)
+		retry = r.pollInterval
+	)
+	defer timer.Stop()
+	defer prune.Stop()
+
+	for {
+		health.Progress(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.log.Infof("Outbox relay stopped")
+			return
+		case <-prune.C:
+			r.prune(ctx)
+			continue
+		case <-timer.C:
+		}
+
+		n, err := r.RunOnce(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			r.log.WithError(err).Errorf("error publishing outbox events")
+
+			// 失敗したイベントより後のイベントは発行しないため、間隔を空けながら同じイベントを再試行する
+			timer.Reset(retry)
+			retry = min(retry*2, r.maxRetryInterval)
+			continue
+		case n == r.batchSize:
+			// 読み取った件数が上限に達した場合は、残りがあるとみなしてすぐに続ける
+			timer.Reset(0)
+		default:
+			timer.Reset(r.pollInterval)
+		}
+
+		retry = r.pollInterval
+	}
+}
+
+// Run が進捗を記録する最長の間隔 (再試行の間隔の上限)
+func (r Relay) ProgressInterval() time.Duration {
+	return max(r.pollInterval, r.maxRetryInterval)
+}
+
+// 未発行のイベントを書き込んだ順にハンドラーに渡し、発行済みにした件数を返却する
+// ハンドラーが失敗した場合は、それより前のイベントだけを発行済みにしてエラーを返却する
+func (r Relay) RunOnce(ctx context.Context) (int, error) {
+	var (
+		published  []domain.EventID
+		handlerErr error
+	)
+
+	err := r.repo.WithTransaction(ctx, func(ctxTx context.Context) error {
+		events, err := r.repo.FindUnpublished(ctxTx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			// 失敗したハンドラーの書き込みだけを取り消すため、イベントごとにセーブポイントを作成する
+			if err := r.repo.WithTransaction(ctxTx, func(ctxSp context.Context) error {
+				return r.dispatch(ctxSp, event)
+			}); err != nil {
+				handlerErr = errors.Wrapf(err, "error handling event %d (%s)", event.ID, event.Type)
+				break
+			}
+
+			published = append(published, event.ID)
+		}
+
+		return r.repo.MarkPublished(ctxTx, published...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(published), handlerErr
+}
+
+// 保持期間を過ぎた発行済みのイベントを削除する
+// 失敗しても次の間隔で再試行するため、ログを出力するだけにする
+func (r Relay) prune(ctx context.Context) {
+	n, err := r.repo.Prune(ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.log.WithError(err).Errorf("error pruning outbox events")
+		}
+		return
+	}
+
+	if n > 0 {
+		r.log.WithFields(logger.Fields{"count": n}).Infof("pruned outbox events")
+	}
+}
+
+func (r Relay) dispatch(ctx context.Context, event domain.Event) error {
+	for _, handler := range r.handlers[event.Type] {
+		if err := handler.Handle(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
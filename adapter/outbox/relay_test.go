@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+)
+
+type mockOutboxRepo struct {
+	domain.OutboxRepository
+	events    []domain.Event
+	published *[]domain.EventID
+}
+
+func (m mockOutboxRepo) FindUnpublished(_ context.Context, limit int) ([]domain.Event, error) {
+	if len(m.events) > limit {
+		return m.events[:limit], nil
+	}
+
+	return m.events, nil
+}
+
+func (m mockOutboxRepo) MarkPublished(_ context.Context, ids ...domain.EventID) error {
+	*m.published = append(*m.published, ids...)
+	return nil
+}
+
+func (m mockOutboxRepo) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+// 受け取ったイベントのIDを記録し、指定したIDで失敗するハンドラー
+type mockHandler struct {
+	handled *[]domain.EventID
+	failOn  domain.EventID
+}
+
+func (m mockHandler) Handle(_ context.Context, event domain.Event) error {
+	if event.ID == m.failOn {
+		return errors.New("handler error")
+	}
+
+	*m.handled = append(*m.handled, event.ID)
+	return nil
+}
+
+func TestRelay_RunOnce(t *testing.T) {
+	var events = []domain.Event{
+		{ID: 1, Type: domain.EventTaskCreated, TaskID: 1},
+		{ID: 2, Type: domain.EventTaskUpdated, TaskID: 1},
+		{ID: 3, Type: domain.EventTaskDeleted, TaskID: 1},
+		{ID: 4, Type: domain.EventTaskCreated, TaskID: 2},
+	}
+
+	tests := []struct {
+		name              string
+		failOn            domain.EventID
+		expectedHandled   []domain.EventID
+		expectedPublished []domain.EventID
+		expectedError     bool
+	}{
+		{
+			name:              "All events are handled in order",
+			expectedHandled:   []domain.EventID{1, 2, 4},
+			expectedPublished: []domain.EventID{1, 2, 3, 4},
+		},
+		{
+			name:              "Events after a failing one are not published",
+			failOn:            2,
+			expectedHandled:   []domain.EventID{1},
+			expectedPublished: []domain.EventID{1},
+			expectedError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				handled   []domain.EventID
+				published []domain.EventID
+				handler   = mockHandler{handled: &handled, failOn: tt.failOn}
+				relay     = NewRelay(mockOutboxRepo{events: events, published: &published}, log.LoggerMock{}).
+						Subscribe(handler, domain.EventTaskCreated, domain.EventTaskUpdated)
+			)
+
+			n, err := relay.RunOnce(context.Background())
+			if (err != nil) != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected error: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if n != len(tt.expectedPublished) {
+				t.Errorf("[TestCase '%s'] Result: '%d' | Expected: '%d'", tt.name, n, len(tt.expectedPublished))
+			}
+
+			if !reflect.DeepEqual(handled, tt.expectedHandled) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, handled, tt.expectedHandled)
+			}
+
+			if !reflect.DeepEqual(published, tt.expectedPublished) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, published, tt.expectedPublished)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package presenter
+
+import (
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type calendarFeedPresenter struct{}
+
+func NewCalendarFeedPresenter() usecase.CalendarFeedPresenter {
+	return calendarFeedPresenter{}
+}
+
+func (c calendarFeedPresenter) Output(tasks []domain.Task) []usecase.CalendarTaskOutput {
+	var o = make([]usecase.CalendarTaskOutput, 0, len(tasks))
+	for _, task := range tasks {
+		o = append(o, usecase.CalendarTaskOutput{
+			ID:          task.ID,
+			Title:       task.Title,
+			DueDate:     task.DueDate,
+			Tags:        task.Tags,
+			Completed:   task.Completed,
+			CompletedAt: task.CompletedAt,
+			Priority:    task.Priority,
+			Recurrence:  task.Recurrence,
+			CreatedAt:   task.CreatedAt,
+			UpdatedAt:   task.UpdatedAt,
+		})
+	}
+
+	return o
+}
@@ -15,8 +15,17 @@ func NewCreateTaskPresenter() usecase.CreateTaskPresenter {
 
 func (t createTaskPresenter) Output(task domain.Task) usecase.CreateTaskOutput {
 	return usecase.CreateTaskOutput{
-		Title:     task.Title,
-		CreatedAt: task.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: task.UpdatedAt.Format(time.RFC3339),
+		ID:          task.ID,
+		Title:       task.Title,
+		DueDate:     formatOptionalTime(task.DueDate),
+		Tags:        tagsOrEmpty(task.Tags),
+		Completed:   task.Completed,
+		Priority:    task.Priority,
+		Recurrence:  task.Recurrence,
+		Contexts:    task.Contexts,
+		Extensions:  usecase.NewExtensions(task.Extensions),
+		CompletedAt: formatOptionalTime(task.CompletedAt),
+		CreatedAt:   task.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   task.UpdatedAt.Format(time.RFC3339),
 	}
 }
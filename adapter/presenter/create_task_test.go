@@ -10,6 +10,8 @@ import (
 )
 
 func Test_createTaskPresenter_Output(t *testing.T) {
+	var dueDate = time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+
 	type args struct {
 		task domain.Task
 	}
@@ -21,10 +23,11 @@ func Test_createTaskPresenter_Output(t *testing.T) {
 	}{
 		{
 			name: "Create task output",
-	
+
 			// 入力値
 			args: args{
 				task: domain.Task{
+					ID:        1,
 					Title:     "Testing",
 					CreatedAt: time.Time{},
 					UpdatedAt: time.Time{},
@@ -33,7 +36,32 @@ func Test_createTaskPresenter_Output(t *testing.T) {
 
 			// 期待値
 			want: usecase.CreateTaskOutput{
+				ID:        1,
+				Title:     "Testing",
+				Tags:      []string{},
+				CreatedAt: "0001-01-01T00:00:00Z",
+				UpdatedAt: "0001-01-01T00:00:00Z",
+			},
+		},
+		{
+			name: "Create task output with due date and tags",
+			args: args{
+				task: domain.Task{
+					ID:        2,
+					Title:     "Testing",
+					DueDate:   &dueDate,
+					Tags:      []string{"work", "home"},
+					Completed: true,
+					CreatedAt: time.Time{},
+					UpdatedAt: time.Time{},
+				},
+			},
+			want: usecase.CreateTaskOutput{
+				ID:        2,
 				Title:     "Testing",
+				DueDate:   "2024-01-10T09:00:00Z",
+				Tags:      []string{"work", "home"},
+				Completed: true,
 				CreatedAt: "0001-01-01T00:00:00Z",
 				UpdatedAt: "0001-01-01T00:00:00Z",
 			},
@@ -48,4 +76,4 @@ func Test_createTaskPresenter_Output(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
@@ -16,8 +16,15 @@ func (a findAllTaskPresenter) Output(tasks []domain.Task) []usecase.FindAllTaskO
 
 	for _, task := range tasks {
 		o = append(o, usecase.FindAllTaskOutput{
-			ID:    task.ID,
-			Title: task.Title,
+			ID:         task.ID,
+			Title:      task.Title,
+			DueDate:    formatOptionalTime(task.DueDate),
+			Tags:       tagsOrEmpty(task.Tags),
+			Completed:  task.Completed,
+			Priority:   task.Priority,
+			Recurrence: task.Recurrence,
+			Contexts:   task.Contexts,
+			Extensions: usecase.NewExtensions(task.Extensions),
 		})
 	}
 
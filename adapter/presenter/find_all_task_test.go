@@ -35,10 +35,12 @@ func Test_findAllTaskPresenter_Output(t *testing.T) {
 				{
 					ID:    1,
 					Title: "Task_1",
+					Tags:  []string{},
 				},
 				{
 					ID:    2,
 					Title: "Task_2",
+					Tags:  []string{},
 				},
 			},
 		},
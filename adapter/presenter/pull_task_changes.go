@@ -0,0 +1,47 @@
+package presenter
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type pullTaskChangesPresenter struct {
+	task usecase.UpdateTaskPresenter
+}
+
+func NewPullTaskChangesPresenter() usecase.PullTaskChangesPresenter {
+	return pullTaskChangesPresenter{
+		task: NewUpdateTaskPresenter(),
+	}
+}
+
+func (p pullTaskChangesPresenter) Output(
+	changes []domain.TaskChange,
+	token domain.ChangeSeq,
+	hasMore bool,
+) usecase.PullTaskChangesOutput {
+	var output = usecase.PullTaskChangesOutput{
+		Changes: make([]usecase.UpdateTaskOutput, 0),
+		Deleted: make([]usecase.DeletedTaskOutput, 0),
+		Token:   strconv.FormatUint(uint64(token), 10),
+		HasMore: hasMore,
+	}
+
+	for _, change := range changes {
+		if change.Task != nil {
+			output.Changes = append(output.Changes, p.task.Output(*change.Task))
+			continue
+		}
+
+		var deleted = usecase.DeletedTaskOutput{ID: change.TaskID}
+		if change.DeletedAt != nil {
+			deleted.DeletedAt = change.DeletedAt.UTC().Format(time.RFC3339)
+		}
+		output.Deleted = append(output.Deleted, deleted)
+	}
+
+	return output
+}
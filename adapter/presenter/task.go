@@ -0,0 +1,22 @@
+package presenter
+
+import "time"
+
+// 期日や完了日時をRFC3339形式の文字列に変換する
+// 設定されていない場合は空文字を返却する
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// タグが設定されていない場合も空の配列として出力する
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
+}
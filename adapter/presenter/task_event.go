@@ -0,0 +1,39 @@
+package presenter
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+// 削除したタスクのイベントのデータ
+type deletedTask struct {
+	ID domain.TaskID `json:"id"`
+}
+
+type taskEventPresenter struct {
+	task usecase.UpdateTaskPresenter
+}
+
+// Webhook とイベントストリームで共通のイベントの形式
+func NewTaskEventPresenter() usecase.TaskEventPresenter {
+	return taskEventPresenter{
+		task: NewUpdateTaskPresenter(),
+	}
+}
+
+func (p taskEventPresenter) Output(event domain.Event) usecase.TaskEventOutput {
+	var data interface{} = deletedTask{ID: event.TaskID}
+	if event.Task != nil {
+		data = p.task.Output(*event.Task)
+	}
+
+	return usecase.TaskEventOutput{
+		ID:        strconv.FormatUint(uint64(event.ID), 10),
+		Type:      event.Type,
+		CreatedAt: event.OccurredAt.UTC().Format(time.RFC3339),
+		Data:      data,
+	}
+}
@@ -0,0 +1,31 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type updateTaskPresenter struct{}
+
+func NewUpdateTaskPresenter() usecase.UpdateTaskPresenter {
+	return updateTaskPresenter{}
+}
+
+func (t updateTaskPresenter) Output(task domain.Task) usecase.UpdateTaskOutput {
+	return usecase.UpdateTaskOutput{
+		ID:          task.ID,
+		Title:       task.Title,
+		DueDate:     formatOptionalTime(task.DueDate),
+		Tags:        tagsOrEmpty(task.Tags),
+		Completed:   task.Completed,
+		Priority:    task.Priority,
+		Recurrence:  task.Recurrence,
+		Contexts:    task.Contexts,
+		Extensions:  usecase.NewExtensions(task.Extensions),
+		CompletedAt: formatOptionalTime(task.CompletedAt),
+		CreatedAt:   task.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   task.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,65 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type webhookPresenter struct{}
+
+func NewWebhookPresenter() usecase.WebhookPresenter {
+	return webhookPresenter{}
+}
+
+func (p webhookPresenter) Output(subscription domain.WebhookSubscription) usecase.WebhookOutput {
+	return usecase.WebhookOutput{
+		ID:                  subscription.ID,
+		URL:                 subscription.URL,
+		Events:              tagsOrEmpty(subscription.Events),
+		Active:              subscription.Active,
+		ConsecutiveFailures: subscription.ConsecutiveFailures,
+		CreatedAt:           subscription.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           subscription.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+type webhookDeliveryPresenter struct{}
+
+func NewWebhookDeliveryPresenter() usecase.WebhookDeliveryPresenter {
+	return webhookDeliveryPresenter{}
+}
+
+func (p webhookDeliveryPresenter) Output(delivery domain.WebhookDelivery) usecase.WebhookDeliveryOutput {
+	var history = make([]usecase.WebhookAttemptOutput, 0, len(delivery.Log))
+	for _, attempt := range delivery.Log {
+		history = append(history, usecase.WebhookAttemptOutput{
+			Attempt:     attempt.Attempt,
+			StatusCode:  attempt.StatusCode,
+			Error:       attempt.Error,
+			DurationMS:  attempt.Duration.Milliseconds(),
+			AttemptedAt: attempt.AttemptedAt.Format(time.RFC3339),
+		})
+	}
+
+	// 次の試行日時は送信待ちの配信にだけ意味がある
+	var nextAttemptAt string
+	if delivery.Status == domain.DeliveryPending {
+		nextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+
+	return usecase.WebhookDeliveryOutput{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  nextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		History:        history,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      delivery.UpdatedAt.Format(time.RFC3339),
+	}
+}
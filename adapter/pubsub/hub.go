@@ -0,0 +1,78 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// プロセス内でイベントの書き込みを購読者に知らせる
+// DBの通知を受け取るリスナーから Publish され、イベントストリームなどのリアルタイムの処理が購読する
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[domain.AccountID]map[chan struct{}]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[domain.AccountID]map[chan struct{}]struct{}),
+	}
+}
+
+// チャネルは1件だけバッファし、受け取っていない通知があれば新しい通知は捨てる
+// 購読者は通知を受け取るたびにまだ読んでいないイベントをまとめて読み取るため、通知の件数は意味を持たない
+func (h *Hub) Subscribe(accountID domain.AccountID) (<-chan struct{}, func()) {
+	var ch = make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subscribers[accountID] == nil {
+		h.subscribers[accountID] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[accountID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			delete(h.subscribers[accountID], ch)
+			if len(h.subscribers[accountID]) == 0 {
+				delete(h.subscribers, accountID)
+			}
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// アカウントの購読者に通知する
+func (h *Hub) Publish(accountID domain.AccountID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[accountID] {
+		notify(ch)
+	}
+}
+
+// すべての購読者に通知する
+// DBとの接続が切れていた間の通知を取りこぼした可能性がある場合に使用する
+func (h *Hub) PublishAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, chs := range h.subscribers {
+		for ch := range chs {
+			notify(ch)
+		}
+	}
+}
+
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,59 @@
+package pubsub
+
+import (
+	"testing"
+)
+
+func TestHub_Publish(t *testing.T) {
+	t.Parallel()
+
+	var hub = NewHub()
+
+	account1, unsubscribe1 := hub.Subscribe(1)
+	account2, unsubscribe2 := hub.Subscribe(2)
+	defer unsubscribe2()
+
+	// 受け取る前の通知は1つにまとめられる
+	hub.Publish(1)
+	hub.Publish(1)
+
+	if n := pending(account1); n != 1 {
+		t.Errorf("[TestCase 'Publish coalesces'] Result: '%d' | Expected: '1'", n)
+	}
+
+	if n := pending(account2); n != 0 {
+		t.Errorf("[TestCase 'Publish other account'] Result: '%d' | Expected: '0'", n)
+	}
+
+	hub.PublishAll()
+
+	if n := pending(account1) + pending(account2); n != 2 {
+		t.Errorf("[TestCase 'PublishAll'] Result: '%d' | Expected: '2'", n)
+	}
+
+	// 購読をやめた後は通知されない
+	unsubscribe1()
+	unsubscribe1()
+	hub.Publish(1)
+
+	if n := pending(account1); n != 0 {
+		t.Errorf("[TestCase 'Unsubscribe'] Result: '%d' | Expected: '0'", n)
+	}
+
+	if _, ok := hub.subscribers[1]; ok {
+		t.Errorf("[TestCase 'Unsubscribe'] subscribers of account 1 were not removed")
+	}
+}
+
+// 受け取れる通知の件数を返却する
+func pending(ch <-chan struct{}) int {
+	var n int
+	for {
+		select {
+		case <-ch:
+			n++
+		default:
+			return n
+		}
+	}
+}
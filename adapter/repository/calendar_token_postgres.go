@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/pkg/errors"
+)
+
+type CalendarTokenSQL struct {
+	db SQL
+}
+
+func NewCalendarTokenSQL(db SQL) CalendarTokenSQL {
+	return CalendarTokenSQL{
+		db: db,
+	}
+}
+
+// 1行だけトークンを保持し、再発行時は上書きする
+func (c CalendarTokenSQL) Save(ctx context.Context, tokenHash string) error {
+	var query = `
+		INSERT INTO calendar_tokens (token_hash) VALUES ($1)
+		ON CONFLICT (id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()
+	`
+
+	if err := c.db.ExecuteContext(ctx, query, tokenHash); err != nil {
+		return translateError(err, "error saving calendar token")
+	}
+
+	return nil
+}
+
+func (c CalendarTokenSQL) Verify(ctx context.Context, tokenHash string) error {
+	var query = "SELECT token_hash FROM calendar_tokens WHERE token_hash = $1"
+
+	var found string
+	err := c.db.QueryRowContext(ctx, query, tokenHash).Scan(&found)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrCalendarTokenInvalid
+	case err != nil:
+		return translateError(err, "error fetching calendar token")
+	}
+
+	return nil
+}
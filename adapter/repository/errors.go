@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+// DBドライバーのエラーを種別付きのドメインエラーに変換する
+// 種別を判別できない場合はメッセージでラップしたエラーを返却する
+func translateError(err error, msg string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			return domain.WrapError(domain.KindConflict, err, msg)
+		case "check_violation", "not_null_violation", "string_data_right_truncation":
+			return domain.WrapError(domain.KindValidation, err, msg)
+		case "deadlock_detected", "serialization_failure", "query_canceled":
+			return domain.WrapError(domain.KindUnavailable, err, msg)
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return domain.WrapError(domain.KindUnavailable, err, msg)
+	}
+
+	return errors.Wrap(err, msg)
+}
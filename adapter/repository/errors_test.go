@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/lib/pq"
+)
+
+func Test_translateError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected domain.ErrorKind
+	}{
+		{
+			name:     "Unique violation",
+			err:      &pq.Error{Code: "23505"},
+			expected: domain.KindConflict,
+		},
+		{
+			name:     "Check violation",
+			err:      &pq.Error{Code: "23514"},
+			expected: domain.KindValidation,
+		},
+		{
+			name:     "String too long",
+			err:      &pq.Error{Code: "22001"},
+			expected: domain.KindValidation,
+		},
+		{
+			name:     "Deadlock",
+			err:      &pq.Error{Code: "40P01"},
+			expected: domain.KindUnavailable,
+		},
+		{
+			name:     "Context deadline exceeded",
+			err:      context.DeadlineExceeded,
+			expected: domain.KindUnavailable,
+		},
+		{
+			name:     "Unknown error",
+			err:      errors.New("error"),
+			expected: domain.KindUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err, "error creating task")
+			if got := domain.KindOf(err); got != tt.expected {
+				t.Errorf("[TestCase '%s'] Got: '%v' | Want: '%v'", tt.name, got, tt.expected)
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("[TestCase '%s'] original error was not wrapped: '%v'", tt.name, err)
+			}
+		})
+	}
+}
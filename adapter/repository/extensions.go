@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/pkg/errors"
+)
+
+// 拡張項目を順番を保ったまま JSONB の配列として読み書きする
+type extensions []domain.Extension
+
+type extensionJSON struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (e extensions) Value() (driver.Value, error) {
+	var items = make([]extensionJSON, 0, len(e))
+	for _, ext := range e {
+		items = append(items, extensionJSON{Key: ext.Key, Value: ext.Value})
+	}
+
+	return json.Marshal(items)
+}
+
+func (e *extensions) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.Errorf("cannot scan %T into extensions", src)
+	}
+
+	var items []extensionJSON
+	if err := json.Unmarshal(b, &items); err != nil {
+		return errors.Wrap(err, "error decoding extensions")
+	}
+
+	*e = make(extensions, 0, len(items))
+	for _, item := range items {
+		*e = append(*e, domain.Extension{Key: item.Key, Value: item.Value})
+	}
+
+	return nil
+}
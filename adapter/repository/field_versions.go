@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/pkg/errors"
+)
+
+// 項目ごとの最後の変更を JSONB のオブジェクトとして読み書きする
+// 連番はトリガーで設定するため、書き込む時は日時だけを使用する
+type fieldVersions map[string]domain.FieldVersion
+
+type fieldVersionJSON struct {
+	UpdatedAt time.Time        `json:"updated_at"`
+	Seq       domain.ChangeSeq `json:"seq"`
+}
+
+func (v fieldVersions) Value() (driver.Value, error) {
+	var items = make(map[string]fieldVersionJSON, len(v))
+	for field, version := range v {
+		items[field] = fieldVersionJSON{UpdatedAt: version.UpdatedAt, Seq: version.Seq}
+	}
+
+	return json.Marshal(items)
+}
+
+func (v *fieldVersions) Scan(src interface{}) error {
+	var b []byte
+	switch s := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		b = s
+	case string:
+		b = []byte(s)
+	default:
+		return errors.Errorf("cannot scan %T into field versions", src)
+	}
+
+	var items map[string]fieldVersionJSON
+	if err := json.Unmarshal(b, &items); err != nil {
+		return errors.Wrap(err, "error decoding field versions")
+	}
+
+	*v = make(fieldVersions, len(items))
+	for field, item := range items {
+		(*v)[field] = domain.FieldVersion{UpdatedAt: item.UpdatedAt, Seq: item.Seq}
+	}
+
+	return nil
+}
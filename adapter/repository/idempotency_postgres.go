@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/pkg/errors"
+)
+
+type IdempotencySQL struct {
+	db SQL
+}
+
+func NewIdempotencySQL(db SQL) IdempotencySQL {
+	return IdempotencySQL{
+		db: db,
+	}
+}
+
+// キーを予約する
+// 期限切れのキー (期限を過ぎた処理中の予約を含む) は新しいリクエストで上書きする
+func (i IdempotencySQL) Reserve(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	var query = `
+		INSERT INTO idempotency_keys (account_id, key, fingerprint, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_id, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			header = NULL,
+			body = NULL,
+			expires_at = EXCLUDED.expires_at,
+			created_at = NOW()
+		WHERE idempotency_keys.expires_at < NOW()
+		RETURNING key
+	`
+
+	var key string
+	err := i.db.QueryRowContext(
+		ctx,
+		query,
+		record.AccountID,
+		record.Key,
+		record.Fingerprint,
+		record.ExpiresAt,
+	).Scan(&key)
+	switch {
+	case err == nil:
+		return record, true, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return domain.IdempotencyRecord{}, false, translateError(err, "error reserving idempotency key")
+	}
+
+	existing, err := i.find(ctx, record.AccountID, record.Key)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	return existing, false, nil
+}
+
+// 予約の期限が切れた後に別のリクエストが予約し直した場合に上書きしないよう、
+// 処理中で同じリクエスト (fingerprint) の予約だけを更新する
+func (i IdempotencySQL) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	var query = `
+		UPDATE idempotency_keys SET status_code = $1, header = $2, body = $3, expires_at = $4
+		WHERE account_id = $5 AND key = $6 AND fingerprint = $7 AND status_code IS NULL
+		RETURNING key
+	`
+
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return errors.Wrap(err, "error encoding idempotency header")
+	}
+
+	var key string
+	err = i.db.QueryRowContext(
+		ctx,
+		query,
+		record.StatusCode,
+		header,
+		record.Body,
+		record.ExpiresAt,
+		record.AccountID,
+		record.Key,
+		record.Fingerprint,
+	).Scan(&key)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrIdempotencyReservationLost
+	case err != nil:
+		return translateError(err, "error completing idempotency key")
+	}
+
+	return nil
+}
+
+// Complete と同じく、処理中で同じリクエスト (fingerprint) の予約だけを削除する
+func (i IdempotencySQL) Release(ctx context.Context, record domain.IdempotencyRecord) error {
+	var query = `
+		DELETE FROM idempotency_keys
+		WHERE account_id = $1 AND key = $2 AND fingerprint = $3 AND status_code IS NULL
+		RETURNING key
+	`
+
+	var key string
+	err := i.db.QueryRowContext(ctx, query, record.AccountID, record.Key, record.Fingerprint).Scan(&key)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrIdempotencyReservationLost
+	case err != nil:
+		return translateError(err, "error releasing idempotency key")
+	}
+
+	return nil
+}
+
+// 期限切れのキーは Reserve で上書きされるまで残るため、定期的に削除する
+func (i IdempotencySQL) Prune(ctx context.Context, before time.Time) (int64, error) {
+	var query = `
+		WITH deleted AS (
+			DELETE FROM idempotency_keys WHERE expires_at < $1 RETURNING 1
+		)
+		SELECT COUNT(*) FROM deleted`
+
+	var n int64
+	if err := i.db.QueryRowContext(ctx, query, before).Scan(&n); err != nil {
+		return 0, translateError(err, "error pruning idempotency keys")
+	}
+
+	return n, nil
+}
+
+func (i IdempotencySQL) find(ctx context.Context, accountID domain.AccountID, key string) (domain.IdempotencyRecord, error) {
+	var query = `
+		SELECT account_id, key, fingerprint, status_code, header, body, expires_at
+		FROM idempotency_keys WHERE account_id = $1 AND key = $2
+	`
+
+	var (
+		record     domain.IdempotencyRecord
+		statusCode sql.NullInt64
+		header     []byte
+	)
+
+	if err := i.db.QueryRowContext(ctx, query, accountID, key).Scan(
+		&record.AccountID,
+		&record.Key,
+		&record.Fingerprint,
+		&statusCode,
+		&header,
+		&record.Body,
+		&record.ExpiresAt,
+	); err != nil {
+		return domain.IdempotencyRecord{}, translateError(err, "error fetching idempotency key")
+	}
+
+	record.StatusCode = int(statusCode.Int64)
+	if len(header) > 0 {
+		if err := json.Unmarshal(header, &record.Header); err != nil {
+			return domain.IdempotencyRecord{}, errors.Wrap(err, "error decoding idempotency header")
+		}
+	}
+
+	return record, nil
+}
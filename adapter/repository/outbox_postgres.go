@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type OutboxSQL struct {
+	db SQL
+}
+
+func NewOutboxSQL(db SQL) OutboxSQL {
+	return OutboxSQL{
+		db: db,
+	}
+}
+
+// イベントのSELECTで取得するカラム (scanEvent の引数と順番を合わせる)
+const eventColumns = "id, event_type, task_id, account_id, task, occurred_at"
+
+// コンテキストのトランザクションで書き込むため、タスクの変更がロールバックされた場合はイベントも残らない
+func (o OutboxSQL) Append(ctx context.Context, events ...domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var (
+		accountID, _ = domain.AccountIDFromContext(ctx)
+		values       = make([]string, 0, len(events))
+		args         = make([]interface{}, 0, len(events)*4)
+	)
+	for i, event := range events {
+		values = append(values, placeholders(i*4, 4))
+		args = append(args, event.Type, event.TaskID, accountID, (*eventTask)(event.Task))
+	}
+
+	var query = "INSERT INTO outbox (event_type, task_id, account_id, task) VALUES " + strings.Join(values, ", ")
+
+	if err := o.executor(ctx).ExecuteContext(ctx, query, args...); err != nil {
+		return translateError(err, "error appending outbox events")
+	}
+
+	return nil
+}
+
+// 行をロックするため、複数の relay が動いていても同じイベントを同時に発行しない
+// 先にロックした relay がコミットするまで待ち、その間に発行済みになった行は返却しない
+func (o OutboxSQL) FindUnpublished(ctx context.Context, limit int) ([]domain.Event, error) {
+	var query = "SELECT " + eventColumns + " FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1"
+	if _, ok := ctx.Value(KeyTransactionContext).(Tx); ok {
+		query += " FOR UPDATE"
+	}
+
+	return o.findEvents(ctx, limit, query, limit)
+}
+
+func (o OutboxSQL) MarkPublished(ctx context.Context, ids ...domain.EventID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var values = make([]int64, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, int64(id))
+	}
+
+	var query = "UPDATE outbox SET published_at = NOW() WHERE id = ANY($1)"
+
+	if err := o.executor(ctx).ExecuteContext(ctx, query, pq.Array(values)); err != nil {
+		return translateError(err, "error marking outbox events as published")
+	}
+
+	return nil
+}
+
+// IDはタスクの書き込みと同じアドバイザリーロックを取得してから採番するため、コミットした順に並ぶ
+// (_scripts/postgres/outbox.sql の trigger_assign_outbox_id を参照)
+// そのため、後からコミットされたイベントが既に返却したIDより小さいIDを持つことはない
+func (o OutboxSQL) FindAfter(ctx context.Context, accountID domain.AccountID, after domain.EventID, limit int) ([]domain.Event, error) {
+	var query = "SELECT " + eventColumns + " FROM outbox WHERE account_id = $1 AND id > $2 ORDER BY id LIMIT $3"
+
+	return o.findEvents(ctx, limit, query, accountID, after, limit)
+}
+
+func (o OutboxSQL) Bounds(ctx context.Context) (domain.EventID, domain.EventID, error) {
+	var (
+		query       = "SELECT COALESCE(MIN(id), 0), COALESCE(MAX(id), 0) FROM outbox"
+		first, last domain.EventID
+	)
+
+	if err := o.executor(ctx).QueryRowContext(ctx, query).Scan(&first, &last); err != nil {
+		return 0, 0, translateError(err, "error finding outbox bounds")
+	}
+
+	return first, last, nil
+}
+
+// 未発行のイベントは発生日時に関わらず削除しない
+func (o OutboxSQL) Prune(ctx context.Context, before time.Time) (int64, error) {
+	var query = `
+		WITH deleted AS (
+			DELETE FROM outbox WHERE published_at IS NOT NULL AND occurred_at < $1 RETURNING 1
+		)
+		SELECT COUNT(*) FROM deleted`
+
+	var n int64
+	if err := o.executor(ctx).QueryRowContext(ctx, query, before).Scan(&n); err != nil {
+		return 0, translateError(err, "error pruning outbox events")
+	}
+
+	return n, nil
+}
+
+// トランザクション内で関数を実行する (withTransaction を参照)
+func (o OutboxSQL) WithTransaction(ctx context.Context, fn func(ctxTx context.Context) error) error {
+	return withTransaction(ctx, o.db, fn)
+}
+
+// コンテキストにトランザクションがあればそれを、なければDBハンドラーを返却する
+func (o OutboxSQL) executor(ctx context.Context) executor {
+	if tx, ok := ctx.Value(KeyTransactionContext).(Tx); ok {
+		return tx
+	}
+
+	return o.db
+}
+
+// eventColumns を取得するクエリを実行し、イベントを読み取る
+func (o OutboxSQL) findEvents(ctx context.Context, limit int, query string, args ...interface{}) ([]domain.Event, error) {
+	rows, err := o.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err, "error listing outbox events")
+	}
+	defer rows.Close()
+
+	var events = make([]domain.Event, 0, limit)
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, translateError(err, "error listing outbox events")
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "error listing outbox events")
+	}
+
+	return events, nil
+}
+
+// eventColumns の順番でイベントを読み取る
+func scanEvent(row Row) (domain.Event, error) {
+	var (
+		event domain.Event
+		task  []byte
+	)
+	if err := row.Scan(
+		&event.ID,
+		&event.Type,
+		&event.TaskID,
+		&event.AccountID,
+		&task,
+		&event.OccurredAt,
+	); err != nil {
+		return domain.Event{}, err
+	}
+
+	// 削除のイベントは NULL
+	if task != nil {
+		decoded, err := decodeEventTask(task)
+		if err != nil {
+			return domain.Event{}, err
+		}
+		event.Task = &decoded
+	}
+
+	return event, nil
+}
+
+// イベント発生時点のタスクを JSONB として読み書きする
+// カラム名と同じキーを使用し、ドメインの構造体の変更が保存済みのイベントに影響しないようにする
+type eventTask domain.Task
+
+type eventTaskJSON struct {
+	ID          domain.TaskID   `json:"id"`
+	Title       string          `json:"title"`
+	DueDate     *time.Time      `json:"due_date"`
+	Tags        []string        `json:"tags"`
+	Completed   bool            `json:"completed"`
+	Priority    int             `json:"priority"`
+	Recurrence  string          `json:"recurrence"`
+	Contexts    []string        `json:"contexts"`
+	Extensions  []extensionJSON `json:"extensions"`
+	CompletedAt *time.Time      `json:"completed_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// 削除のイベントでは NULL を書き込む
+func (t *eventTask) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	var items = make([]extensionJSON, 0, len(t.Extensions))
+	for _, ext := range t.Extensions {
+		items = append(items, extensionJSON{Key: ext.Key, Value: ext.Value})
+	}
+
+	return json.Marshal(eventTaskJSON{
+		ID:          t.ID,
+		Title:       t.Title,
+		DueDate:     t.DueDate,
+		Tags:        t.Tags,
+		Completed:   t.Completed,
+		Priority:    t.Priority,
+		Recurrence:  t.Recurrence,
+		Contexts:    t.Contexts,
+		Extensions:  items,
+		CompletedAt: t.CompletedAt,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	})
+}
+
+func decodeEventTask(b []byte) (domain.Task, error) {
+	var item eventTaskJSON
+	if err := json.Unmarshal(b, &item); err != nil {
+		return domain.Task{}, errors.Wrap(err, "error decoding event task")
+	}
+
+	var exts = make([]domain.Extension, 0, len(item.Extensions))
+	for _, ext := range item.Extensions {
+		exts = append(exts, domain.Extension{Key: ext.Key, Value: ext.Value})
+	}
+
+	return domain.Task{
+		ID:          item.ID,
+		Title:       item.Title,
+		DueDate:     item.DueDate,
+		Tags:        item.Tags,
+		Completed:   item.Completed,
+		Priority:    item.Priority,
+		Recurrence:  item.Recurrence,
+		Contexts:    item.Contexts,
+		Extensions:  exts,
+		CompletedAt: item.CompletedAt,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}, nil
+}
@@ -31,3 +31,10 @@ type Tx interface {
 	Commit() error
 	Rollback() error
 }
+
+// SQLとTxに共通するクエリ実行のインターフェース
+type executor interface {
+	ExecuteContext(context.Context, string, ...interface{}) error
+	QueryContext(context.Context, string, ...interface{}) (Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) Row
+}
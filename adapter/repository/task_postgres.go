@@ -2,8 +2,13 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
@@ -14,67 +19,399 @@ type TaskSQL struct {
 func NewTaskSQL(db SQL) TaskSQL {
 	return TaskSQL{
 		db: db,
-	}	
+	}
 }
 
+// タスクのSELECT/RETURNINGで取得するカラム (scanTask の引数と順番を合わせる)
+const taskColumns = "id, title, due_date, tags, completed, priority, recurrence, contexts, extensions, completed_at, created_at, updated_at, change_seq, field_versions"
+
+// INSERT/UPDATEで書き込むカラム (taskValues の戻り値と順番を合わせる)
+var taskWritableColumns = []string{"title", "due_date", "tags", "completed", "priority", "recurrence", "contexts", "extensions", "field_versions"}
+
+// タスクを書き込むトランザクションを直列化するアドバイザリーロック
+// change_seq の順番とコミットの順番を揃え、同期で連番より前の変更を後から見つけることがないようにする
+// (_scripts/postgres/tasks.sql と outbox.sql のトリガーでも同じロックを取得する)
+// outbox のIDもこのロックでコミットの順に採番されるため、ロックを外す場合はイベントストリームの読み取りも変更する
+const taskWriteLockQuery = "SELECT pg_advisory_xact_lock(hashtext('task_change_seq'))"
+
 func (t TaskSQL) Create(ctx context.Context, task domain.Task) (domain.Task, error) {
-	var query = "INSERT INTO tasks (title) VALUES ($1)"
+	var query = `
+		INSERT INTO tasks (` + strings.Join(taskWritableColumns, ", ") + `) VALUES ` + placeholders(0, len(taskWritableColumns)) + `
+		RETURNING ` + taskColumns
 
-	if err := t.db.ExecuteContext(
-		ctx,
-		query,
-		task.Title,
-	); err != nil {
-		return domain.Task{}, errors.Wrap(err, "error creating task")
+	created, err := scanTask(t.executor(ctx).QueryRowContext(ctx, query, taskValues(task)...))
+	if err != nil {
+		return domain.Task{}, translateError(err, "error creating task")
 	}
 
-	return task, nil
+	return created, nil
 }
 
-func (t TaskSQL) Update(ctx context.Context, task domain.Task, taskID domain.TaskID) error {
-	var query = "UPDATE tasks SET title = $1 WHERE id = $2"
+// 複数のタスクを1回のINSERTで作成し、作成したタスクを入力と同じ順番で返却する
+func (t TaskSQL) CreateMany(ctx context.Context, tasks []domain.Task) ([]domain.Task, error) {
+	if len(tasks) == 0 {
+		return []domain.Task{}, nil
+	}
 
-	if err := t.db.ExecuteContext(
-		ctx,
-		query,
-		task.Title,
-		taskID,
-	); err != nil {
-		return errors.Wrap(err, "error updating task")
+	var (
+		n      = len(taskWritableColumns)
+		values = make([]string, 0, len(tasks))
+		args   = make([]interface{}, 0, len(tasks)*n)
+	)
+	for i, task := range tasks {
+		values = append(values, placeholders(i*n, n))
+		args = append(args, taskValues(task)...)
 	}
 
-	return nil
+	// id は連番のため、ORDER BY id で入力と同じ順番になる
+	var query = `
+		WITH created AS (
+			INSERT INTO tasks (` + strings.Join(taskWritableColumns, ", ") + `) VALUES ` + strings.Join(values, ", ") + `
+			RETURNING ` + taskColumns + `
+		)
+		SELECT ` + taskColumns + ` FROM created ORDER BY id`
+
+	rows, err := t.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err, "error creating tasks")
+	}
+	defer rows.Close()
+
+	var created = make([]domain.Task, 0, len(tasks))
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, translateError(err, "error scanning created task")
+		}
+
+		created = append(created, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "error creating tasks")
+	}
+
+	return created, nil
+}
+
+// タスクを更新し、更新後のタスクを返却する
+// 対象のタスクが存在しない場合は domain.ErrTaskNotFound を返却する
+func (t TaskSQL) Update(ctx context.Context, task domain.Task, taskID domain.TaskID) (domain.Task, error) {
+	var sets = make([]string, 0, len(taskWritableColumns))
+	for i, column := range taskWritableColumns {
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, i+1))
+	}
+
+	var query = `
+		UPDATE tasks SET ` + strings.Join(sets, ", ") + fmt.Sprintf(`
+		WHERE id = $%d
+		RETURNING `, len(taskWritableColumns)+1) + taskColumns
+
+	updated, err := scanTask(t.executor(ctx).QueryRowContext(ctx, query, append(taskValues(task), taskID)...))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.Task{}, domain.ErrTaskNotFound
+	case err != nil:
+		return domain.Task{}, translateError(err, "error updating task")
+	}
+
+	return updated, nil
 }
 
 func (t TaskSQL) FindAll(ctx context.Context) ([]domain.Task, error) {
-	var query = "SELECT id, title FROM tasks"
+	var query = "SELECT " + taskColumns + " FROM tasks ORDER BY id"
 
-	rows, err := t.db.QueryContext(ctx, query)
+	rows, err := t.executor(ctx).QueryContext(ctx, query)
 	if err != nil {
-		return []domain.Task{}, errors.Wrap(err, "error listing tasks")
+		return []domain.Task{}, translateError(err, "error listing tasks")
 	}
-	
+	defer rows.Close()
+
 	var tasks = make([]domain.Task, 0)
 	for rows.Next() {
-		var (
-			ID    domain.TaskID
-			title string
-		)
+		task, err := scanTask(rows)
+		if err != nil {
+			return []domain.Task{}, translateError(err, "error listing tasks")
+		}
 
-		if err = rows.Scan(&ID, &title); err != nil {
-			return []domain.Task{}, errors.Wrap(err, "error listing tasks")
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []domain.Task{}, translateError(err, "error listing tasks")
+	}
+
+	return tasks, nil
+}
+
+// ID順にタスクを1件ずつ読み取り、関数に渡す
+func (t TaskSQL) FindEach(ctx context.Context, fn func(domain.Task) error) error {
+	var query = "SELECT " + taskColumns + " FROM tasks ORDER BY id"
+
+	rows, err := t.executor(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return translateError(err, "error listing tasks")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return translateError(err, "error listing tasks")
 		}
 
-		tasks = append(tasks, domain.Task{
-			ID:    ID,
-			Title: title,
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return translateError(err, "error listing tasks")
+	}
+
+	return nil
+}
+
+// タスクを1件取得する
+// 対象のタスクが存在しない場合は domain.ErrTaskNotFound を返却する
+// トランザクション内で呼び出された場合は更新が終わるまで行をロックする
+func (t TaskSQL) FindByID(ctx context.Context, taskID domain.TaskID) (domain.Task, error) {
+	var query = "SELECT " + taskColumns + " FROM tasks WHERE id = $1"
+	if _, ok := ctx.Value(KeyTransactionContext).(Tx); ok {
+		query += " FOR UPDATE"
+	}
+
+	task, err := scanTask(t.executor(ctx).QueryRowContext(ctx, query, taskID))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.Task{}, domain.ErrTaskNotFound
+	case err != nil:
+		return domain.Task{}, translateError(err, "error fetching task")
+	}
+
+	return task, nil
+}
+
+// 作成・更新されたタスクと削除されたタスクを連番の順に合わせて返却する
+// 2つのテーブルを同じスナップショットで読み取るため、トランザクション外で呼び出された場合は
+// REPEATABLE READ の読み取り専用トランザクションを開始する
+func (t TaskSQL) FindChanges(ctx context.Context, since domain.ChangeSeq, limit int) ([]domain.TaskChange, error) {
+	if _, ok := ctx.Value(KeyTransactionContext).(Tx); !ok {
+		var changes []domain.TaskChange
+		err := withTransaction(ctx, t.db, func(ctxTx context.Context) error {
+			var query = "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"
+			if err := t.executor(ctxTx).ExecuteContext(ctxTx, query); err != nil {
+				return translateError(err, "error setting transaction isolation level")
+			}
+
+			var err error
+			changes, err = t.FindChanges(ctxTx, since, limit)
+			return err
 		})
+
+		return changes, err
+	}
+
+	tasks, err := t.findChangedTasks(ctx, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstones, err := t.findTombstones(ctx, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// それぞれ連番の順に limit 件まで読み取っているため、併合した先頭 limit 件が全体の先頭 limit 件になる
+	var changes = make([]domain.TaskChange, 0, len(tasks)+len(tombstones))
+	for len(tasks) > 0 || len(tombstones) > 0 {
+		if len(tombstones) == 0 || (len(tasks) > 0 && tasks[0].Seq < tombstones[0].Seq) {
+			changes = append(changes, tasks[0])
+			tasks = tasks[1:]
+		} else {
+			changes = append(changes, tombstones[0])
+			tombstones = tombstones[1:]
+		}
+	}
+
+	if len(changes) > limit {
+		changes = changes[:limit]
+	}
+
+	return changes, nil
+}
+
+func (t TaskSQL) findChangedTasks(ctx context.Context, since domain.ChangeSeq, limit int) ([]domain.TaskChange, error) {
+	var query = "SELECT " + taskColumns + " FROM tasks WHERE change_seq > $1 ORDER BY change_seq LIMIT $2"
+
+	rows, err := t.executor(ctx).QueryContext(ctx, query, since, limit)
+	if err != nil {
+		return nil, translateError(err, "error listing task changes")
 	}
 	defer rows.Close()
 
-	if err = rows.Err(); err != nil {
-		return []domain.Task{}, err
+	var changes = make([]domain.TaskChange, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, translateError(err, "error listing task changes")
+		}
+
+		changes = append(changes, domain.TaskChange{Seq: task.ChangeSeq, TaskID: task.ID, Task: &task})
 	}
 
-	return tasks, nil
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "error listing task changes")
+	}
+
+	return changes, nil
+}
+
+func (t TaskSQL) findTombstones(ctx context.Context, since domain.ChangeSeq, limit int) ([]domain.TaskChange, error) {
+	var query = "SELECT change_seq, task_id, deleted_at FROM task_tombstones WHERE change_seq > $1 ORDER BY change_seq LIMIT $2"
+
+	rows, err := t.executor(ctx).QueryContext(ctx, query, since, limit)
+	if err != nil {
+		return nil, translateError(err, "error listing deleted tasks")
+	}
+	defer rows.Close()
+
+	var changes = make([]domain.TaskChange, 0)
+	for rows.Next() {
+		var (
+			change    domain.TaskChange
+			deletedAt time.Time
+		)
+		if err := rows.Scan(&change.Seq, &change.TaskID, &deletedAt); err != nil {
+			return nil, translateError(err, "error listing deleted tasks")
+		}
+
+		change.DeletedAt = &deletedAt
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "error listing deleted tasks")
+	}
+
+	return changes, nil
+}
+
+// 期限はセッションのタイムゾーンによらず、現在日時と比較する (due_date は TIMESTAMPTZ)
+func (t TaskSQL) Count(ctx context.Context) (domain.TaskCounts, error) {
+	var query = `
+		SELECT
+			COUNT(*) FILTER (WHERE NOT completed),
+			COUNT(*) FILTER (WHERE NOT completed AND due_date < NOW())
+		FROM tasks
+	`
+
+	var counts domain.TaskCounts
+	if err := t.executor(ctx).QueryRowContext(ctx, query).Scan(&counts.Open, &counts.Overdue); err != nil {
+		return domain.TaskCounts{}, translateError(err, "error counting tasks")
+	}
+
+	return counts, nil
+}
+
+// タスクを削除する
+// 対象のタスクが存在しない場合は domain.ErrTaskNotFound を返却する
+func (t TaskSQL) Delete(ctx context.Context, taskID domain.TaskID) error {
+	var query = "DELETE FROM tasks WHERE id = $1 RETURNING id"
+
+	var id domain.TaskID
+	err := t.executor(ctx).QueryRowContext(ctx, query, taskID).Scan(&id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrTaskNotFound
+	case err != nil:
+		return translateError(err, "error deleting task")
+	}
+
+	return nil
+}
+
+// トランザクション内で関数を実行する (withTransaction を参照)
+// 新しく開始したトランザクションでは、最初にタスクの書き込みのロックを取得する (taskWriteLockQuery を参照)
+// 行をロックした後にこのロックを待つと、他のトランザクションとデッドロックする可能性があるため
+func (t TaskSQL) WithTransaction(ctx context.Context, fn func(ctxTx context.Context) error) error {
+	if _, ok := ctx.Value(KeyTransactionContext).(Tx); ok {
+		return withTransaction(ctx, t.db, fn)
+	}
+
+	return withTransaction(ctx, t.db, func(ctxTx context.Context) error {
+		if err := t.executor(ctxTx).ExecuteContext(ctxTx, taskWriteLockQuery); err != nil {
+			return translateError(err, "error locking tasks")
+		}
+
+		return fn(ctxTx)
+	})
+}
+
+// コンテキストにトランザクションがあればそれを、なければDBハンドラーを返却する
+func (t TaskSQL) executor(ctx context.Context) executor {
+	if tx, ok := ctx.Value(KeyTransactionContext).(Tx); ok {
+		return tx
+	}
+
+	return t.db
+}
+
+// taskColumns の順番でタスクを読み取る
+func scanTask(row Row) (domain.Task, error) {
+	var task domain.Task
+	if err := row.Scan(
+		&task.ID,
+		&task.Title,
+		&task.DueDate,
+		pq.Array(&task.Tags),
+		&task.Completed,
+		&task.Priority,
+		&task.Recurrence,
+		pq.Array(&task.Contexts),
+		(*extensions)(&task.Extensions),
+		&task.CompletedAt,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.ChangeSeq,
+		(*fieldVersions)(&task.Versions),
+	); err != nil {
+		return domain.Task{}, err
+	}
+
+	return task, nil
+}
+
+// taskWritableColumns の順番で書き込む値を返却する
+func taskValues(task domain.Task) []interface{} {
+	return []interface{}{
+		task.Title,
+		task.DueDate,
+		pq.Array(tagsOrEmpty(task.Tags)),
+		task.Completed,
+		task.Priority,
+		task.Recurrence,
+		pq.Array(tagsOrEmpty(task.Contexts)),
+		extensions(task.Extensions),
+		fieldVersions(task.Versions),
+	}
+}
+
+// offset+1 から始まる n 個のプレースホルダー (例: "($1, $2, $3)")
+func placeholders(offset, n int) string {
+	var p = make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		p = append(p, fmt.Sprintf("$%d", offset+i))
+	}
+
+	return "(" + strings.Join(p, ", ") + ")"
+}
+
+// NOT NULL のカラムに保存するため、nilのタグを空のスライスに変換する
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
 }
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// トランザクション内で関数を実行する
+// 関数がエラーを返却した場合はロールバックし、そうでなければコミットする
+// すでにトランザクション内で呼び出された場合はセーブポイントを作成し、その範囲だけをロールバックする
+// コンテキストでトランザクションを受け渡すため、異なるリポジトリの変更も同じトランザクションで実行される
+func withTransaction(ctx context.Context, db SQL, fn func(ctxTx context.Context) error) error {
+	if tx, ok := ctx.Value(KeyTransactionContext).(Tx); ok {
+		return withSavepoint(ctx, tx, fn)
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return translateError(err, "error begin tx")
+	}
+
+	ctxTx := context.WithValue(ctx, KeyTransactionContext, tx)
+	if err := fn(ctxTx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Wrap(err, "rollback error")
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return translateError(err, "error commit tx")
+	}
+
+	return nil
+}
+
+// セーブポイント名の連番
+var savepointSeq atomic.Uint64
+
+// セーブポイント内で関数を実行する
+func withSavepoint(ctx context.Context, tx Tx, fn func(ctxTx context.Context) error) error {
+	var name = fmt.Sprintf("sp_%d", savepointSeq.Add(1))
+
+	if err := tx.ExecuteContext(ctx, "SAVEPOINT "+name); err != nil {
+		return translateError(err, "error creating savepoint")
+	}
+
+	if err := fn(ctx); err != nil {
+		if rbErr := tx.ExecuteContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return errors.Wrap(err, "rollback to savepoint error")
+		}
+		return err
+	}
+
+	if err := tx.ExecuteContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return translateError(err, "error releasing savepoint")
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type WebhookSQL struct {
+	db SQL
+}
+
+func NewWebhookSQL(db SQL) WebhookSQL {
+	return WebhookSQL{
+		db: db,
+	}
+}
+
+// 購読のSELECT/RETURNINGで取得するカラム (scanWebhook の引数と順番を合わせる)
+const webhookColumns = "id, account_id, url, events, secret, active, consecutive_failures, created_at, updated_at"
+
+// 配信のSELECT/RETURNINGで取得するカラム (deliveryDest の戻り値と順番を合わせる)
+const deliveryColumns = "d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.updated_at"
+
+func (w WebhookSQL) Create(ctx context.Context, subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	var query = `
+		INSERT INTO webhook_subscriptions (account_id, url, events, secret) VALUES ($1, $2, $3, $4)
+		RETURNING ` + webhookColumns
+
+	created, err := scanWebhook(w.executor(ctx).QueryRowContext(
+		ctx,
+		query,
+		subscription.AccountID,
+		subscription.URL,
+		pq.Array(subscription.Events),
+		subscription.Secret,
+	))
+	if err != nil {
+		return domain.WebhookSubscription{}, translateError(err, "error creating webhook")
+	}
+
+	return created, nil
+}
+
+func (w WebhookSQL) FindAll(ctx context.Context, accountID domain.AccountID) ([]domain.WebhookSubscription, error) {
+	var query = "SELECT " + webhookColumns + " FROM webhook_subscriptions WHERE account_id = $1 ORDER BY id"
+
+	rows, err := w.executor(ctx).QueryContext(ctx, query, accountID)
+	if err != nil {
+		return nil, translateError(err, "error listing webhooks")
+	}
+	defer rows.Close()
+
+	var subscriptions = make([]domain.WebhookSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanWebhook(rows)
+		if err != nil {
+			return nil, translateError(err, "error listing webhooks")
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "error listing webhooks")
+	}
+
+	return subscriptions, nil
+}
+
+func (w WebhookSQL) Delete(ctx context.Context, accountID domain.AccountID, id domain.WebhookID) error {
+	var query = "DELETE FROM webhook_subscriptions WHERE id = $1 AND account_id = $2 RETURNING id"
+
+	var deleted domain.WebhookID
+	err := w.executor(ctx).QueryRowContext(ctx, query, id, accountID).Scan(&deleted)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrWebhookNotFound
+	case err != nil:
+		return translateError(err, "error deleting webhook")
+	}
+
+	return nil
+}
+
+func (w WebhookSQL) Enable(ctx context.Context, accountID domain.AccountID, id domain.WebhookID) (domain.WebhookSubscription, error) {
+	var query = `
+		UPDATE webhook_subscriptions SET active = TRUE, consecutive_failures = 0, updated_at = NOW()
+		WHERE id = $1 AND account_id = $2
+		RETURNING ` + webhookColumns
+
+	enabled, err := scanWebhook(w.executor(ctx).QueryRowContext(ctx, query, id, accountID))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.WebhookSubscription{}, domain.ErrWebhookNotFound
+	case err != nil:
+		return domain.WebhookSubscription{}, translateError(err, "error enabling webhook")
+	}
+
+	return enabled, nil
+}
+
+// 試行の履歴は1件のクエリで JSON に集約して取得する
+func (w WebhookSQL) FindDeliveries(
+	ctx context.Context,
+	accountID domain.AccountID,
+	id domain.WebhookID,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	var exists bool
+	if err := w.executor(ctx).QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM webhook_subscriptions WHERE id = $1 AND account_id = $2)",
+		id,
+		accountID,
+	).Scan(&exists); err != nil {
+		return nil, translateError(err, "error fetching webhook")
+	}
+
+	if !exists {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	var query = `
+		SELECT ` + deliveryColumns + `, COALESCE((
+			SELECT json_agg(json_build_object(
+				'attempt', a.attempt,
+				'status_code', a.status_code,
+				'error', a.error,
+				'duration_ms', a.duration_ms,
+				'attempted_at', a.attempted_at
+			) ORDER BY a.attempt)
+			FROM webhook_delivery_attempts a WHERE a.delivery_id = d.id
+		), '[]')
+		FROM webhook_deliveries d
+		WHERE d.subscription_id = $1
+		ORDER BY d.id DESC
+		LIMIT $2`
+
+	rows, err := w.executor(ctx).QueryContext(ctx, query, id, limit)
+	if err != nil {
+		return nil, translateError(err, "error listing webhook deliveries")
+	}
+	defer rows.Close()
+
+	var deliveries = make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var (
+			delivery domain.WebhookDelivery
+			log      attemptLog
+		)
+		if err := rows.Scan(append(deliveryDest(&delivery), &log)...); err != nil {
+			return nil, translateError(err, "error listing webhook deliveries")
+		}
+
+		delivery.Log = log
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "error listing webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// 同じイベントを再度登録した場合は何もしない
+func (w WebhookSQL) Enqueue(ctx context.Context, event domain.WebhookEvent) error {
+	var query = `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT id, $1, $2, $3 FROM webhook_subscriptions
+		WHERE active AND $2 = ANY(events) AND account_id = $4
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	if err := w.executor(ctx).ExecuteContext(ctx, query, event.ID, event.Type, event.Payload, event.AccountID); err != nil {
+		return translateError(err, "error enqueuing webhook event")
+	}
+
+	return nil
+}
+
+// 取り出した配信の次の試行日時を lease 後に進めることで、
+// 複数のワーカーが同時に動いても同じ配信を重複して送信しないようにする
+// ワーカーが結果を記録せずに停止した場合は lease 後に再び取り出される
+func (w WebhookSQL) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	var query = `
+		UPDATE webhook_deliveries d SET next_attempt_at = NOW() + $2::DOUBLE PRECISION * INTERVAL '1 second'
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT due.id FROM webhook_deliveries due
+			JOIN webhook_subscriptions sub ON sub.id = due.subscription_id
+			WHERE due.status = 'pending' AND due.next_attempt_at <= NOW() AND sub.active
+			ORDER BY due.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF due SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns + `, s.url, s.secret`
+
+	rows, err := w.executor(ctx).QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, translateError(err, "error claiming webhook deliveries")
+	}
+	defer rows.Close()
+
+	var deliveries = make([]domain.WebhookDelivery, 0, limit)
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := rows.Scan(append(deliveryDest(&delivery), &delivery.URL, &delivery.Secret)...); err != nil {
+			return nil, translateError(err, "error claiming webhook deliveries")
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "error claiming webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// 試行の履歴・配信・購読の更新を1つのステートメントで行う
+func (w WebhookSQL) RecordAttempt(ctx context.Context, result domain.WebhookAttemptResult) error {
+	var status = domain.DeliveryPending
+	switch {
+	case result.Succeeded:
+		status = domain.DeliverySucceeded
+	case result.RetryAt == nil:
+		status = domain.DeliveryFailed
+	}
+
+	var query = `
+		WITH attempt AS (
+			INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms, attempted_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		), delivery AS (
+			UPDATE webhook_deliveries SET
+				status = $7,
+				attempts = $2,
+				next_attempt_at = COALESCE($8, next_attempt_at),
+				last_status_code = $3,
+				last_error = $4,
+				updated_at = NOW()
+			WHERE id = $1
+		)
+		UPDATE webhook_subscriptions SET
+			consecutive_failures = CASE WHEN $9::BOOLEAN THEN 0 ELSE consecutive_failures + 1 END,
+			active = active AND ($9::BOOLEAN OR consecutive_failures + 1 < $10),
+			updated_at = NOW()
+		WHERE id = $11
+	`
+
+	if err := w.executor(ctx).ExecuteContext(
+		ctx,
+		query,
+		result.DeliveryID,
+		result.Attempt,
+		result.StatusCode,
+		result.Error,
+		result.Duration.Milliseconds(),
+		result.AttemptedAt,
+		status,
+		result.RetryAt,
+		result.Succeeded,
+		result.DisableAfter,
+		result.SubscriptionID,
+	); err != nil {
+		return translateError(err, "error recording webhook attempt")
+	}
+
+	return nil
+}
+
+// コンテキストにトランザクションがあればそれを、なければDBハンドラーを返却する
+func (w WebhookSQL) executor(ctx context.Context) executor {
+	if tx, ok := ctx.Value(KeyTransactionContext).(Tx); ok {
+		return tx
+	}
+
+	return w.db
+}
+
+// webhookColumns の順番で購読を読み取る
+func scanWebhook(row Row) (domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	if err := row.Scan(
+		&subscription.ID,
+		&subscription.AccountID,
+		&subscription.URL,
+		pq.Array(&subscription.Events),
+		&subscription.Secret,
+		&subscription.Active,
+		&subscription.ConsecutiveFailures,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	return subscription, nil
+}
+
+// deliveryColumns の順番で配信を読み取るための引数
+func deliveryDest(delivery *domain.WebhookDelivery) []interface{} {
+	return []interface{}{
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	}
+}
+
+// json_agg で集約した試行の履歴
+type attemptLog []domain.WebhookAttempt
+
+func (l *attemptLog) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.Errorf("unsupported type for webhook attempts: %T", src)
+	}
+
+	var rows []struct {
+		Attempt     int       `json:"attempt"`
+		StatusCode  int       `json:"status_code"`
+		Error       string    `json:"error"`
+		DurationMS  int64     `json:"duration_ms"`
+		AttemptedAt time.Time `json:"attempted_at"`
+	}
+	if err := json.Unmarshal(b, &rows); err != nil {
+		return errors.Wrap(err, "error decoding webhook attempts")
+	}
+
+	*l = make(attemptLog, 0, len(rows))
+	for _, r := range rows {
+		*l = append(*l, domain.WebhookAttempt{
+			Attempt:     r.Attempt,
+			StatusCode:  r.StatusCode,
+			Error:       r.Error,
+			Duration:    time.Duration(r.DurationMS) * time.Millisecond,
+			AttemptedAt: r.AttemptedAt,
+		})
+	}
+
+	return nil
+}
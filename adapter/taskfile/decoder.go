@@ -0,0 +1,222 @@
+package taskfile
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+var (
+	ErrMissingTitleColumn = errors.New("csv header must contain a title column")
+
+	ErrNotJSONArray = errors.New("json file must contain an array of tasks")
+)
+
+// ファイルから読み取った1件のタスク
+type Record struct {
+	// CSV、NDJSON、todo.txtの場合は行番号、JSON配列の場合は要素の番号 (1始まり)
+	Line int
+	Task usecase.UpdateTaskInput
+	// この行だけを読み取れなかった場合のエラー (残りの行は読み取りを続けられる)
+	Err error
+}
+
+// タスクを1件ずつ読み取る
+// 最後まで読み取った場合は io.EOF を、ファイル全体が読み取れない場合はそのエラーを返却する
+type Decoder interface {
+	Next() (Record, error)
+}
+
+func NewDecoder(r io.Reader, f Format) (Decoder, error) {
+	switch f {
+	case FormatCSV:
+		var reader = csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		return &csvDecoder{r: reader}, nil
+	case FormatJSON:
+		return &jsonDecoder{dec: json.NewDecoder(r)}, nil
+	case FormatNDJSON:
+		var scanner = bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		return &ndjsonDecoder{s: scanner}, nil
+	case FormatTodoTxt:
+		return &todoTxtDecoder{s: bufio.NewScanner(r)}, nil
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+type csvDecoder struct {
+	r *csv.Reader
+	// 列名から列の位置への対応 (ヘッダーを読み取るまでは nil)
+	index map[string]int
+}
+
+func (d *csvDecoder) Next() (Record, error) {
+	if d.index == nil {
+		if err := d.readHeader(); err != nil {
+			return Record{}, err
+		}
+	}
+
+	fields, err := d.r.Read()
+	var parseErr *csv.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		return Record{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	case err != nil:
+		return Record{}, err
+	}
+
+	line, _ := d.r.FieldPos(0)
+	var record = Record{Line: line}
+	record.Task, record.Err = d.parse(fields)
+
+	return record, nil
+}
+
+func (d *csvDecoder) readHeader() error {
+	header, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+
+	d.index = make(map[string]int, len(header))
+	for i, name := range header {
+		d.index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := d.index["title"]; !ok {
+		return ErrMissingTitleColumn
+	}
+
+	return nil
+}
+
+func (d *csvDecoder) parse(fields []string) (usecase.UpdateTaskInput, error) {
+	var input = usecase.UpdateTaskInput{Title: d.field(fields, "title")}
+
+	if v := d.field(fields, "due_date"); v != "" {
+		dueDate, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return input, fmt.Errorf("due_date must be an RFC3339 timestamp: %q", v)
+		}
+		input.DueDate = &dueDate
+	}
+
+	if v := d.field(fields, "tags"); v != "" {
+		input.Tags = splitList(v)
+	}
+
+	if v := d.field(fields, "completed"); v != "" {
+		completed, err := strconv.ParseBool(v)
+		if err != nil {
+			return input, fmt.Errorf("completed must be a boolean: %q", v)
+		}
+		input.Completed = completed
+	}
+
+	if v := d.field(fields, "priority"); v != "" {
+		priority, err := strconv.Atoi(v)
+		if err != nil {
+			return input, fmt.Errorf("priority must be an integer: %q", v)
+		}
+		input.Priority = priority
+	}
+
+	input.Recurrence = d.field(fields, "recurrence")
+
+	if v := d.field(fields, "contexts"); v != "" {
+		input.Contexts = splitList(v)
+	}
+
+	if v := d.field(fields, "extensions"); v != "" {
+		extensions, err := splitExtensions(v)
+		if err != nil {
+			return input, err
+		}
+		input.Extensions = extensions
+	}
+
+	return input, nil
+}
+
+// 列の値を返却する (列がない場合は空文字)
+// エクスポートで数式の対策として付けた ' は取り除く
+func (d *csvDecoder) field(fields []string, name string) string {
+	i, ok := d.index[name]
+	if !ok || i >= len(fields) {
+		return ""
+	}
+
+	return unescapeFormula(fields[i])
+}
+
+type jsonDecoder struct {
+	dec     *json.Decoder
+	started bool
+	count   int
+}
+
+func (d *jsonDecoder) Next() (Record, error) {
+	if !d.started {
+		if tok, err := d.dec.Token(); err != nil || tok != json.Delim('[') {
+			return Record{}, ErrNotJSONArray
+		}
+		d.started = true
+	}
+
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil {
+			return Record{}, err
+		}
+		return Record{}, io.EOF
+	}
+
+	// 構文エラーは続きを読み取れないため、ファイル全体のエラーとする
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return Record{}, err
+	}
+
+	d.count++
+	var record = Record{Line: d.count}
+	record.Err = json.Unmarshal(raw, &record.Task)
+
+	return record, nil
+}
+
+type ndjsonDecoder struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func (d *ndjsonDecoder) Next() (Record, error) {
+	for d.s.Scan() {
+		d.line++
+
+		var text = strings.TrimSpace(d.s.Text())
+		if text == "" {
+			continue
+		}
+
+		var record = Record{Line: d.line}
+		record.Err = json.Unmarshal([]byte(text), &record.Task)
+
+		return record, nil
+	}
+
+	if err := d.s.Err(); err != nil {
+		return Record{}, err
+	}
+
+	return Record{}, io.EOF
+}
@@ -0,0 +1,203 @@
+package taskfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+func TestDecoder_Next(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dueDate = time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+		dueDay  = time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	)
+
+	// 読み取った結果 (エラーの行は Title を "error" とする)
+	type result struct {
+		Line int
+		Task usecase.UpdateTaskInput
+	}
+
+	tests := []struct {
+		name          string
+		format        Format
+		input         string
+		expected      []result
+		expectedError error
+	}{
+		{
+			name:   "Decode csv",
+			format: FormatCSV,
+			input: "title,due_date,tags,completed,id,priority,recurrence\n" +
+				"Task_1,2024-01-31T18:00:00Z,work; side-project,true,10,2,FREQ=DAILY\n" +
+				"Task_2,tomorrow,,,\n" +
+				"\"Task, 3\",,,,\n",
+			expected: []result{
+				{Line: 2, Task: usecase.UpdateTaskInput{Title: "Task_1", DueDate: &dueDate, Tags: []string{"work", "side-project"}, Completed: true, Priority: 2, Recurrence: "FREQ=DAILY"}},
+				{Line: 3, Task: usecase.UpdateTaskInput{Title: "error"}},
+				{Line: 4, Task: usecase.UpdateTaskInput{Title: "Task, 3"}},
+			},
+		},
+		{
+			name:   "Decode csv with contexts and extensions",
+			format: FormatCSV,
+			input: "title,contexts,extensions\n" +
+				"Task_1,office; phone,id:1;note:a\\;b;url:https://example.com\n" +
+				"Task_2,,no-value\n",
+			expected: []result{
+				{Line: 2, Task: usecase.UpdateTaskInput{Title: "Task_1", Contexts: []string{"office", "phone"}, Extensions: []usecase.Extension{{Key: "id", Value: "1"}, {Key: "note", Value: "a;b"}, {Key: "url", Value: "https://example.com"}}}},
+				{Line: 3, Task: usecase.UpdateTaskInput{Title: "error"}},
+			},
+		},
+		{
+			name:   "Decode csv with escaped formulas",
+			format: FormatCSV,
+			input:  "title\n'=1+1\n''=quoted\n'quoted\n",
+			expected: []result{
+				{Line: 2, Task: usecase.UpdateTaskInput{Title: "=1+1"}},
+				{Line: 3, Task: usecase.UpdateTaskInput{Title: "'=quoted"}},
+				{Line: 4, Task: usecase.UpdateTaskInput{Title: "'quoted"}},
+			},
+		},
+		{
+			name:          "Decode csv without title column",
+			format:        FormatCSV,
+			input:         "name\nTask_1\n",
+			expectedError: ErrMissingTitleColumn,
+		},
+		{
+			name:   "Decode json",
+			format: FormatJSON,
+			input:  `[{"title":"Task_1","tags":["work"]},{"title":1},{"id":3,"title":"Task_3","completed":true}]`,
+			expected: []result{
+				{Line: 1, Task: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{"work"}}},
+				{Line: 2, Task: usecase.UpdateTaskInput{Title: "error"}},
+				{Line: 3, Task: usecase.UpdateTaskInput{Title: "Task_3", Completed: true}},
+			},
+		},
+		{
+			name:          "Decode json object",
+			format:        FormatJSON,
+			input:         `{"title":"Task_1"}`,
+			expectedError: ErrNotJSONArray,
+		},
+		{
+			name:   "Decode todo.txt",
+			format: FormatTodoTxt,
+			input:  "(A) 2024-01-01 Task_1 +work @office due:2024-01-31 id:1\n\nTask_2 due:soon\nx Task_3\n",
+			expected: []result{
+				{Line: 1, Task: usecase.UpdateTaskInput{Title: "Task_1", DueDate: &dueDay, Tags: []string{"work"}, Priority: 1, Contexts: []string{"office"}, Extensions: []usecase.Extension{{Key: "id", Value: "1"}}}},
+				{Line: 3, Task: usecase.UpdateTaskInput{Title: "error"}},
+				{Line: 4, Task: usecase.UpdateTaskInput{Title: "Task_3", Completed: true}},
+			},
+		},
+		{
+			name:   "Decode todo.txt with CamelCase projects and contexts",
+			format: FormatTodoTxt,
+			input:  "Task_1 +WorkProject @HomeOffice\n",
+			expected: []result{
+				{Line: 1, Task: usecase.UpdateTaskInput{Title: "Task_1", Tags: []string{"workproject"}, Contexts: []string{"homeoffice"}}},
+			},
+		},
+		{
+			name:   "Decode ndjson",
+			format: FormatNDJSON,
+			input:  "{\"title\":\"Task_1\",\"due_date\":\"2024-01-31T18:00:00Z\"}\n\n{\"title\":\n{\"title\":\"Task_3\"}\n",
+			expected: []result{
+				{Line: 1, Task: usecase.UpdateTaskInput{Title: "Task_1", DueDate: &dueDate}},
+				{Line: 3, Task: usecase.UpdateTaskInput{Title: "error"}},
+				{Line: 4, Task: usecase.UpdateTaskInput{Title: "Task_3"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec, err := NewDecoder(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var results []result
+			for {
+				record, err := dec.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					if !errors.Is(err, tt.expectedError) {
+						t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+					}
+					return
+				}
+
+				if record.Err != nil {
+					record.Task = usecase.UpdateTaskInput{Title: "error"}
+				}
+				results = append(results, result{Line: record.Line, Task: record.Task})
+			}
+
+			if tt.expectedError != nil {
+				t.Errorf("[TestCase '%s'] ExpectedError: '%v'", tt.name, tt.expectedError)
+			}
+
+			if !reflect.DeepEqual(results, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%+v'", tt.name, results, tt.expected)
+			}
+		})
+	}
+}
+
+// CSVに書き出したタスクを、同じ内容で読み取れる
+func TestDecoder_NextCSVRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dueDate = time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+		task    = usecase.UpdateTaskOutput{
+			ID:         1,
+			Title:      "=Task, \"1\"",
+			DueDate:    "2024-01-31T18:00:00Z",
+			Tags:       []string{"work", "side-project"},
+			Priority:   2,
+			Recurrence: "FREQ=WEEKLY;BYDAY=MO",
+			Contexts:   []string{"office", "phone"},
+			Extensions: []usecase.Extension{{Key: "id", Value: "1"}, {Key: "note", Value: `a;b\c`}},
+		}
+		expected = usecase.UpdateTaskInput{
+			Title:      task.Title,
+			DueDate:    &dueDate,
+			Tags:       task.Tags,
+			Priority:   task.Priority,
+			Recurrence: task.Recurrence,
+			Contexts:   task.Contexts,
+			Extensions: task.Extensions,
+		}
+	)
+
+	var buf bytes.Buffer
+	enc, _ := NewEncoder(&buf, FormatCSV)
+	_ = enc.Encode(task)
+	_ = enc.Close()
+
+	dec, _ := NewDecoder(&buf, FormatCSV)
+	record, err := dec.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Err != nil {
+		t.Fatal(record.Err)
+	}
+
+	if !reflect.DeepEqual(record.Task, expected) {
+		t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%+v'", t.Name(), record.Task, expected)
+	}
+}
@@ -0,0 +1,133 @@
+package taskfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+// タスクを1件ずつ書き出す
+// 書き出し終わったら Close を呼び出す
+type Encoder interface {
+	Encode(usecase.UpdateTaskOutput) error
+	Close() error
+}
+
+func NewEncoder(w io.Writer, f Format) (Encoder, error) {
+	switch f {
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case FormatJSON:
+		return &jsonEncoder{w: w}, nil
+	case FormatNDJSON:
+		return ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	case FormatTodoTxt:
+		return todoTxtEncoder{w: w}, nil
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+type csvEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvEncoder) Encode(task usecase.UpdateTaskOutput) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	var record = []string{
+		strconv.FormatUint(uint64(task.ID), 10),
+		task.Title,
+		task.DueDate,
+		strings.Join(task.Tags, tagSeparator),
+		strconv.FormatBool(task.Completed),
+		strconv.Itoa(task.Priority),
+		task.Recurrence,
+		strings.Join(task.Contexts, tagSeparator),
+		joinExtensions(task.Extensions),
+		task.CreatedAt,
+		task.UpdatedAt,
+	}
+	// 表計算ソフトで開いた時に、タイトルなどが数式として実行されないようにする
+	for i, v := range record {
+		record[i] = escapeFormula(v)
+	}
+
+	if err := e.w.Write(record); err != nil {
+		return err
+	}
+
+	// 1行ごとにクライアントへ送る
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) Close() error {
+	// タスクが0件の場合もヘッダーは書き出す
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+
+	e.headerWritten = true
+	return e.w.Write(columns)
+}
+
+// 配列全体を組み立てずに、要素ごとに書き出す
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(task usecase.UpdateTaskOutput) error {
+	var prefix = ","
+	if e.count == 0 {
+		prefix = "["
+	}
+	e.count++
+
+	b, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(append([]byte(prefix), b...))
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	var suffix = "]\n"
+	if e.count == 0 {
+		suffix = "[]\n"
+	}
+
+	_, err := io.WriteString(e.w, suffix)
+	return err
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e ndjsonEncoder) Encode(task usecase.UpdateTaskOutput) error {
+	return e.enc.Encode(task)
+}
+
+func (e ndjsonEncoder) Close() error {
+	return nil
+}
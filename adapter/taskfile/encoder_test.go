@@ -0,0 +1,126 @@
+package taskfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+func TestEncoder_Encode(t *testing.T) {
+	t.Parallel()
+
+	var tasks = []usecase.UpdateTaskOutput{
+		{ID: 1, Title: "Task_1", DueDate: "2024-01-31T18:00:00Z", Tags: []string{"work", "side-project"}, Priority: 1, Recurrence: "FREQ=WEEKLY;BYDAY=MO", Contexts: []string{"office"}, Extensions: []usecase.Extension{{Key: "id", Value: "1"}, {Key: "note", Value: "a;b"}}, CreatedAt: "2024-01-01T00:00:00Z", UpdatedAt: "2024-01-02T00:00:00Z"},
+		{ID: 2, Title: "Task, \"2\"", Tags: []string{}, Completed: true, CreatedAt: "2024-01-01T00:00:00Z", UpdatedAt: "2024-01-01T00:00:00Z"},
+	}
+
+	tests := []struct {
+		name     string
+		format   Format
+		tasks    []usecase.UpdateTaskOutput
+		expected string
+	}{
+		{
+			name:   "Encode csv",
+			format: FormatCSV,
+			tasks:  tasks,
+			expected: "id,title,due_date,tags,completed,priority,recurrence,contexts,extensions,created_at,updated_at\n" +
+				"1,Task_1,2024-01-31T18:00:00Z,work;side-project,false,1,FREQ=WEEKLY;BYDAY=MO,office,id:1;note:a\\;b,2024-01-01T00:00:00Z,2024-01-02T00:00:00Z\n" +
+				"2,\"Task, \"\"2\"\"\",,,true,0,,,,2024-01-01T00:00:00Z,2024-01-01T00:00:00Z\n",
+		},
+		{
+			name:   "Encode csv with formulas",
+			format: FormatCSV,
+			tasks: []usecase.UpdateTaskOutput{
+				{ID: 3, Title: "=HYPERLINK(\"x\")", Tags: []string{}},
+				{ID: 4, Title: "-1", Tags: []string{}},
+				{ID: 5, Title: "'=quoted", Tags: []string{}},
+				{ID: 6, Title: "'quoted", Tags: []string{}},
+			},
+			expected: "id,title,due_date,tags,completed,priority,recurrence,contexts,extensions,created_at,updated_at\n" +
+				"3,\"'=HYPERLINK(\"\"x\"\")\",,,false,0,,,,,\n" +
+				"4,'-1,,,false,0,,,,,\n" +
+				"5,''=quoted,,,false,0,,,,,\n" +
+				"6,'quoted,,,false,0,,,,,\n",
+		},
+		{
+			name:     "Encode empty csv",
+			format:   FormatCSV,
+			expected: "id,title,due_date,tags,completed,priority,recurrence,contexts,extensions,created_at,updated_at\n",
+		},
+		{
+			name:   "Encode json",
+			format: FormatJSON,
+			tasks:  tasks,
+			expected: `[{"id":1,"title":"Task_1","due_date":"2024-01-31T18:00:00Z","tags":["work","side-project"],"completed":false,"priority":1,"recurrence":"FREQ=WEEKLY;BYDAY=MO","contexts":["office"],"extensions":[{"key":"id","value":"1"},{"key":"note","value":"a;b"}],"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z"},` +
+				`{"id":2,"title":"Task, \"2\"","tags":[],"completed":true,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}]` + "\n",
+		},
+		{
+			name:     "Encode empty json",
+			format:   FormatJSON,
+			expected: "[]\n",
+		},
+		{
+			name:   "Encode ndjson",
+			format: FormatNDJSON,
+			tasks:  tasks,
+			expected: `{"id":1,"title":"Task_1","due_date":"2024-01-31T18:00:00Z","tags":["work","side-project"],"completed":false,"priority":1,"recurrence":"FREQ=WEEKLY;BYDAY=MO","contexts":["office"],"extensions":[{"key":"id","value":"1"},{"key":"note","value":"a;b"}],"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z"}` + "\n" +
+				`{"id":2,"title":"Task, \"2\"","tags":[],"completed":true,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			enc, err := NewEncoder(&buf, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, task := range tt.tasks {
+				if err := enc.Encode(task); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := enc.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncoder_EncodeTodoTxt(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	enc, _ := NewEncoder(&buf, FormatTodoTxt)
+
+	_ = enc.Encode(usecase.UpdateTaskOutput{
+		Title:      "Task_1",
+		DueDate:    "2024-01-31T18:00:00Z",
+		Tags:       []string{"work"},
+		Priority:   2,
+		Contexts:   []string{"office"},
+		Extensions: []usecase.Extension{{Key: "id", Value: "1"}},
+		CreatedAt:  "2024-01-01T09:00:00Z",
+	})
+	_ = enc.Encode(usecase.UpdateTaskOutput{
+		Title:       "Task_2",
+		Completed:   true,
+		CompletedAt: "2024-01-03T09:00:00Z",
+		CreatedAt:   "2024-01-01T09:00:00Z",
+	})
+	_ = enc.Close()
+
+	var expected = "(B) 2024-01-01 Task_1 +work @office due:2024-01-31 id:1\nx 2024-01-03 2024-01-01 Task_2\n"
+	if buf.String() != expected {
+		t.Errorf("Result: '%v' | Expected: '%v'", buf.String(), expected)
+	}
+}
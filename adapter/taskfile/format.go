@@ -0,0 +1,198 @@
+// タスクをCSV、JSON、NDJSON、todo.txtのファイルとして読み書きする
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSON    Format = "json"
+	FormatNDJSON  Format = "ndjson"
+	FormatTodoTxt Format = "todotxt"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
+// CSVの列 (エクスポートはこの順番で書き出す)
+var columns = []string{"id", "title", "due_date", "tags", "completed", "priority", "recurrence", "contexts", "extensions", "created_at", "updated_at"}
+
+// CSVのタグ・コンテキスト・拡張項目の列の区切り文字
+const tagSeparator = ";"
+
+// CSVの拡張項目の列で、キーと値を区切る文字
+const extensionSeparator = ":"
+
+// 拡張項目のキーと値に含まれる区切り文字を \ でエスケープする
+var extensionEscaper = strings.NewReplacer(`\`, `\\`, tagSeparator, `\`+tagSeparator)
+
+// 拡張項目を key:value;key:value の形式にする
+func joinExtensions(extensions []usecase.Extension) string {
+	var pairs = make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		pairs = append(pairs, extensionEscaper.Replace(ext.Key)+extensionSeparator+extensionEscaper.Replace(ext.Value))
+	}
+
+	return strings.Join(pairs, tagSeparator)
+}
+
+// key:value;key:value の形式から拡張項目を読み取る
+// キーに : は使えないため、最初の : で区切る
+func splitExtensions(v string) ([]usecase.Extension, error) {
+	var (
+		extensions []usecase.Extension
+		pair       strings.Builder
+		escaped    bool
+	)
+
+	var appendPair = func() error {
+		var s = pair.String()
+		pair.Reset()
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+
+		key, value, ok := strings.Cut(s, extensionSeparator)
+		if !ok {
+			return fmt.Errorf("extensions must be key:value pairs separated by ;: %q", v)
+		}
+		extensions = append(extensions, usecase.Extension{Key: key, Value: value})
+		return nil
+	}
+
+	for _, r := range v {
+		switch {
+		case escaped:
+			pair.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case string(r) == tagSeparator:
+			if err := appendPair(); err != nil {
+				return nil, err
+			}
+		default:
+			pair.WriteRune(r)
+		}
+	}
+
+	if err := appendPair(); err != nil {
+		return nil, err
+	}
+
+	return extensions, nil
+}
+
+// ; で区切った値を読み取る (空の値は除く)
+func splitList(v string) []string {
+	var values []string
+	for _, value := range strings.Split(v, tagSeparator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// 表計算ソフトが数式として解釈するセルの先頭の文字 (CSV インジェクション)
+const formulaPrefixes = "=+-@\t\r"
+
+// 数式として解釈されないよう、セルの先頭に付ける文字
+const formulaEscape = '\''
+
+// 数式として解釈される値の先頭に ' を付ける
+// 取り込みで元に戻せるよう、' の後に数式の文字が続く値にもさらに ' を付ける
+func escapeFormula(v string) string {
+	if isFormula(strings.TrimLeft(v, string(formulaEscape))) {
+		return string(formulaEscape) + v
+	}
+
+	return v
+}
+
+// escapeFormula で付けた ' を取り除く
+func unescapeFormula(v string) string {
+	if len(v) > 0 && v[0] == formulaEscape && isFormula(strings.TrimLeft(v, string(formulaEscape))) {
+		return v[1:]
+	}
+
+	return v
+}
+
+func isFormula(v string) bool {
+	return v != "" && strings.ContainsRune(formulaPrefixes, rune(v[0]))
+}
+
+// format クエリパラメータの値から形式を返却する
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatJSON, FormatNDJSON, FormatTodoTxt:
+		return f, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+// Content-Type から形式を返却する
+func FormatFromContentType(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", ErrUnsupportedFormat
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV, nil
+	case "application/json":
+		return FormatJSON, nil
+	case "application/x-ndjson", "application/jsonl":
+		return FormatNDJSON, nil
+	case "text/plain":
+		return FormatTodoTxt, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+// ファイル名の拡張子から形式を返却する
+func FormatFromFilename(name string) (Format, error) {
+	switch ext := strings.TrimPrefix(path.Ext(name), "."); ext {
+	case "jsonl":
+		return FormatNDJSON, nil
+	case "txt":
+		return FormatTodoTxt, nil
+	default:
+		return ParseFormat(ext)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatTodoTxt:
+		return "text/plain; charset=utf-8"
+	}
+
+	return "application/json"
+}
+
+// ダウンロード時のファイル名の拡張子
+func (f Format) Extension() string {
+	if f == FormatTodoTxt {
+		return "txt"
+	}
+
+	return string(f)
+}
@@ -0,0 +1,114 @@
+package taskfile
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/todotxt"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+type todoTxtEncoder struct {
+	w io.Writer
+}
+
+func (e todoTxtEncoder) Encode(task usecase.UpdateTaskOutput) error {
+	_, err := io.WriteString(e.w, todotxt.Marshal(outputToTask(task))+"\n")
+	return err
+}
+
+func (e todoTxtEncoder) Close() error {
+	return nil
+}
+
+type todoTxtDecoder struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func (d *todoTxtDecoder) Next() (Record, error) {
+	for d.s.Scan() {
+		d.line++
+
+		if strings.TrimSpace(d.s.Text()) == "" {
+			continue
+		}
+
+		var record = Record{Line: d.line}
+
+		task, err := todotxt.Unmarshal(d.s.Text())
+		if err != nil {
+			record.Err = err
+			return record, nil
+		}
+
+		// 作成日と完了日はデータベースで設定するため取り込まない
+		// todo.txt では +WorkProject のように大文字を含むプロジェクト・コンテキストが多いため、小文字にして取り込む
+		record.Task = usecase.UpdateTaskInput{
+			Title:      task.Title,
+			DueDate:    task.DueDate,
+			Tags:       toLower(task.Tags),
+			Completed:  task.Completed,
+			Priority:   task.Priority,
+			Contexts:   toLower(task.Contexts),
+			Extensions: usecase.NewExtensions(task.Extensions),
+		}
+
+		return record, nil
+	}
+
+	if err := d.s.Err(); err != nil {
+		return Record{}, err
+	}
+
+	return Record{}, io.EOF
+}
+
+func toLower(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	var lower = make([]string, len(values))
+	for i, v := range values {
+		lower[i] = strings.ToLower(v)
+	}
+
+	return lower
+}
+
+// 出力用に文字列へ変換されたタスクを todo.txt に変換できるよう戻す
+func outputToTask(output usecase.UpdateTaskOutput) domain.Task {
+	var task = domain.Task{
+		ID:          output.ID,
+		Title:       output.Title,
+		DueDate:     parseOptionalTime(output.DueDate),
+		Tags:        output.Tags,
+		Completed:   output.Completed,
+		Priority:    output.Priority,
+		Contexts:    output.Contexts,
+		CompletedAt: parseOptionalTime(output.CompletedAt),
+	}
+
+	for _, ext := range output.Extensions {
+		task.Extensions = append(task.Extensions, domain.Extension{Key: ext.Key, Value: ext.Value})
+	}
+
+	if createdAt := parseOptionalTime(output.CreatedAt); createdAt != nil {
+		task.CreatedAt = *createdAt
+	}
+
+	return task
+}
+
+func parseOptionalTime(s string) *time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,185 @@
+// タスクと todo.txt 形式 (https://github.com/todotxt/todo.txt) の行を相互に変換する
+package todotxt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+const (
+	dateFormat = "2006-01-02"
+
+	// 期日と、完了したタスクの優先度を保持するキー
+	keyDue      = "due"
+	keyPriority = "pri"
+
+	// タスクの優先度 (1-9) に対応する todo.txt の優先度の範囲
+	// J 以降は最も低い優先度 9 として扱う
+	maxPriority = 9
+)
+
+var ErrEmptyLine = errors.New("todo.txt line is empty")
+
+// タスクを todo.txt の1行に変換する
+//
+//	x 2024-01-02 2024-01-01 Title +tag @context due:2024-01-31 pri:A key:value
+//	(A) 2024-01-01 Title +tag @context due:2024-01-31 key:value
+//
+// 完了したタスクの優先度は規約に従い pri:X として書き出す
+func Marshal(task domain.Task) string {
+	var parts []string
+
+	if task.Completed {
+		parts = append(parts, "x")
+		if task.CompletedAt != nil {
+			parts = append(parts, task.CompletedAt.UTC().Format(dateFormat))
+		}
+	} else if task.Priority > 0 {
+		parts = append(parts, "("+priorityLetter(task.Priority)+")")
+	}
+
+	// 完了日のない完了済みのタスクには作成日を書けない
+	if !task.CreatedAt.IsZero() && (!task.Completed || task.CompletedAt != nil) {
+		parts = append(parts, task.CreatedAt.UTC().Format(dateFormat))
+	}
+
+	if task.Title != "" {
+		parts = append(parts, task.Title)
+	}
+
+	for _, tag := range task.Tags {
+		parts = append(parts, "+"+tag)
+	}
+
+	for _, c := range task.Contexts {
+		parts = append(parts, "@"+c)
+	}
+
+	if task.DueDate != nil {
+		parts = append(parts, keyDue+":"+task.DueDate.UTC().Format(dateFormat))
+	}
+
+	if task.Completed && task.Priority > 0 {
+		parts = append(parts, keyPriority+":"+priorityLetter(task.Priority))
+	}
+
+	for _, ext := range task.Extensions {
+		parts = append(parts, ext.Key+":"+ext.Value)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// todo.txt の1行をタスクに変換する
+// +project はタグ、@context はコンテキスト、due: は期日とし、それ以外の key:value は順番通りに拡張項目として保持する
+// 日付は UTC の 0 時として扱う
+func Unmarshal(line string) (domain.Task, error) {
+	var fields = strings.Fields(line)
+	if len(fields) == 0 {
+		return domain.Task{}, ErrEmptyLine
+	}
+
+	var task domain.Task
+
+	if fields[0] == "x" {
+		task.Completed = true
+		fields = fields[1:]
+
+		if d, ok := parseDate(fields); ok {
+			task.CompletedAt = &d
+			fields = fields[1:]
+		}
+	} else if p, ok := parsePriority(fields); ok {
+		task.Priority = p
+		fields = fields[1:]
+	}
+
+	if d, ok := parseDate(fields); ok {
+		task.CreatedAt = d
+		fields = fields[1:]
+	}
+
+	var words []string
+	for _, field := range fields {
+		switch {
+		case len(field) > 1 && field[0] == '+':
+			task.Tags = append(task.Tags, field[1:])
+		case len(field) > 1 && field[0] == '@':
+			task.Contexts = append(task.Contexts, field[1:])
+		default:
+			key, value, ok := splitKeyValue(field)
+			if !ok {
+				words = append(words, field)
+				continue
+			}
+
+			switch {
+			case key == keyDue:
+				due, err := time.Parse(dateFormat, value)
+				if err != nil {
+					return domain.Task{}, fmt.Errorf("due must be a YYYY-MM-DD date: %q", value)
+				}
+				task.DueDate = &due
+			case key == keyPriority && task.Priority == 0 && isPriorityLetter(value):
+				task.Priority = letterPriority(value[0])
+			default:
+				task.Extensions = append(task.Extensions, domain.Extension{Key: key, Value: value})
+			}
+		}
+	}
+
+	task.Title = strings.Join(words, " ")
+
+	return task, nil
+}
+
+// 先頭のフィールドが日付であれば返却する
+func parseDate(fields []string) (time.Time, bool) {
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+
+	d, err := time.Parse(dateFormat, fields[0])
+	return d, err == nil
+}
+
+// 先頭のフィールドが "(A)" 形式の優先度であれば返却する
+func parsePriority(fields []string) (int, bool) {
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	var f = fields[0]
+	if len(f) != 3 || f[0] != '(' || f[2] != ')' || !isPriorityLetter(f[1:2]) {
+		return 0, false
+	}
+
+	return letterPriority(f[1]), true
+}
+
+// "key:value" を分割する
+// URL ("https://...") は拡張項目として扱わない
+func splitKeyValue(field string) (string, string, bool) {
+	key, value, ok := strings.Cut(field, ":")
+	if !ok || key == "" || value == "" || strings.HasPrefix(value, "//") {
+		return "", "", false
+	}
+
+	return key, value, true
+}
+
+func isPriorityLetter(s string) bool {
+	return len(s) == 1 && s[0] >= 'A' && s[0] <= 'Z'
+}
+
+func letterPriority(letter byte) int {
+	return min(int(letter-'A')+1, maxPriority)
+}
+
+func priorityLetter(priority int) string {
+	return string(rune('A' + min(priority, maxPriority) - 1))
+}
@@ -0,0 +1,169 @@
+package todotxt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	var d = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		line          string
+		expected      domain.Task
+		expectedError error
+	}{
+		{
+			name: "Open task with priority and dates",
+			line: "(A) 2024-01-01 Call mom +family @phone due:2024-01-31 rec:1w",
+			expected: domain.Task{
+				Title:      "Call mom",
+				Priority:   1,
+				CreatedAt:  *date(2024, 1, 1),
+				Tags:       []string{"family"},
+				Contexts:   []string{"phone"},
+				DueDate:    date(2024, 1, 31),
+				Extensions: []domain.Extension{{Key: "rec", Value: "1w"}},
+			},
+		},
+		{
+			name: "Completed task keeps priority from pri",
+			line: "x 2024-01-02 2024-01-01 Pay rent pri:C",
+			expected: domain.Task{
+				Title:       "Pay rent",
+				Completed:   true,
+				CompletedAt: date(2024, 1, 2),
+				CreatedAt:   *date(2024, 1, 1),
+				Priority:    3,
+			},
+		},
+		{
+			name: "Low priorities are clamped",
+			line: "(Z) Someday",
+			expected: domain.Task{
+				Title:    "Someday",
+				Priority: 9,
+			},
+		},
+		{
+			name: "Duplicated extensions and urls",
+			line: "Read https://example.com/a id:1 id:2 x (A)",
+			expected: domain.Task{
+				Title:      "Read https://example.com/a x (A)",
+				Extensions: []domain.Extension{{Key: "id", Value: "1"}, {Key: "id", Value: "2"}},
+			},
+		},
+		{
+			name:          "Invalid due date",
+			line:          "Task due:tomorrow",
+			expectedError: errors.New(`due must be a YYYY-MM-DD date: "tomorrow"`),
+		},
+		{
+			name:          "Empty line",
+			line:          "   ",
+			expectedError: ErrEmptyLine,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Unmarshal(tt.line)
+			if (err != nil || tt.expectedError != nil) && (err == nil || tt.expectedError == nil || err.Error() != tt.expectedError.Error()) {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%+v'", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		task     domain.Task
+		expected string
+	}{
+		{
+			name: "Open task",
+			task: domain.Task{
+				Title:      "Call mom",
+				Priority:   1,
+				CreatedAt:  time.Date(2024, 1, 1, 15, 30, 0, 0, time.UTC),
+				Tags:       []string{"family"},
+				Contexts:   []string{"phone"},
+				DueDate:    date(2024, 1, 31),
+				Extensions: []domain.Extension{{Key: "rec", Value: "1w"}},
+			},
+			expected: "(A) 2024-01-01 Call mom +family @phone due:2024-01-31 rec:1w",
+		},
+		{
+			name: "Completed task",
+			task: domain.Task{
+				Title:       "Pay rent",
+				Completed:   true,
+				CompletedAt: date(2024, 1, 2),
+				CreatedAt:   *date(2024, 1, 1),
+				Priority:    3,
+			},
+			expected: "x 2024-01-02 2024-01-01 Pay rent pri:C",
+		},
+		{
+			name: "Completed task without completion date",
+			task: domain.Task{
+				Title:     "Pay rent",
+				Completed: true,
+				CreatedAt: *date(2024, 1, 1),
+			},
+			expected: "x Pay rent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Marshal(tt.task); result != tt.expected {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+// 拡張項目を含む行は、書き出した後に読み込んでも同じ内容になる
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var lines = []string{
+		"(B) 2024-01-01 Plan trip +travel @home due:2024-03-01 rec:+1m t:2024-02-01 id:1 id:2",
+		"x 2024-01-05 2024-01-01 Renew passport +travel pri:A h:1 url:example.com/x",
+		"Unsorted note lang:ja",
+	}
+
+	for _, line := range lines {
+		task, err := Unmarshal(line)
+		if err != nil {
+			t.Fatalf("[TestCase '%s'] unexpected error: %v", line, err)
+		}
+
+		if result := Marshal(task); result != line {
+			t.Errorf("[TestCase '%s'] Result: '%v'", line, result)
+		}
+
+		again, err := Unmarshal(Marshal(task))
+		if err != nil || !reflect.DeepEqual(again.Extensions, task.Extensions) {
+			t.Errorf("[TestCase '%s'] Extensions: '%v' | Expected: '%v'", line, again.Extensions, task.Extensions)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type (
+	// 終了した区間を送る先
+	// 区間を終了したゴルーチンから呼び出されるため、時間のかかる処理はしない
+	Exporter interface {
+		Export(SpanData)
+	}
+
+	// 終了した区間
+	SpanData struct {
+		TraceID      string                 `json:"trace_id"`
+		SpanID       string                 `json:"span_id"`
+		ParentSpanID string                 `json:"parent_span_id,omitempty"`
+		Name         string                 `json:"name"`
+		StartTime    time.Time              `json:"start_time"`
+		EndTime      time.Time              `json:"end_time"`
+		Attributes   map[string]interface{} `json:"attributes,omitempty"`
+		// 区間が失敗した場合のエラー
+		Error string `json:"error,omitempty"`
+	}
+)
+
+// 区間を1行ずつ JSON で書き込む
+type stdoutExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewStdoutExporter(w io.Writer) Exporter {
+	return &stdoutExporter{enc: json.NewEncoder(w)}
+}
+
+func (e *stdoutExporter) Export(data SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	_ = e.enc.Encode(data)
+}
+
+// 区間をメモリに保持する (テスト用)
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) Export(data SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, data)
+}
+
+// 終了した順に区間を返却する
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]SpanData(nil), e.spans...)
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// W3C Trace Context のヘッダー
+// https://www.w3.org/TR/trace-context/
+const HeaderTraceparent = "traceparent"
+
+const (
+	traceparentVersion = "00"
+	flagSampled        = 0x01
+)
+
+type (
+	TraceID [16]byte
+	SpanID  [8]byte
+)
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+func (s SpanID) String() string  { return hex.EncodeToString(s[:]) }
+
+func (t TraceID) isValid() bool { return t != TraceID{} }
+func (s SpanID) isValid() bool  { return s != SpanID{} }
+
+// 区間をトレースの中で識別する値 (traceparent で伝播する)
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	// 記録の対象か
+	Sampled bool
+}
+
+// traceparent ヘッダーの値を解析する
+// 形式が不正な場合や、ID がすべて 0 の場合は false を返却する
+// 将来のバージョンは、先頭の項目がバージョン 00 と同じ形式であれば受け付ける
+func ParseTraceparent(s string) (SpanContext, bool) {
+	var parts = strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return SpanContext{}, false
+	}
+	if parts[0] == traceparentVersion && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+
+	var (
+		sc    SpanContext
+		flags [1]byte
+	)
+	if !decodeHex(sc.TraceID[:], parts[1]) || !decodeHex(sc.SpanID[:], parts[2]) || !decodeHex(flags[:], parts[3]) {
+		return SpanContext{}, false
+	}
+
+	if !sc.TraceID.isValid() || !sc.SpanID.isValid() {
+		return SpanContext{}, false
+	}
+
+	sc.Sampled = flags[0]&flagSampled != 0
+	return sc, true
+}
+
+// traceparent ヘッダーの値を返却する
+func (sc SpanContext) Traceparent() string {
+	var flags = "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return traceparentVersion + "-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// 小文字の16進数だけを受け付ける
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return false
+	}
+
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	_, _ = rand.Read(id[:])
+	return id
+}
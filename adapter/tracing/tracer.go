@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type tracingContextKey string
+
+const (
+	keySpan   tracingContextKey = "SpanContextKey"
+	keyRemote tracingContextKey = "RemoteSpanContextKey"
+)
+
+// 区間を記録し、終了した区間を Exporter に渡す
+type Tracer struct {
+	exporter Exporter
+}
+
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// コンテキストの区間、なければ他のサービスから受け取った区間の子として開始する
+// どちらもなければ新しいトレースを開始する
+// 親が記録の対象でなければ、子も記録しない
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, domain.Span) {
+	var span = &span{
+		exporter: t.exporter,
+		data: SpanData{
+			Name:      name,
+			StartTime: time.Now(),
+		},
+	}
+
+	if parent, ok := SpanContextFromContext(ctx); ok {
+		span.sc = SpanContext{TraceID: parent.TraceID, SpanID: newSpanID(), Sampled: parent.Sampled}
+		span.data.ParentSpanID = parent.SpanID.String()
+	} else {
+		span.sc = SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true}
+	}
+
+	span.data.TraceID = span.sc.TraceID.String()
+	span.data.SpanID = span.sc.SpanID.String()
+
+	return context.WithValue(ctx, keySpan, span), span
+}
+
+// 他のサービスから受け取った区間をコンテキストにセットし返却する
+func WithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, keyRemote, sc)
+}
+
+// コンテキストにセットされた区間を返却する
+// 区間がなければ、他のサービスから受け取った区間を返却する
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	if s, ok := ctx.Value(keySpan).(*span); ok {
+		return s.sc, true
+	}
+
+	sc, ok := ctx.Value(keyRemote).(SpanContext)
+	return sc, ok
+}
+
+// ログにトレースと区間のIDを付ける (logger.RegisterContextFields に登録する)
+func LogFields(ctx context.Context) logger.Fields {
+	var s, ok = ctx.Value(keySpan).(*span)
+	if !ok {
+		return nil
+	}
+
+	return logger.Fields{
+		"trace_id": s.sc.TraceID.String(),
+		"span_id":  s.sc.SpanID.String(),
+	}
+}
+
+type span struct {
+	sc       SpanContext
+	exporter Exporter
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+func (s *span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{})
+	}
+	s.data.Attributes[key] = value
+}
+
+func (s *span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Error = err.Error()
+}
+
+// 2回目以降の呼び出しは何もしない
+func (s *span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	var data = s.data
+	s.mu.Unlock()
+
+	if s.sc.Sampled {
+		s.exporter.Export(data)
+	}
+}
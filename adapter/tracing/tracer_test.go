@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+		sampled  bool
+	}{
+		{
+			name:     "Sampled",
+			header:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expected: true,
+			sampled:  true,
+		},
+		{
+			name:     "Not sampled",
+			header:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			expected: true,
+		},
+		{
+			name:     "Future version with extra fields",
+			header:   "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			expected: true,
+			sampled:  true,
+		},
+		{
+			name:   "Extra fields in version 00",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		},
+		{
+			name:   "Invalid version",
+			header: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:   "Zero trace id",
+			header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name:   "Upper case",
+			header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:   "Short span id",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",
+		},
+		{
+			name: "Empty",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sc, ok := ParseTraceparent(tt.header)
+			if ok != tt.expected {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, ok, tt.expected)
+			}
+
+			if !ok {
+				return
+			}
+
+			if sc.Sampled != tt.sampled {
+				t.Errorf("[TestCase '%s'] Sampled: '%v' | Expected: '%v'", tt.name, sc.Sampled, tt.sampled)
+			}
+
+			if expected := "4bf92f3577b34da6a3ce929d0e0e4736"; sc.TraceID.String() != expected {
+				t.Errorf("[TestCase '%s'] TraceID: '%v' | Expected: '%v'", tt.name, sc.TraceID, expected)
+			}
+		})
+	}
+}
+
+func TestTracer_Start(t *testing.T) {
+	t.Parallel()
+
+	var (
+		exporter = NewInMemoryExporter()
+		tracer   = NewTracer(exporter)
+	)
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	var ctx = WithRemoteSpanContext(context.Background(), remote)
+
+	ctx, parent := tracer.Start(ctx, "GET /v1/tasks")
+	_, child := tracer.Start(ctx, "db.query")
+	child.SetAttribute("db.system", "postgresql")
+	child.RecordError(errors.New("connection refused"))
+	child.End()
+	child.End()
+	parent.End()
+
+	var spans = exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("[TestCase 'Exported spans'] Result: '%d' | Expected: '2'", len(spans))
+	}
+
+	var db, server = spans[0], spans[1]
+
+	// 受け取ったトレースの続きとして記録する
+	if server.TraceID != remote.TraceID.String() || server.ParentSpanID != remote.SpanID.String() {
+		t.Errorf("[TestCase 'Remote parent'] Result: '%v' | Expected: '%v'", server, remote.Traceparent())
+	}
+
+	if db.TraceID != server.TraceID || db.ParentSpanID != server.SpanID {
+		t.Errorf("[TestCase 'Child span'] Result: '%v' | Expected parent: '%v'", db, server.SpanID)
+	}
+
+	if db.Error != "connection refused" || db.Attributes["db.system"] != "postgresql" {
+		t.Errorf("[TestCase 'Span data'] Result: '%v'", db)
+	}
+
+	// ログには現在の区間のIDを付ける
+	var fields = LogFields(ctx)
+	if fields["trace_id"] != server.TraceID || fields["span_id"] != server.SpanID {
+		t.Errorf("[TestCase 'Log fields'] Result: '%v' | Expected: '%v %v'", fields, server.TraceID, server.SpanID)
+	}
+}
+
+func TestTracer_Start_NotSampled(t *testing.T) {
+	t.Parallel()
+
+	var (
+		exporter = NewInMemoryExporter()
+		tracer   = NewTracer(exporter)
+	)
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	ctx, parent := tracer.Start(WithRemoteSpanContext(context.Background(), remote), "GET /v1/tasks")
+	_, child := tracer.Start(ctx, "db.query")
+	child.End()
+	parent.End()
+
+	// 呼び出し元が記録しないトレースは記録しない
+	if spans := exporter.Spans(); len(spans) != 0 {
+		t.Errorf("Result: '%v' | Expected: '[]'", spans)
+	}
+}
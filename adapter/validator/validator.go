@@ -1,6 +1,24 @@
 package validator
 
+import "context"
+
+// バリデーター
+// 検証結果は戻り値でのみ返却し、インスタンスに状態を持たないこと
+// (1つのインスタンスが全てのリクエストで共有されるため)
 type Validator interface {
-	Validate(interface{}) error
-	Messages() []string
+	// 違反がなければ空のスライスを返却する
+	// メッセージはコンテキストにセットされた言語で翻訳する
+	Validate(context.Context, interface{}) []Violation
+}
+
+// フィールド単位のバリデーションエラー
+type Violation struct {
+	// JSONのフィールド名 (ネストしている場合は "tags[0]" のようなパス)
+	Field string `json:"field"`
+	// 違反したルール名 (例: "required", "lte")
+	Rule string `json:"rule"`
+	// ルールのパラメータ (例: "lte=15" の "15")
+	Param string `json:"param,omitempty"`
+	// 翻訳済みのメッセージ
+	Message string `json:"message"`
 }
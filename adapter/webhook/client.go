@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// 配信先として許可しないアドレスへの接続を表すエラー
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// キャリアグレードNAT (RFC 6598) のアドレス
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// 配信先として使用できるアドレスか
+// ループバック・プライベート・リンクローカル・未指定・マルチキャストのアドレスは、内部のサービスに届くため許可しない
+func IsPublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// 配信に使用する HTTP クライアントを返却する
+// 名前解決の結果を差し替えられても内部のサービスに送らないよう、実際に接続するアドレスを allow で確認する
+// リダイレクトには従わず、3xx の応答をそのまま返却する (リダイレクト先の確認を避けるため)
+func newClient(timeout time.Duration, allow func(net.IP) bool) *http.Client {
+	var dialer = &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !allow(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+
+			return nil
+		},
+	}
+
+	var transport = http.DefaultTransport.(*http.Transport).Clone()
+	// プロキシを経由すると接続先のアドレスを確認できないため使用しない
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/doglapping707/todo-api-go/adapter/presenter"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/usecase"
+	"github.com/pkg/errors"
+)
+
+// ドメインイベントを購読者ごとの配信として登録するハンドラー
+// relay のトランザクション内で呼び出され、イベントが発行済みになるのと同時にコミットされる
+type eventHandler struct {
+	webhooks  domain.WebhookRepository
+	presenter usecase.TaskEventPresenter
+}
+
+func NewEventHandler(webhooks domain.WebhookRepository) domain.EventHandler {
+	return eventHandler{
+		webhooks:  webhooks,
+		presenter: presenter.NewTaskEventPresenter(),
+	}
+}
+
+func (h eventHandler) Handle(ctx context.Context, event domain.Event) error {
+	// 再送されても同じ ID になるよう、ドメインイベントの ID を使用する
+	var payload = h.presenter.Output(event)
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "error encoding webhook event")
+	}
+
+	return h.webhooks.Enqueue(ctx, domain.WebhookEvent{
+		ID:        payload.ID,
+		Type:      payload.Type,
+		AccountID: event.AccountID,
+		Payload:   b,
+	})
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type mockEnqueue struct {
+	domain.WebhookRepository
+	events *[]domain.WebhookEvent
+}
+
+func (m mockEnqueue) Enqueue(_ context.Context, event domain.WebhookEvent) error {
+	*m.events = append(*m.events, event)
+	return nil
+}
+
+func TestEventHandler_Handle(t *testing.T) {
+	var occurredAt = time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		event           domain.Event
+		expectedPayload string
+	}{
+		{
+			name: "Task created",
+			event: domain.Event{
+				ID:         42,
+				Type:       domain.EventTaskCreated,
+				TaskID:     1,
+				AccountID:  7,
+				Task:       &domain.Task{ID: 1, Title: "Task"},
+				OccurredAt: occurredAt,
+			},
+			expectedPayload: `{"id":"42","type":"task.created","created_at":"2024-01-10T09:00:00Z","data":{"id":1,"title":"Task","tags":[],"completed":false,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}}`,
+		},
+		{
+			name: "Task deleted",
+			event: domain.Event{
+				ID:         43,
+				Type:       domain.EventTaskDeleted,
+				TaskID:     5,
+				AccountID:  7,
+				OccurredAt: occurredAt.In(time.FixedZone("JST", 9*60*60)),
+			},
+			expectedPayload: `{"id":"43","type":"task.deleted","created_at":"2024-01-10T09:00:00Z","data":{"id":5}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []domain.WebhookEvent
+			if err := NewEventHandler(mockEnqueue{events: &events}).Handle(context.Background(), tt.event); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(events) != 1 {
+				t.Fatalf("[TestCase '%s'] Result: '%d' events | Expected: '1'", tt.name, len(events))
+			}
+
+			if events[0].Type != tt.event.Type || string(events[0].Payload) != tt.expectedPayload {
+				t.Errorf("[TestCase '%s'] Result: '%s' | Expected: '%s'", tt.name, events[0].Payload, tt.expectedPayload)
+			}
+
+			if events[0].AccountID != tt.event.AccountID {
+				t.Errorf("[TestCase '%s'] AccountID: '%v' | Expected: '%v'", tt.name, events[0].AccountID, tt.event.AccountID)
+			}
+		})
+	}
+}
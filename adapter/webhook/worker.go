@@ -0,0 +1,228 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/health"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// 現在時刻 (テストで差し替える)
+var now = time.Now
+
+const (
+	// 1回に取り出す配信の件数
+	defaultBatchSize = 20
+	// 配信がない場合に次に確認するまでの間隔
+	defaultPollInterval = time.Second
+	// 取り出した配信を他のワーカーから隠す時間 (送信のタイムアウトより長くする)
+	defaultLease = time.Minute
+	// 1回の送信のタイムアウト
+	defaultTimeout = 10 * time.Second
+	// 1つの配信を試行する最大回数
+	defaultMaxAttempts = 10
+	// 再試行の間隔 (試行ごとに2倍にし、最大値で打ち切る)
+	defaultBaseBackoff = 30 * time.Second
+	defaultMaxBackoff  = 6 * time.Hour
+	// 購読を無効にする連続失敗回数
+	defaultDisableAfter = 15
+)
+
+// 配信を取り出し、購読者のURLに送信するワーカー
+type Worker struct {
+	repo   domain.WebhookRepository
+	client *http.Client
+	log    logger.Logger
+
+	batchSize    int
+	pollInterval time.Duration
+	lease        time.Duration
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	disableAfter int
+}
+
+func NewWorker(repo domain.WebhookRepository, log logger.Logger) Worker {
+	return Worker{
+		repo:   repo,
+		client: newClient(defaultTimeout, IsPublicAddress),
+		log:    log,
+
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+		lease:        defaultLease,
+		maxAttempts:  defaultMaxAttempts,
+		baseBackoff:  defaultBaseBackoff,
+		maxBackoff:   defaultMaxBackoff,
+		disableAfter: defaultDisableAfter,
+	}
+}
+
+// コンテキストがキャンセルされるまで配信を続ける
+func (w Worker) Run(ctx context.Context) {
+	w.log.Infof("Starting webhook worker")
+
+	var timer = time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		health.Progress(ctx)
+
+		select {
+		case <-ctx.Done():
+			w.log.Infof("Webhook worker stopped")
+			return
+		case <-timer.C:
+		}
+
+		n, err := w.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.log.WithError(err).Errorf("error delivering webhooks")
+		}
+
+		// 取り出した件数が上限に達した場合は、残りがあるとみなしてすぐに続ける
+		if n == w.batchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(w.pollInterval)
+		}
+	}
+}
+
+// Run が進捗を記録する最長の間隔 (配信がない場合の待ち時間と、1回の送信のタイムアウト)
+func (w Worker) ProgressInterval() time.Duration {
+	return w.pollInterval + defaultTimeout
+}
+
+// 配信時刻を過ぎた配信を取り出して送信し、送信した件数を返却する
+func (w Worker) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := w.repo.ClaimDue(ctx, w.batchSize, w.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		var result = w.deliver(ctx, delivery)
+		// 1回の取り出しで送信に時間がかかっても、止まっているとみなされないようにする
+		health.Progress(ctx)
+
+		// 停止中に中断した送信は記録せず、lease の経過後に再送する
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		if err := w.repo.RecordAttempt(ctx, result); err != nil {
+			return 0, err
+		}
+
+		w.logResult(delivery, result)
+	}
+
+	return len(deliveries), nil
+}
+
+// 配信を1回送信し、その結果を返却する
+func (w Worker) deliver(ctx context.Context, delivery domain.WebhookDelivery) domain.WebhookAttemptResult {
+	var (
+		start  = now()
+		result = domain.WebhookAttemptResult{
+			DeliveryID:     delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			WebhookAttempt: domain.WebhookAttempt{
+				Attempt:     delivery.Attempts + 1,
+				AttemptedAt: start,
+			},
+			DisableAfter: w.disableAfter,
+		}
+	)
+
+	statusCode, err := w.send(ctx, delivery, start)
+	result.Duration = now().Sub(start)
+	result.StatusCode = statusCode
+
+	if err == nil {
+		result.Succeeded = true
+		return result
+	}
+
+	result.Error = err.Error()
+	if result.Attempt < w.maxAttempts {
+		var retryAt = start.Add(w.backoff(result.Attempt))
+		result.RetryAt = &retryAt
+	}
+
+	return result
+}
+
+// 署名を付けてペイロードをPOSTする
+// 2xx 以外の応答はエラーとして扱う
+func (w Worker) send(ctx context.Context, delivery domain.WebhookDelivery, at time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	var timestamp = at.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-api-webhook/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	// 接続を再利用できるように、応答の本文を一定量まで読み捨てる
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// attempt 回目の試行が失敗した後、次の試行までの間隔
+func (w Worker) backoff(attempt int) time.Duration {
+	var d = w.baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= w.maxBackoff {
+			return w.maxBackoff
+		}
+	}
+
+	return d
+}
+
+func (w Worker) logResult(delivery domain.WebhookDelivery, result domain.WebhookAttemptResult) {
+	var entry = w.log.WithFields(logger.Fields{
+		"key":             "webhook_delivery",
+		"delivery_id":     delivery.ID,
+		"subscription_id": delivery.SubscriptionID,
+		"event_type":      delivery.EventType,
+		"attempt":         result.Attempt,
+		"http_status":     result.StatusCode,
+	})
+
+	switch {
+	case result.Succeeded:
+		entry.Infof("success delivering webhook")
+	case result.RetryAt != nil:
+		entry.Warnf("error delivering webhook, will retry: %s", result.Error)
+	default:
+		entry.Errorf("error delivering webhook, giving up: %s", result.Error)
+	}
+}
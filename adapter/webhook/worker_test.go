@@ -0,0 +1,295 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/log"
+)
+
+type mockWebhookRepo struct {
+	domain.WebhookRepository
+	deliveries []domain.WebhookDelivery
+	results    *[]domain.WebhookAttemptResult
+}
+
+func (m mockWebhookRepo) ClaimDue(_ context.Context, _ int, _ time.Duration) ([]domain.WebhookDelivery, error) {
+	return m.deliveries, nil
+}
+
+func (m mockWebhookRepo) RecordAttempt(_ context.Context, result domain.WebhookAttemptResult) error {
+	*m.results = append(*m.results, result)
+	return nil
+}
+
+func TestWorker_RunOnce(t *testing.T) {
+	var fixedNow = time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+	defer func() { now = time.Now }()
+
+	const (
+		secret  = "0123456789abcdef"
+		payload = `{"id":"e1","type":"task.created","created_at":"2024-01-10T09:00:00Z","data":{"id":1}}`
+	)
+
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		// 期待値
+		expectedSucceeded bool
+		expectedStatus    int
+		expectedError     string
+		expectedRetryAt   *time.Time
+	}{
+		{
+			name:              "Delivered",
+			status:            http.StatusNoContent,
+			expectedSucceeded: true,
+			expectedStatus:    http.StatusNoContent,
+		},
+		{
+			name:            "Server error is retried",
+			status:          http.StatusInternalServerError,
+			attempts:        2,
+			expectedStatus:  http.StatusInternalServerError,
+			expectedError:   "unexpected status 500",
+			expectedRetryAt: timePtr(fixedNow.Add(2 * time.Minute)),
+		},
+		{
+			name:           "Gives up after the last attempt",
+			status:         http.StatusGone,
+			attempts:       defaultMaxAttempts - 1,
+			expectedStatus: http.StatusGone,
+			expectedError:  "unexpected status 410",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			var (
+				results []domain.WebhookAttemptResult
+				repo    = mockWebhookRepo{
+					deliveries: []domain.WebhookDelivery{{
+						ID:             7,
+						SubscriptionID: 3,
+						EventID:        "e1",
+						EventType:      domain.EventTaskCreated,
+						Payload:        []byte(payload),
+						Attempts:       tt.attempts,
+						URL:            server.URL,
+						Secret:         secret,
+					}},
+					results: &results,
+				}
+				worker = NewWorker(repo, log.LoggerMock{})
+			)
+			// テスト用のサーバーはループバックアドレスで待ち受ける
+			worker.client = newClient(defaultTimeout, allowAll)
+
+			n, err := worker.RunOnce(context.Background())
+			if err != nil || n != 1 {
+				t.Fatalf("[TestCase '%s'] Result: '%v', '%v' | Expected: '1', '<nil>'", tt.name, n, err)
+			}
+
+			// 署名を検証できること
+			timestamp, _ := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+			if string(body) != payload || !Verify(secret, timestamp, body, received.Header.Get(HeaderSignature)) {
+				t.Errorf("[TestCase '%s'] invalid signature '%s' for body '%s'", tt.name, received.Header.Get(HeaderSignature), body)
+			}
+
+			if received.Header.Get(HeaderEvent) != domain.EventTaskCreated || received.Header.Get(HeaderEventID) != "e1" {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected event headers", tt.name, received.Header)
+			}
+
+			var result = results[0]
+			if result.Succeeded != tt.expectedSucceeded ||
+				result.StatusCode != tt.expectedStatus ||
+				result.Error != tt.expectedError ||
+				result.Attempt != tt.attempts+1 ||
+				result.DeliveryID != 7 ||
+				result.SubscriptionID != 3 ||
+				result.DisableAfter != defaultDisableAfter {
+				t.Errorf("[TestCase '%s'] Result: '%+v'", tt.name, result)
+			}
+
+			if !equalTimePtr(result.RetryAt, tt.expectedRetryAt) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result.RetryAt, tt.expectedRetryAt)
+			}
+		})
+	}
+}
+
+func TestWorker_RunOnce_ConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	var url = server.URL
+	server.Close()
+
+	var (
+		results []domain.WebhookAttemptResult
+		repo    = mockWebhookRepo{
+			deliveries: []domain.WebhookDelivery{{ID: 1, SubscriptionID: 1, URL: url, Payload: []byte(`{}`)}},
+			results:    &results,
+		}
+	)
+
+	var worker = NewWorker(repo, log.LoggerMock{})
+	worker.client = newClient(defaultTimeout, allowAll)
+
+	if _, err := worker.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if results[0].Succeeded || results[0].StatusCode != 0 || results[0].Error == "" || results[0].RetryAt == nil {
+		t.Errorf("Result: '%+v' | Expected a failed attempt to be retried", results[0])
+	}
+}
+
+func allowAll(net.IP) bool { return true }
+
+func TestWorker_RunOnce_ForbiddenAddress(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "Loopback", url: server.URL},
+		{name: "Localhost name", url: strings.Replace(server.URL, "127.0.0.1", "localhost", 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				results []domain.WebhookAttemptResult
+				repo    = mockWebhookRepo{
+					deliveries: []domain.WebhookDelivery{{ID: 1, SubscriptionID: 1, URL: tt.url, Payload: []byte(`{}`)}},
+					results:    &results,
+				}
+			)
+
+			if _, err := NewWorker(repo, log.LoggerMock{}).RunOnce(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if results[0].Succeeded || !strings.Contains(results[0].Error, ErrForbiddenAddress.Error()) {
+				t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%v'", tt.name, results[0], ErrForbiddenAddress)
+			}
+		})
+	}
+
+	if called {
+		t.Errorf("Result: the loopback server was called | Expected: no request")
+	}
+}
+
+func TestWorker_RunOnce_DoesNotFollowRedirects(t *testing.T) {
+	var called bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer target.Close()
+
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer redirect.Close()
+
+	var (
+		results []domain.WebhookAttemptResult
+		repo    = mockWebhookRepo{
+			deliveries: []domain.WebhookDelivery{{ID: 1, SubscriptionID: 1, URL: redirect.URL, Payload: []byte(`{}`)}},
+			results:    &results,
+		}
+		worker = NewWorker(repo, log.LoggerMock{})
+	)
+	worker.client = newClient(defaultTimeout, allowAll)
+
+	if _, err := worker.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if called || results[0].Succeeded || results[0].StatusCode != http.StatusFound {
+		t.Errorf("Result: '%+v', called '%v' | Expected: a failed attempt with status 302", results[0], called)
+	}
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{ip: "93.184.216.34", expected: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", expected: true},
+		{ip: "127.0.0.1", expected: false},
+		{ip: "::1", expected: false},
+		{ip: "10.0.0.1", expected: false},
+		{ip: "172.16.0.1", expected: false},
+		{ip: "192.168.1.1", expected: false},
+		{ip: "169.254.169.254", expected: false},
+		{ip: "100.64.0.1", expected: false},
+		{ip: "0.0.0.0", expected: false},
+		{ip: "::ffff:127.0.0.1", expected: false},
+		{ip: "fd00::1", expected: false},
+		{ip: "fe80::1", expected: false},
+	}
+
+	for _, tt := range tests {
+		if result := IsPublicAddress(net.ParseIP(tt.ip)); result != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.ip, result, tt.expected)
+		}
+	}
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	var worker = NewWorker(mockWebhookRepo{}, log.LoggerMock{})
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 1, expected: 30 * time.Second},
+		{attempt: 2, expected: time.Minute},
+		{attempt: 5, expected: 8 * time.Minute},
+		{attempt: 10, expected: 4*time.Hour + 16*time.Minute},
+		{attempt: 11, expected: 6 * time.Hour},
+		{attempt: 100, expected: 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if result := worker.backoff(tt.attempt); result != tt.expected {
+			t.Errorf("[TestCase 'attempt %d'] Result: '%v' | Expected: '%v'", tt.attempt, result, tt.expected)
+		}
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "context"
+
+type AccountID uint64
+
+type accountContextKey string
+
+const keyAccountID accountContextKey = "AccountIDContextKey"
+
+// コンテキストにリクエストを行ったアカウントをセットし返却する
+func WithAccountID(ctx context.Context, id AccountID) context.Context {
+	return context.WithValue(ctx, keyAccountID, id)
+}
+
+// コンテキストにセットされたアカウントを返却する
+// セットされていない場合は false を返却する
+func AccountIDFromContext(ctx context.Context) (AccountID, bool) {
+	id, ok := ctx.Value(keyAccountID).(AccountID)
+	return id, ok
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "context"
+
+var (
+	ErrCalendarTokenInvalid = NewError(KindForbidden, "invalid calendar feed token")
+)
+
+type (
+	// カレンダーフィードのトークン
+	// タスクはまだアカウントごとに分かれていないため、API全体で1つのトークンだけを保持する
+	// トークンそのものは保存せず、ハッシュだけを保存する
+	CalendarTokenRepository interface {
+		// トークンのハッシュを保存する (発行済みのトークンは無効になる)
+		Save(context.Context, string) error
+		// 保存したトークンのハッシュと一致するか確認する
+		// 一致しない場合は ErrCalendarTokenInvalid を返却する
+		Verify(context.Context, string) error
+	}
+)
@@ -0,0 +1,80 @@
+package domain
+
+import "errors"
+
+// ドメインエラーの種別
+type ErrorKind int
+
+const (
+	KindUnknown ErrorKind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindForbidden
+	KindUnavailable
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindValidation:
+		return "validation"
+	case KindForbidden:
+		return "forbidden"
+	case KindUnavailable:
+		return "unavailable"
+	default:
+		return "unknown"
+	}
+}
+
+// 種別を持つドメインエラー
+type Error struct {
+	kind ErrorKind
+	msg  string
+	err  error
+}
+
+// 種別とメッセージからドメインエラーを生成する
+func NewError(kind ErrorKind, msg string) *Error {
+	return &Error{kind: kind, msg: msg}
+}
+
+// 元のエラーを種別付きのドメインエラーでラップする
+func WrapError(kind ErrorKind, err error, msg string) *Error {
+	return &Error{kind: kind, msg: msg, err: err}
+}
+
+func (e *Error) Error() string {
+	if e.err != nil {
+		return e.msg + ": " + e.err.Error()
+	}
+
+	return e.msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func (e *Error) Kind() ErrorKind {
+	return e.kind
+}
+
+// ラップしたエラーを含まないメッセージを返却する
+func (e *Error) Message() string {
+	return e.msg
+}
+
+// エラーチェーンの中で最初に見つかったドメインエラーの種別を返却する
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.kind
+	}
+
+	return KindUnknown
+}
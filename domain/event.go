@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type EventID uint64
+
+// ドメインイベントの種類
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskDeleted = "task.deleted"
+)
+
+type (
+	// タスクの変更と同じトランザクションでイベントを書き込み、後から発行するための保管場所
+	OutboxRepository interface {
+		// イベントを書き込む
+		// ID と発生日時はリポジトリで設定し、アカウントにはコンテキストのアカウントを記録する
+		Append(context.Context, ...Event) error
+		// 未発行のイベントをコミットした順に指定件数まで返却する
+		// トランザクション内で呼び出した場合は、コミットするまで他の呼び出し元を待たせる
+		FindUnpublished(context.Context, int) ([]Event, error)
+		// イベントを発行済みにする
+		MarkPublished(context.Context, ...EventID) error
+		// アカウントのイベントのうち、指定したIDより後のものをコミットした順に指定件数まで返却する
+		// 発行済みかどうかに関わらず返却する
+		FindAfter(context.Context, AccountID, EventID, int) ([]Event, error)
+		// 保持しているイベントの最小と最大のIDを返却する (イベントがない場合は 0)
+		Bounds(context.Context) (EventID, EventID, error)
+		// 指定日時より前に発生した発行済みのイベントを削除し、削除した件数を返却する
+		Prune(context.Context, time.Time) (int64, error)
+		WithTransaction(context.Context, func(context.Context) error) error
+	}
+
+	// イベントが書き込まれたことをアカウントごとに知らせる
+	// 通知はイベントを読み取るきっかけにだけ使い、イベントそのものは OutboxRepository から読み取る
+	EventNotifier interface {
+		// アカウントのイベントが書き込まれると値を受け取るチャネルと、購読をやめる関数を返却する
+		// 受け取る前に続けて書き込まれた場合、通知は1つにまとめられる
+		Subscribe(AccountID) (<-chan struct{}, func())
+	}
+
+	// 発行したイベントを処理する
+	// 同じイベントが複数回渡される可能性があるため、処理は冪等にする
+	EventHandler interface {
+		Handle(context.Context, Event) error
+	}
+
+	// タスクに起きた変更
+	Event struct {
+		ID     EventID
+		Type   string
+		TaskID TaskID
+		// 変更を行ったアカウント
+		AccountID AccountID
+		// 変更後のタスク (削除の場合は nil)
+		Task       *Task
+		OccurredAt time.Time
+	}
+)
+
+// タスクの作成・更新のイベントを生成する
+func NewTaskEvent(eventType string, task Task) Event {
+	return Event{
+		Type:   eventType,
+		TaskID: task.ID,
+		Task:   &task,
+	}
+}
+
+// タスクの削除のイベントを生成する
+func NewTaskDeletedEvent(taskID TaskID) Event {
+	return Event{
+		Type:   EventTaskDeleted,
+		TaskID: taskID,
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// 予約の期限が切れ、同じキーが別のリクエストに予約し直されたことを表すエラー
+// 別のリクエストの予約を上書き・取り消ししないよう、レスポンスを保存しない
+var ErrIdempotencyReservationLost = NewError(KindConflict, "idempotency key was reserved again by another request")
+
+type (
+	IdempotencyRepository interface {
+		// キーを処理中として予約する
+		// すでに有効なキーが存在する場合は予約せず、保存済みのレコードと false を返却する
+		Reserve(context.Context, IdempotencyRecord) (IdempotencyRecord, bool, error)
+		// 処理結果のレスポンスを保存し、有効期限を更新する
+		// Reserve で予約したままの場合だけ保存し、予約し直されている場合は ErrIdempotencyReservationLost を返却する
+		Complete(context.Context, IdempotencyRecord) error
+		// 予約を取り消し、同じキーで再実行できるようにする
+		// Reserve で予約したままの場合だけ取り消し、予約し直されている場合は ErrIdempotencyReservationLost を返却する
+		Release(context.Context, IdempotencyRecord) error
+		// 指定日時より前に期限が切れたキーを削除し、削除した件数を返却する
+		Prune(context.Context, time.Time) (int64, error)
+	}
+
+	// Idempotency-Key ごとに保存するリクエストとレスポンス
+	IdempotencyRecord struct {
+		AccountID AccountID
+		Key       string
+		// 同じキーで異なるリクエストが送られていないか判定するためのハッシュ
+		Fingerprint string
+		// 処理中の場合は 0
+		StatusCode int
+		Header     map[string][]string
+		Body       []byte
+		// 処理中の場合は予約の期限、レスポンスの保存後は保持期限
+		ExpiresAt time.Time
+	}
+)
+
+// レスポンスが保存済みか
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
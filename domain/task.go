@@ -5,19 +5,107 @@ import (
 	"time"
 )
 
+var (
+	ErrTaskNotFound = NewError(KindNotFound, "task not found")
+	// 削除より後に変更されたタスクは削除しない
+	ErrTaskModifiedAfterDelete = NewError(KindConflict, "task was modified after it was deleted")
+)
+
 type TaskID uint64
 
+// タスクの変更ごとに採番する連番 (削除を含め、コミットした順に大きくなる)
+type ChangeSeq uint64
+
+// 項目ごとに変更を記録するタスクの項目
+const (
+	TaskFieldTitle      = "title"
+	TaskFieldDueDate    = "due_date"
+	TaskFieldTags       = "tags"
+	TaskFieldCompleted  = "completed"
+	TaskFieldPriority   = "priority"
+	TaskFieldRecurrence = "recurrence"
+	TaskFieldContexts   = "contexts"
+	TaskFieldExtensions = "extensions"
+)
+
+// TaskField* の一覧
+var TaskFields = []string{
+	TaskFieldTitle,
+	TaskFieldDueDate,
+	TaskFieldTags,
+	TaskFieldCompleted,
+	TaskFieldPriority,
+	TaskFieldRecurrence,
+	TaskFieldContexts,
+	TaskFieldExtensions,
+}
+
 type (
 	TaskRepository interface {
 		Create(context.Context, Task) (Task, error)
-		Update(context.Context, Task, TaskID) error
+		CreateMany(context.Context, []Task) ([]Task, error)
+		Update(context.Context, Task, TaskID) (Task, error)
 		FindAll(context.Context) ([]Task, error)
+		// タスクを1件ずつ関数に渡す (全件をメモリに読み込まない)
+		// 関数がエラーを返却した場合は中断し、そのエラーを返却する
+		FindEach(context.Context, func(Task) error) error
+		FindByID(context.Context, TaskID) (Task, error)
+		// 指定した連番より後の変更 (削除を含む) を連番の順に指定件数まで返却する
+		FindChanges(context.Context, ChangeSeq, int) ([]TaskChange, error)
+		// 完了していないタスクと、そのうち期限を過ぎたタスクの件数を返却する
+		Count(context.Context) (TaskCounts, error)
+		Delete(context.Context, TaskID) error
+		WithTransaction(context.Context, func(context.Context) error) error
 	}
 
 	Task struct {
 		ID        TaskID
 		Title     string
-		CreatedAt time.Time
+		DueDate   *time.Time
+		Tags      []string
+		Completed bool
+		// 優先度 (RFC 5545 と同じく 1 が最も高く、0 は未設定)
+		Priority int
+		// 繰り返しの規則 (RFC 5545 の RRULE、例: "FREQ=WEEKLY;BYDAY=MO")
+		Recurrence string
+		// 場所や状況 (todo.txt の @context)
+		Contexts []string
+		// 任意のキーと値 (todo.txt の key:value)、順番と重複を保持する
+		Extensions []Extension
+		// 完了した日時 (完了していない場合は nil)
+		CompletedAt *time.Time
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		// 最後に変更した時の連番
+		ChangeSeq ChangeSeq
+		// 項目 (TaskField*) ごとの最後の変更
+		// 書き込む時に UpdatedAt を変更した項目は、その日時に変更したものとして記録する
+		// それ以外の変更した項目は、書き込んだ日時に変更したものとして記録する
+		Versions map[string]FieldVersion
+	}
+
+	// 項目の最後の変更
+	FieldVersion struct {
 		UpdatedAt time.Time
+		Seq       ChangeSeq
+	}
+
+	// タスクの作成・更新・削除
+	TaskChange struct {
+		Seq    ChangeSeq
+		TaskID TaskID
+		// 変更後のタスク (削除の場合は nil)
+		Task      *Task
+		DeletedAt *time.Time
+	}
+
+	TaskCounts struct {
+		Open    int
+		Overdue int
+	}
+
+	Extension struct {
+		Key   string
+		Value string
 	}
 )
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type (
+	// トレースの1つの区間
+	Span interface {
+		SetAttribute(key string, value interface{})
+		// エラーを記録し、区間を失敗として扱う
+		RecordError(error)
+		End()
+	}
+
+	// 区間を開始する
+	// コンテキストに区間があれば、その子として開始する
+	Tracer interface {
+		Start(ctx context.Context, name string) (context.Context, Span)
+	}
+)
+
+type tracerHolder struct {
+	tracer Tracer
+}
+
+// 区間の開始に使用する Tracer (未設定の場合は何も記録しない)
+var globalTracer atomic.Value
+
+// 区間の開始に使用する Tracer を設定する
+// サーバーを起動する前に呼び出す
+func SetTracer(t Tracer) {
+	globalTracer.Store(tracerHolder{tracer: t})
+}
+
+// 区間を開始し、区間をセットしたコンテキストを返却する
+// 返却された区間は、処理が終わった時に End を呼び出す
+func StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	if h, ok := globalTracer.Load().(tracerHolder); ok && h.tracer != nil {
+		return h.tracer.Start(ctx, name)
+	}
+
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(_ string, _ interface{}) {}
+func (noopSpan) RecordError(_ error)                  {}
+func (noopSpan) End()                                 {}
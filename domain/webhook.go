@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound = NewError(KindNotFound, "webhook not found")
+)
+
+type WebhookID uint64
+
+type WebhookDeliveryID uint64
+
+// 配信の状態
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type (
+	WebhookRepository interface {
+		Create(context.Context, WebhookSubscription) (WebhookSubscription, error)
+		// アカウントの購読をID順に返却する
+		FindAll(context.Context, AccountID) ([]WebhookSubscription, error)
+		// 対象の購読が存在しない場合は ErrWebhookNotFound を返却する
+		Delete(context.Context, AccountID, WebhookID) error
+		// 購読を有効にし、連続失敗回数をリセットする
+		// 対象の購読が存在しない場合は ErrWebhookNotFound を返却する
+		Enable(context.Context, AccountID, WebhookID) (WebhookSubscription, error)
+		// 購読の配信を新しい順に指定件数まで返却する
+		// 対象の購読が存在しない場合は ErrWebhookNotFound を返却する
+		FindDeliveries(context.Context, AccountID, WebhookID, int) ([]WebhookDelivery, error)
+
+		// イベントのアカウントの、イベントの種類を購読している有効な購読ごとに配信を登録する
+		// 登録済みのイベントは重複して登録しない
+		Enqueue(context.Context, WebhookEvent) error
+		// 配信時刻を過ぎた配信を指定件数まで取り出す
+		// 取り出した配信は指定時間が経つまで他のワーカーから取り出されない
+		ClaimDue(context.Context, int, time.Duration) ([]WebhookDelivery, error)
+		// 配信の試行結果を記録し、配信と購読の状態を更新する
+		RecordAttempt(context.Context, WebhookAttemptResult) error
+	}
+
+	WebhookSubscription struct {
+		ID        WebhookID
+		AccountID AccountID
+		URL       string
+		Events    []string
+		// ペイロードの署名に使用する秘密鍵
+		Secret string
+		// 連続で配信に失敗すると自動で無効になる
+		Active              bool
+		ConsecutiveFailures int
+		CreatedAt           time.Time
+		UpdatedAt           time.Time
+	}
+
+	// 購読者に通知するイベント (ID はドメインイベントと同じ)
+	WebhookEvent struct {
+		ID   string
+		Type string
+		// 変更を行ったアカウント (このアカウントの購読にだけ配信する)
+		AccountID AccountID
+		Payload   []byte
+	}
+
+	// 購読ごとのイベントの配信
+	WebhookDelivery struct {
+		ID             WebhookDeliveryID
+		SubscriptionID WebhookID
+		EventID        string
+		EventType      string
+		Payload        []byte
+		Status         string
+		Attempts       int
+		NextAttemptAt  time.Time
+		LastStatusCode int
+		LastError      string
+		CreatedAt      time.Time
+		UpdatedAt      time.Time
+
+		// 配信先 (ClaimDue でのみ設定する)
+		URL    string
+		Secret string
+
+		// 試行の履歴 (FindDeliveries でのみ設定する)
+		Log []WebhookAttempt
+	}
+
+	// 1回の配信の試行
+	WebhookAttempt struct {
+		// 何回目の試行か (1始まり)
+		Attempt int
+		// 応答がなかった場合は 0
+		StatusCode  int
+		Error       string
+		Duration    time.Duration
+		AttemptedAt time.Time
+	}
+
+	// 記録する試行の結果
+	WebhookAttemptResult struct {
+		DeliveryID     WebhookDeliveryID
+		SubscriptionID WebhookID
+		WebhookAttempt
+		Succeeded bool
+		// 再試行する時刻 (再試行しない場合は nil)
+		RetryAt *time.Time
+		// 連続失敗回数がこの値に達したら購読を無効にする
+		DisableAfter int
+	}
+)
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ログに出力する接続文字列で、パスワードの代わりに表示する値
+const redactedPassword = "xxxxx"
+
+var dsnEscaper = strings.NewReplacer(`'`, `\'`, `\`, `\\`)
+
+// 接続文字列のうち、パスワード (password・sslpassword など) を指定する key='value'
+var passwordPattern = regexp.MustCompile(`(^|\s)(\w*password)='(?:[^'\\]|\\.)*'`)
+
+// 接続文字列を返却する
+func dataSourceName(c *config) (string, error) {
+	return c.dsn()
+}
+
+// パスワードを伏せた接続文字列を返却する (ログ用)
+// DATABASE_URL のユーザー情報だけでなく、クエリ文字列 (?password=...) で指定したパスワードも伏せるため、
+// 組み立てた後の接続文字列から password を含むキーの値を置き換える
+func redactedDataSourceName(c *config) string {
+	ds, err := dataSourceName(c)
+	if err != nil {
+		return "invalid DATABASE_URL"
+	}
+
+	return passwordPattern.ReplaceAllString(ds, "${1}${2}='"+redactedPassword+"'")
+}
+
+// key=value 形式の接続文字列を組み立てる
+// DATABASE_URL がある場合は POSTGRES_HOST などの代わりに使用し、TLS の設定だけを後ろに加える
+// (後ろにある値が優先される)
+func (c *config) dsn() (string, error) {
+	var kvs []string
+	if c.url != "" {
+		ds, err := pq.ParseURL(c.url)
+		if err != nil {
+			return "", err
+		}
+		kvs = append(kvs, ds)
+	} else {
+		kvs = appendDSN(kvs, "host", c.host)
+		kvs = appendDSN(kvs, "port", c.port)
+		kvs = appendDSN(kvs, "user", c.user)
+		kvs = appendDSN(kvs, "dbname", c.database)
+		kvs = appendDSN(kvs, "password", c.password)
+	}
+
+	kvs = appendDSN(kvs, "sslmode", c.sslMode)
+	kvs = appendDSN(kvs, "sslrootcert", c.sslRootCert)
+	kvs = appendDSN(kvs, "sslcert", c.sslCert)
+	kvs = appendDSN(kvs, "sslkey", c.sslKey)
+
+	return strings.Join(kvs, " "), nil
+}
+
+// 空の値は lib/pq の既定値を使うため加えない
+func appendDSN(kvs []string, key, value string) []string {
+	if value == "" {
+		return kvs
+	}
+
+	return append(kvs, key+"='"+dsnEscaper.Replace(value)+"'")
+}
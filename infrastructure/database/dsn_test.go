@@ -0,0 +1,83 @@
+package database
+
+import "testing"
+
+func TestDataSourceName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		config           config
+		expected         string
+		expectedRedacted string
+	}{
+		{
+			name: "Connection settings",
+			config: config{
+				host:     "postgres",
+				port:     "5432",
+				user:     "todo",
+				database: "todo",
+				password: `it's a \secret`,
+				sslMode:  "disable",
+			},
+			expected:         `host='postgres' port='5432' user='todo' dbname='todo' password='it\'s a \\secret' sslmode='disable'`,
+			expectedRedacted: `host='postgres' port='5432' user='todo' dbname='todo' password='xxxxx' sslmode='disable'`,
+		},
+		{
+			name: "Empty values are left to the driver",
+			config: config{
+				host: "postgres",
+			},
+			expected:         `host='postgres'`,
+			expectedRedacted: `host='postgres'`,
+		},
+		{
+			name: "Database URL with TLS",
+			config: config{
+				host:        "ignored",
+				url:         "postgres://todo:s3cr3t@db:5432/todo",
+				sslMode:     "verify-full",
+				sslRootCert: "/etc/ssl/ca.pem",
+			},
+			expected:         `dbname='todo' host='db' password='s3cr3t' port='5432' user='todo' sslmode='verify-full' sslrootcert='/etc/ssl/ca.pem'`,
+			expectedRedacted: `dbname='todo' host='db' password='xxxxx' port='5432' user='todo' sslmode='verify-full' sslrootcert='/etc/ssl/ca.pem'`,
+		},
+		{
+			name: "Database URL with password in the query string",
+			config: config{
+				url: `postgres://todo@db/todo?password=s3cr3t&sslpassword=it's`,
+			},
+			expected:         `dbname='todo' host='db' password='s3cr3t' sslpassword='it\'s' user='todo'`,
+			expectedRedacted: `dbname='todo' host='db' password='xxxxx' sslpassword='xxxxx' user='todo'`,
+		},
+		{
+			name: "Database URL without password",
+			config: config{
+				url: "postgres://todo@db/todo?sslmode=require",
+			},
+			expected:         `dbname='todo' host='db' sslmode='require' user='todo'`,
+			expectedRedacted: `dbname='todo' host='db' sslmode='require' user='todo'`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := dataSourceName(&tt.config)
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, nil)
+			}
+
+			if result != tt.expected {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expected)
+			}
+
+			if redacted := redactedDataSourceName(&tt.config); redacted != tt.expectedRedacted {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, redacted, tt.expectedRedacted)
+			}
+		})
+	}
+}
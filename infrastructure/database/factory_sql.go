@@ -1,9 +1,13 @@
 package database
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	"github.com/doglapping707/todo-api-go/adapter/logger"
 	"github.com/doglapping707/todo-api-go/adapter/repository"
+	"github.com/doglapping707/todo-api-go/infrastructure/settings"
 )
 
 var (
@@ -14,11 +18,33 @@ const (
 	InstancePostgres int = iota
 )
 
+// アプリケーションが前提とするスキーマのバージョン
+// (_scripts/postgres/schema_version.sql を参照)
+const SchemaVersion = 4
+
+// DBからの通知を受け取り続ける
+type Listener interface {
+	Run(context.Context)
+	// Run が進捗を記録する最長の間隔
+	ProgressInterval() time.Duration
+}
+
 // 生成されたDBハンドラーを返却する
-func NewDatabaseSQLFactory(instance int) (repository.SQL, error) {
+// DBに接続できなかった場合はエラーを返却する
+func NewDatabaseSQLFactory(instance int, s settings.Database, log logger.Logger) (repository.SQL, error) {
+	switch instance {
+	case InstancePostgres:
+		return NewPostgresHandler(newConfigPostgres(s), log)
+	default:
+		return nil, errInvalidSQLDatabaseInstance
+	}
+}
+
+// 生成されたDBの通知のリスナーを返却する
+func NewListenerFactory(instance int, s settings.Database, publisher Publisher, log logger.Logger) (Listener, error) {
 	switch instance {
 	case InstancePostgres:
-		return NewPostgresHandler(newConfigPostgres())
+		return NewPostgresListener(newConfigPostgres(s), publisher, log)
 	default:
 		return nil, errInvalidSQLDatabaseInstance
 	}
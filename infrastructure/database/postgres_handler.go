@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"math/rand"
+	"strings"
+	"time"
 
+	"github.com/doglapping707/todo-api-go/adapter/logger"
 	"github.com/doglapping707/todo-api-go/adapter/repository"
+	"github.com/doglapping707/todo-api-go/domain"
 	_ "github.com/lib/pq"
 )
 
@@ -16,30 +20,83 @@ type postgresHandler struct {
 }
 
 // postgresハンドラを却する
-func NewPostgresHandler(c *config) (*postgresHandler, error) {
-	var ds = fmt.Sprintf(
-		"host=%s port=%s user=%s dbname=%s sslmode=disable password=%s",
-		c.host,
-		c.port,
-		c.user,
-		c.database,
-		c.password,
-	)
-
-	fmt.Println(ds)
-	db, err := sql.Open(c.driver, ds)
+// DBが起動するまで、間隔を空けながら接続を試みる
+func NewPostgresHandler(c *config, log logger.Logger) (*postgresHandler, error) {
+	ds, err := dataSourceName(c)
 	if err != nil {
 		return &postgresHandler{}, err
 	}
 
-	err = db.Ping()
+	log.WithFields(logger.Fields{"dsn": redactedDataSourceName(c)}).Infof("Connecting to the SQL database")
+
+	db, err := sql.Open(c.driver, ds)
 	if err != nil {
-		log.Fatalln(err)
+		return &postgresHandler{}, err
+	}
+
+	db.SetMaxOpenConns(c.maxOpenConns)
+	db.SetMaxIdleConns(c.maxIdleConns)
+	db.SetConnMaxLifetime(c.connMaxLifetime)
+	db.SetConnMaxIdleTime(c.connMaxIdleTime)
+
+	if err := connect(db, c, log); err != nil {
+		db.Close()
+		return &postgresHandler{}, err
 	}
 
 	return &postgresHandler{db: db}, nil
 }
 
+// 接続できるまで、指数的に延ばした間隔にゆらぎを加えて Ping を繰り返す
+// 回数の上限に達するか、接続を待つ時間を過ぎた場合は最後のエラーを返却する
+func connect(db *sql.DB, c *config, log logger.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.connectTimeout)
+	defer cancel()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = db.PingContext(ctx); err == nil {
+			return nil
+		}
+
+		if attempt >= c.connectRetries {
+			return fmt.Errorf("could not connect to the database after %d attempts: %w", attempt, err)
+		}
+
+		var wait = backoff(attempt, c.connectBackoff, c.connectMaxBackoff)
+		log.WithError(err).WithFields(logger.Fields{
+			"attempt": attempt,
+			"retry":   wait.String(),
+		}).Warnf("Could not connect to the database, retrying")
+
+		var timer = time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("could not connect to the database within %s: %w", c.connectTimeout, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt 回目の失敗の後に待つ間隔を返却する
+// 複数のレプリカが同時に接続し直さないよう、間隔の半分から全体までの範囲でばらつかせる
+func backoff(attempt int, base, max time.Duration) time.Duration {
+	var d = base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	if half := int64(d / 2); half > 0 {
+		return d/2 + time.Duration(rand.Int63n(half+1))
+	}
+
+	return d
+}
+
 // Txを取得する
 func (p postgresHandler) BeginTx(ctx context.Context) (repository.Tx, error) {
 	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{})
@@ -50,9 +107,23 @@ func (p postgresHandler) BeginTx(ctx context.Context) (repository.Tx, error) {
 	return newPostgresTx(tx), nil
 }
 
+// コネクションプールの状態を返却する
+func (p postgresHandler) Stats() sql.DBStats {
+	return p.db.Stats()
+}
+
+// DBとの接続を確認する
+func (p postgresHandler) PingContext(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
 func (p postgresHandler) ExecuteContext(ctx context.Context, query string, args ...interface{}) error {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
 	_, err := p.db.ExecContext(ctx, query, args...)
 	if err != nil {
+		span.RecordError(err)
 		return err
 	}
 
@@ -60,8 +131,12 @@ func (p postgresHandler) ExecuteContext(ctx context.Context, query string, args
 }
 
 func (p postgresHandler) QueryContext(ctx context.Context, query string, args ...interface{}) (repository.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
 	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 
@@ -71,11 +146,25 @@ func (p postgresHandler) QueryContext(ctx context.Context, query string, args ..
 }
 
 func (p postgresHandler) QueryRowContext(ctx context.Context, query string, args ...interface{}) repository.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
 	row := p.db.QueryRowContext(ctx, query, args...)
+	span.RecordError(row.Err())
 
 	return newPostgresRow(row)
 }
 
+// クエリの区間を開始する
+// 区間はクエリが結果を返すまでで、行の読み取りは含まない
+func startQuerySpan(ctx context.Context, query string) (context.Context, domain.Span) {
+	ctx, span := domain.StartSpan(ctx, "db.query")
+	span.SetAttribute("db.system", "postgresql")
+	span.SetAttribute("db.statement", strings.Join(strings.Fields(query), " "))
+
+	return ctx, span
+}
+
 type postgresRow struct {
 	row *sql.Row
 }
@@ -129,8 +218,12 @@ func newPostgresTx(tx *sql.Tx) postgresTx {
 }
 
 func (p postgresTx) ExecuteContext(ctx context.Context, query string, args ...interface{}) error {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
 	_, err := p.tx.ExecContext(ctx, query, args...)
 	if err != nil {
+		span.RecordError(err)
 		return err
 	}
 
@@ -138,8 +231,12 @@ func (p postgresTx) ExecuteContext(ctx context.Context, query string, args ...in
 }
 
 func (p postgresTx) QueryContext(ctx context.Context, query string, args ...interface{}) (repository.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
 	rows, err := p.tx.QueryContext(ctx, query, args...)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 
@@ -149,7 +246,11 @@ func (p postgresTx) QueryContext(ctx context.Context, query string, args ...inte
 }
 
 func (p postgresTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) repository.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
 	row := p.tx.QueryRowContext(ctx, query, args...)
+	span.RecordError(row.Err())
 
 	return newPostgresRow(row)
 }
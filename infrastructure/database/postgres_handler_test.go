@@ -0,0 +1,35 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		attempt  int
+		expected time.Duration
+	}{
+		{name: "First attempt", attempt: 1, expected: 500 * time.Millisecond},
+		{name: "Doubled", attempt: 3, expected: 2 * time.Second},
+		{name: "Capped", attempt: 6, expected: 10 * time.Second},
+		{name: "Many attempts", attempt: 100, expected: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for i := 0; i < 100; i++ {
+				var result = backoff(tt.attempt, 500*time.Millisecond, 10*time.Second)
+				if result < tt.expected/2 || result > tt.expected {
+					t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: between '%v' and '%v'", tt.name, result, tt.expected/2, tt.expected)
+				}
+			}
+		})
+	}
+}
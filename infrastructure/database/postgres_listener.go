@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/health"
+	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/lib/pq"
+)
+
+// outbox にイベントが書き込まれると、トリガーがイベントのアカウントIDを送るチャンネル
+// (_scripts/postgres/outbox.sql を参照)
+const channelTaskEvents = "task_events"
+
+const (
+	// 接続が切れた後、再接続を試みるまでの最初の間隔 (失敗するたびに倍にする)
+	listenerMinReconnectInterval = time.Second
+	// 再接続を試みる間隔の上限
+	listenerMaxReconnectInterval = time.Minute
+	// 通知がない間に接続が生きているか確認する間隔
+	listenerPingInterval = 90 * time.Second
+)
+
+// 受け取った通知を伝える先
+type Publisher interface {
+	Publish(domain.AccountID)
+	PublishAll()
+}
+
+// LISTEN 専用の接続で outbox への書き込みの通知を受け取り、Publisher に伝える
+// NOTIFY はコミットした時に送られるため、他のレプリカで書き込まれたイベントも受け取る
+type postgresListener struct {
+	listener  *pq.Listener
+	publisher Publisher
+	log       logger.Logger
+}
+
+func NewPostgresListener(c *config, publisher Publisher, log logger.Logger) (*postgresListener, error) {
+	ds, err := dataSourceName(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var l = &postgresListener{
+		publisher: publisher,
+		log:       log,
+	}
+
+	l.listener = pq.NewListener(
+		ds,
+		listenerMinReconnectInterval,
+		listenerMaxReconnectInterval,
+		l.onEvent,
+	)
+
+	return l, nil
+}
+
+// コンテキストがキャンセルされるまで通知を受け取り続ける
+// 接続が切れた場合は pq.Listener が間隔を空けながら再接続する
+func (l *postgresListener) Run(ctx context.Context) {
+	defer l.listener.Close()
+
+	// 接続できるまで戻らないため、停止できるよう別のゴルーチンで実行する
+	go func() {
+		if err := l.listener.Listen(channelTaskEvents); err != nil && ctx.Err() == nil {
+			l.log.WithError(err).Errorf("error listening to %s", channelTaskEvents)
+		}
+	}()
+
+	l.log.Infof("Starting database listener")
+
+	var ping = time.NewTicker(listenerPingInterval)
+	defer ping.Stop()
+
+	for {
+		health.Progress(ctx)
+
+		select {
+		case <-ctx.Done():
+			l.log.Infof("Database listener stopped")
+			return
+		case n := <-l.listener.Notify:
+			l.handle(n)
+		case <-ping.C:
+			// 応答がなければ接続が切断され、再接続が始まる
+			go l.listener.Ping()
+		}
+	}
+}
+
+// Run が進捗を記録する最長の間隔 (通知がない場合は接続の確認ごとに記録する)
+func (l *postgresListener) ProgressInterval() time.Duration {
+	return listenerPingInterval
+}
+
+func (l *postgresListener) handle(n *pq.Notification) {
+	// 再接続した場合は nil が送られる
+	// 切れていた間の通知は届かないため、すべての購読者に読み直させる
+	if n == nil {
+		l.publisher.PublishAll()
+		return
+	}
+
+	accountID, err := strconv.ParseUint(n.Extra, 10, 64)
+	if err != nil {
+		l.log.WithError(err).Warnf("invalid notification payload %q", n.Extra)
+		l.publisher.PublishAll()
+		return
+	}
+
+	l.publisher.Publish(domain.AccountID(accountID))
+}
+
+// 接続の状態の変化をログに出力する
+func (l *postgresListener) onEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventConnected:
+		l.log.Infof("Database listener connected")
+	case pq.ListenerEventDisconnected:
+		l.log.WithError(err).Warnf("database listener disconnected")
+	case pq.ListenerEventReconnected:
+		l.log.Infof("Database listener reconnected")
+	case pq.ListenerEventConnectionAttemptFailed:
+		l.log.WithError(err).Warnf("database listener connection attempt failed")
+	}
+}
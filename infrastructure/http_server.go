@@ -1,32 +1,52 @@
 package infrastructure
 
 import (
-	"strconv"
+	"context"
+	"database/sql"
+	"os"
 	"time"
 
+	"github.com/doglapping707/todo-api-go/adapter/api/logging"
+	"github.com/doglapping707/todo-api-go/adapter/health"
+	"github.com/doglapping707/todo-api-go/adapter/idempotency"
 	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/metrics"
+	"github.com/doglapping707/todo-api-go/adapter/outbox"
+	"github.com/doglapping707/todo-api-go/adapter/pubsub"
 	"github.com/doglapping707/todo-api-go/adapter/repository"
+	"github.com/doglapping707/todo-api-go/adapter/tracing"
 	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/adapter/webhook"
+	"github.com/doglapping707/todo-api-go/domain"
 	"github.com/doglapping707/todo-api-go/infrastructure/database"
 	"github.com/doglapping707/todo-api-go/infrastructure/log"
 	"github.com/doglapping707/todo-api-go/infrastructure/router"
+	"github.com/doglapping707/todo-api-go/infrastructure/settings"
 	"github.com/doglapping707/todo-api-go/infrastructure/validation"
 )
 
 // サーバー接続設定
 type config struct {
-	appName       string
-	logger        logger.Logger
-	validator     validator.Validator
-	dbSQL         repository.SQL
-	ctxTimeout    time.Duration
-	webServerPort router.Port
-	webServer     router.Server
+	appName        string
+	logger         logger.Logger
+	validator      validator.Validator
+	dbSQL          repository.SQL
+	dbListener     database.Listener
+	eventHub       *pubsub.Hub
+	metrics        *metrics.Registry
+	health         *health.Registry
+	workers        *health.Workers
+	ctxTimeout     time.Duration
+	httpTimeouts   settings.HTTP
+	idempotencyTTL time.Duration
+	legacyErrors   bool
+	webServerPort  router.Port
+	webServer      router.Server
 }
 
 // サーバー接続設定を返す
 func NewConfig() *config {
-	return &config{}
+	return &config{workers: health.NewWorkers()}
 }
 
 // サーバー接続設定に "コンテキストがタイムアウトする時間" をセットし返却する
@@ -35,12 +55,30 @@ func (c *config) ContextTimeout(t time.Duration) *config {
 	return c
 }
 
+// サーバー接続設定に "HTTPサーバーのタイムアウト" をセットし返却する
+func (c *config) HTTPTimeouts(t settings.HTTP) *config {
+	c.httpTimeouts = t
+	return c
+}
+
+// サーバー接続設定に "Idempotency-Key を保持する時間" をセットし返却する
+func (c *config) IdempotencyTTL(ttl time.Duration) *config {
+	c.idempotencyTTL = ttl
+	return c
+}
+
 // サーバー接続設定に "アプリケーション名" をセットし返却する
 func (c *config) Name(name string) *config {
 	c.appName = name
 	return c
 }
 
+// サーバー接続設定に "旧形式のエラーレスポンスを使用するか" をセットし返却する
+func (c *config) LegacyErrorFormat(enabled bool) *config {
+	c.legacyErrors = enabled
+	return c
+}
+
 // サーバー接続設定に "ロガー" をセットし返却する
 func (c *config) Logger(instance int) *config {
 	log, err := log.NewLoggerFactory(instance)
@@ -48,14 +86,37 @@ func (c *config) Logger(instance int) *config {
 		log.Fatalln(err)
 	}
 
+	// リクエストの処理中に出力するログに、リクエストID・ルート・アカウントIDを付ける
+	logger.RegisterContextFields(logging.ContextFields)
+
 	c.logger = log
 	c.logger.Infof("Successfully configured log")
 	return c
 }
 
+// サーバー接続設定に "トレースの送り先" をセットし返却する
+// "stdout" は区間を1行ずつ JSON で標準出力に書き込む。未指定または "none" の場合は記録しない
+// ログにはトレースと区間のIDを付ける
+func (c *config) Tracing(exporter string) *config {
+	switch exporter {
+	case "", "none":
+		return c
+	case "stdout":
+		domain.SetTracer(tracing.NewTracer(tracing.NewStdoutExporter(os.Stdout)))
+	default:
+		c.logger.Fatalln("invalid trace exporter", exporter)
+	}
+
+	logger.RegisterContextFields(tracing.LogFields)
+
+	c.logger.Infof("Successfully configured tracing")
+	return c
+}
+
 // サーバー接続設定に "DBハンドラー" をセットし返却する
-func (c *config) DbSQL(instance int) *config {
-	db, err := database.NewDatabaseSQLFactory(instance)
+// DBが起動するまで接続を待ち、接続できなかった場合は終了する
+func (c *config) DbSQL(instance int, s settings.Database) *config {
+	db, err := database.NewDatabaseSQLFactory(instance, s, c.logger)
 	if err != nil {
 		c.logger.Fatalln(err, "Could not make a connection to the database")
 	}
@@ -66,6 +127,65 @@ func (c *config) DbSQL(instance int) *config {
 	return c
 }
 
+// サーバー接続設定に "DBの通知のリスナー" をセットし返却する
+// リスナーが受け取った通知は、プロセス内の購読者に伝える
+func (c *config) DbListener(instance int, s settings.Database) *config {
+	c.eventHub = pubsub.NewHub()
+
+	l, err := database.NewListenerFactory(instance, s, c.eventHub, c.logger)
+	if err != nil {
+		c.logger.Fatalln(err)
+	}
+
+	c.logger.Infof("Successfully configured database listener")
+
+	c.dbListener = l
+	return c
+}
+
+// サーバー接続設定に "メトリクス" をセットし返却する
+// DBのコネクションプールの状態とタスクの件数を登録する (HTTPのメトリクスはサーバーが登録する)
+func (c *config) Metrics() *config {
+	c.metrics = metrics.NewRegistry()
+
+	if db, ok := c.dbSQL.(interface{ Stats() sql.DBStats }); ok {
+		c.metrics.Register(metrics.NewDBStatsCollector(db.Stats))
+	}
+	c.metrics.Register(metrics.NewTaskCollector(repository.NewTaskSQL(c.dbSQL), c.ctxTimeout))
+
+	c.logger.Infof("Successfully configured metrics")
+
+	return c
+}
+
+// ディスクの空き容量がこの値を下回ると警告する
+const minFreeDiskSpace = 100 << 20
+
+// サーバー接続設定に "準備ができているかの確認" をセットし返却する
+// DBとの接続とスキーマのバージョンは重要な確認とし、失敗した場合は /health/ready が 503 を返却する
+// ワーカーの停止とディスクの空き容量の不足は警告だけとする
+// diskPath が未指定の場合は、ディスクの空き容量を確認しない
+func (c *config) HealthChecks(diskPath string) *config {
+	c.health = health.NewRegistry()
+
+	if db, ok := c.dbSQL.(health.Pinger); ok {
+		c.health.Register(health.Check{Name: "database", Critical: true, Probe: health.Ping(db)})
+	}
+	c.health.Register(health.Check{
+		Name:     "schema_version",
+		Critical: true,
+		Probe:    health.SchemaVersion(c.dbSQL, database.SchemaVersion),
+	})
+	c.health.Register(health.Check{Name: "workers", Probe: c.workers.Probe})
+	if diskPath != "" {
+		c.health.Register(health.Check{Name: "disk_space", Probe: health.DiskSpace(diskPath, minFreeDiskSpace)})
+	}
+
+	c.logger.Infof("Successfully configured health checks")
+
+	return c
+}
+
 // サーバー接続設定に "バリデーター" をセットし返却する
 func (c *config) Validator(instance int) *config {
 	v, err := validation.NewValidatorFactory(instance)
@@ -88,6 +208,12 @@ func (c *config) WebServer(instance int) *config {
 		c.validator,
 		c.webServerPort,
 		c.ctxTimeout,
+		c.httpTimeouts,
+		c.idempotencyTTL,
+		c.legacyErrors,
+		c.eventHub,
+		c.metrics,
+		c.health,
 	)
 
 	if err != nil {
@@ -101,17 +227,37 @@ func (c *config) WebServer(instance int) *config {
 }
 
 // サーバー接続設定に "ポート" をセットし返却する
-func (c *config) WebServerPort(port string) *config {
-	p, err := strconv.ParseInt(port, 10, 64)
-	if err != nil {
-		c.logger.Fatalln(err)
-	}
-
-	c.webServerPort = router.Port(p)
+func (c *config) WebServerPort(port int) *config {
+	c.webServerPort = router.Port(port)
 	return c
 }
 
-// サーバーを起動する
+// サーバーと、DBの通知のリスナー・イベントの relay・Webhook の配信ワーカー・期限切れの Idempotency-Key の削除を起動する
+// リスナー・relay・ワーカーはサーバーが停止した後に停止する
 func (c *config) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var webhooks = repository.NewWebhookSQL(c.dbSQL)
+
+	var relay = outbox.NewRelay(repository.NewOutboxSQL(c.dbSQL), c.logger).
+		Subscribe(
+			webhook.NewEventHandler(webhooks),
+			domain.EventTaskCreated,
+			domain.EventTaskUpdated,
+			domain.EventTaskDeleted,
+		)
+
+	var (
+		worker = webhook.NewWorker(webhooks, c.logger)
+		pruner = idempotency.NewPruner(repository.NewIdempotencySQL(c.dbSQL), c.logger)
+	)
+
+	// 停止したワーカーと、進捗が止まったワーカーは /health/ready で報告する
+	go c.workers.Run(ctx, "database_listener", c.dbListener.ProgressInterval(), c.dbListener.Run)
+	go c.workers.Run(ctx, "outbox_relay", relay.ProgressInterval(), relay.Run)
+	go c.workers.Run(ctx, "webhook_worker", worker.ProgressInterval(), worker.Run)
+	go c.workers.Run(ctx, "idempotency_pruner", pruner.ProgressInterval(), pruner.Run)
+
 	c.webServer.Listen()
 }
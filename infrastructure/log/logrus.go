@@ -1,6 +1,8 @@
 package log
 
 import (
+	"context"
+
 	"github.com/doglapping707/todo-api-go/adapter/logger"
 	"github.com/sirupsen/logrus"
 )
@@ -49,6 +51,12 @@ func (l *logrusLogger) WithError(err error) logger.Logger {
 	}
 }
 
+func (l *logrusLogger) WithContext(ctx context.Context) logger.Logger {
+	return &logrusLogEntry{
+		entry: l.logger.WithContext(ctx).WithFields(convertToLogrusFields(logger.FieldsFromContext(ctx))),
+	}
+}
+
 type logrusLogEntry struct {
 	entry *logrus.Entry
 }
@@ -81,6 +89,12 @@ func (l *logrusLogEntry) WithError(err error) logger.Logger {
 	}
 }
 
+func (l *logrusLogEntry) WithContext(ctx context.Context) logger.Logger {
+	return &logrusLogEntry{
+		entry: l.entry.WithContext(ctx).WithFields(convertToLogrusFields(logger.FieldsFromContext(ctx))),
+	}
+}
+
 func convertToLogrusFields(fields logger.Fields) logrus.Fields {
 	logrusFields := logrus.Fields{}
 	for index, field := range fields {
@@ -4,9 +4,13 @@ import (
 	"errors"
 	"time"
 
+	"github.com/doglapping707/todo-api-go/adapter/health"
 	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/metrics"
 	"github.com/doglapping707/todo-api-go/adapter/repository"
 	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/settings"
 )
 
 type Server interface {
@@ -30,10 +34,16 @@ func NewWebServerFactory(
 	validator validator.Validator,
 	port Port,
 	ctxTimeout time.Duration,
+	timeouts settings.HTTP,
+	idempotencyTTL time.Duration,
+	legacyErrors bool,
+	notifier domain.EventNotifier,
+	registry *metrics.Registry,
+	checks *health.Registry,
 ) (Server, error) {
 	switch instance {
 	case InstanceGorillaMux:
-		return newGorillaMux(log, dbSQL, validator, port, ctxTimeout), nil
+		return newGorillaMux(log, dbSQL, validator, port, ctxTimeout, timeouts, idempotencyTTL, legacyErrors, notifier, registry, checks), nil
 	default:
 		return nil, errInvalidWebServerInstance
 	}
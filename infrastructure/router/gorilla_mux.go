@@ -3,6 +3,7 @@ package router
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,10 +12,14 @@ import (
 
 	"github.com/doglapping707/todo-api-go/adapter/api/action"
 	"github.com/doglapping707/todo-api-go/adapter/api/middleware"
+	"github.com/doglapping707/todo-api-go/adapter/health"
 	"github.com/doglapping707/todo-api-go/adapter/logger"
+	"github.com/doglapping707/todo-api-go/adapter/metrics"
 	"github.com/doglapping707/todo-api-go/adapter/presenter"
 	"github.com/doglapping707/todo-api-go/adapter/repository"
 	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/domain"
+	"github.com/doglapping707/todo-api-go/infrastructure/settings"
 	"github.com/doglapping707/todo-api-go/usecase"
 	"github.com/gorilla/mux"
 	"github.com/urfave/negroni"
@@ -28,6 +33,18 @@ type gorillaMux struct {
 	validator  validator.Validator
 	port       Port
 	ctxTimeout time.Duration
+	// リクエストの読み取り・レスポンスの書き込み・停止を待つ時間
+	timeouts settings.HTTP
+	// Idempotency-Key を保持する時間
+	idempotencyTTL time.Duration
+	// 旧形式 {"errors": [...]} でエラーを返却するか
+	legacyErrors bool
+	// イベントの書き込みの通知
+	notifier domain.EventNotifier
+	// /metrics で出力するメトリクス
+	metrics *metrics.Registry
+	// /health/ready で実行する確認
+	health *health.Registry
 }
 
 func newGorillaMux(
@@ -36,6 +53,12 @@ func newGorillaMux(
 	validator validator.Validator,
 	port Port,
 	t time.Duration,
+	timeouts settings.HTTP,
+	idempotencyTTL time.Duration,
+	legacyErrors bool,
+	notifier domain.EventNotifier,
+	registry *metrics.Registry,
+	checks *health.Registry,
 ) *gorillaMux {
 	return &gorillaMux{
 		router:     mux.NewRouter(),
@@ -45,22 +68,62 @@ func newGorillaMux(
 		validator:  validator,
 		port:       port,
 		ctxTimeout: t,
+		timeouts:   timeouts,
+
+		idempotencyTTL: idempotencyTTL,
+		legacyErrors:   legacyErrors,
+		notifier:       notifier,
+		metrics:        registry,
+		health:         checks,
 	}
 }
 
 func (g gorillaMux) Listen() {
+	// ルートに一致したリクエストの区間とメトリクスを記録し、ログにルートを付ける
+	var (
+		httpTracing = middleware.NewTracing(routeTemplate)
+		httpMetrics = middleware.NewMetrics(g.metrics, routeTemplate)
+		httpRoute   = middleware.NewRoute(routeTemplate)
+	)
+	g.router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httpTracing.Execute(w, r, next.ServeHTTP)
+		})
+	})
+	g.router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httpMetrics.Execute(w, r, next.ServeHTTP)
+		})
+	})
+	g.router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httpRoute.Execute(w, r, next.ServeHTTP)
+		})
+	})
+
 	// HTTPハンドラーをセットする
 	g.setAppHandlers(g.router)
+	// すべてのルートのエラーレスポンスを設定した形式で返却する
+	g.middleware.Use(negroni.HandlerFunc(middleware.NewLegacyError(g.legacyErrors).Execute))
 	// HTTPハンドラーを登録する
 	g.middleware.UseHandler(g.router)
 
+	// イベントストリームのように接続を続けるリクエストは、停止時にこのコンテキストで終了させる
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
 	// HTTPサーバーを起動するためのパラメータを成形する
+	// WriteTimeout を延長するハンドラーのため、ResponseController は negroni でラップする前に作成する
 	server := &http.Server{
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 15 * time.Second,
+		ReadTimeout:  g.timeouts.ReadTimeout,
+		WriteTimeout: g.timeouts.WriteTimeout,
 		Addr:         fmt.Sprintf(":%d", g.port),
-		Handler:      g.middleware,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			middleware.NewResponseController().Execute(w, r, g.middleware.ServeHTTP)
+		}),
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
 	}
+	server.RegisterOnShutdown(cancelBase)
 
 	// シグナルの受付を開始する
 	stop := make(chan os.Signal, 1)
@@ -78,7 +141,7 @@ func (g gorillaMux) Listen() {
 	<-stop
 
 	// 指定時間でタイムアウトするコンテキストを作成する
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeouts.ShutdownTimeout)
 	defer func() {
 		cancel()
 	}()
@@ -93,34 +156,112 @@ func (g gorillaMux) Listen() {
 }
 
 func (g gorillaMux) setAppHandlers(router *mux.Router) {
+	// metrics
+	router.HandleFunc("/metrics", action.NewMetricsAction(g.metrics, g.log).Execute).Methods(http.MethodGet)
+
+	// liveness / readiness
+	router.HandleFunc("/health/live", action.Liveness).Methods(http.MethodGet)
+	router.HandleFunc("/health/ready", action.NewReadinessAction(g.health, g.log).Execute).Methods(http.MethodGet)
+
 	// prefix
 	api := router.PathPrefix("/v1").Subrouter()
 
 	// task
 	api.Handle("/tasks", g.buildCreateTaskAction()).Methods(http.MethodPost)
+	api.Handle("/tasks:batch", g.buildBatchTaskAction()).Methods(http.MethodPost)
 	api.Handle("/tasks/{task_id}", g.buildUpdateTaskAction()).Methods(http.MethodPut)
+	api.Handle("/tasks/{task_id}", g.buildPatchTaskAction()).Methods(http.MethodPatch)
 	api.Handle("/tasks", g.buildFindAllTaskAction()).Methods(http.MethodGet)
+	api.Handle("/tasks/export", g.buildExportTaskAction()).Methods(http.MethodGet)
+	api.Handle("/tasks/import", g.buildImportTaskAction()).Methods(http.MethodPost)
+
+	// calendar
+	api.Handle("/calendar.ics", g.buildCalendarFeedAction()).Methods(http.MethodGet)
+	api.Handle("/calendar/token", g.buildIssueCalendarTokenAction()).Methods(http.MethodPost)
+
+	// webhook
+	api.Handle("/webhooks", g.buildCreateWebhookAction()).Methods(http.MethodPost)
+	api.Handle("/webhooks", g.buildFindAllWebhookAction()).Methods(http.MethodGet)
+	api.Handle("/webhooks/{webhook_id}", g.buildDeleteWebhookAction()).Methods(http.MethodDelete)
+	api.Handle("/webhooks/{webhook_id}/enable", g.buildEnableWebhookAction()).Methods(http.MethodPost)
+	api.Handle("/webhooks/{webhook_id}/deliveries", g.buildFindWebhookDeliveriesAction()).Methods(http.MethodGet)
 
-	// health check
+	// sync
+	api.Handle("/sync", g.buildPullTaskChangesAction()).Methods(http.MethodGet)
+	api.Handle("/sync", g.buildPushTaskChangesAction()).Methods(http.MethodPost)
+
+	// event
+	api.Handle("/events", g.buildStreamTaskEventAction()).Methods(http.MethodGet)
+
+	// health check (依存先を確認しない。/health/live と同じ)
 	api.HandleFunc("/health", action.HealthCheck).Methods(http.MethodGet)
 }
 
+// 一致したルートのパスのテンプレート (/v1/tasks/{task_id} など) を返却する
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+
+	return "unknown"
+}
+
 func (g gorillaMux) buildCreateTaskAction() *negroni.Negroni {
 	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
 		var (
 			uc = usecase.NewCreateTaskInteractor(
 				repository.NewTaskSQL(g.db),
+				repository.NewOutboxSQL(g.db),
 				presenter.NewCreateTaskPresenter(),
 				g.ctxTimeout,
 			)
-			act = action.NewCreateTaskAction(uc, g.log, g.validator)
+			act = action.NewCreateTaskAction(uc, g.log.WithContext(req.Context()), g.validator)
+		)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.HandlerFunc(middleware.NewIdempotency(
+			repository.NewIdempotencySQL(g.db),
+			g.log,
+			g.idempotencyTTL,
+		).Execute),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildBatchTaskAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewBatchTaskInteractor(
+				repository.NewTaskSQL(g.db),
+				repository.NewOutboxSQL(g.db),
+				presenter.NewUpdateTaskPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewBatchTaskAction(uc, g.log.WithContext(req.Context()), g.validator)
 		)
 		act.Execute(res, req)
 	}
 
 	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
 		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
 		negroni.NewRecovery(),
+		negroni.HandlerFunc(middleware.NewIdempotency(
+			repository.NewIdempotencySQL(g.db),
+			g.log,
+			g.idempotencyTTL,
+		).Execute),
 		negroni.Wrap(handler),
 	)
 }
@@ -130,9 +271,11 @@ func (g gorillaMux) buildUpdateTaskAction() *negroni.Negroni {
 		var (
 			uc = usecase.NewUpdateTaskInteractor(
 				repository.NewTaskSQL(g.db),
+				repository.NewOutboxSQL(g.db),
+				presenter.NewUpdateTaskPresenter(),
 				g.ctxTimeout,
 			)
-			act = action.NewUpdateTaskAction(uc, g.log, g.validator)
+			act = action.NewUpdateTaskAction(uc, g.log.WithContext(req.Context()), g.validator)
 		)
 
 		var (
@@ -147,6 +290,42 @@ func (g gorillaMux) buildUpdateTaskAction() *negroni.Negroni {
 	}
 
 	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildPatchTaskAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewPatchTaskInteractor(
+				repository.NewTaskSQL(g.db),
+				repository.NewOutboxSQL(g.db),
+				presenter.NewUpdateTaskPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewPatchTaskAction(uc, g.log.WithContext(req.Context()), g.validator)
+		)
+
+		var (
+			vars = mux.Vars(req)   // Get path params
+			q    = req.URL.Query() // Get query param
+		)
+
+		q.Add("task_id", vars["task_id"])
+		req.URL.RawQuery = q.Encode()
+
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
 		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
 		negroni.NewRecovery(),
 		negroni.Wrap(handler),
@@ -161,14 +340,314 @@ func (g gorillaMux) buildFindAllTaskAction() *negroni.Negroni {
 				presenter.NewFindAllTaskPresenter(),
 				g.ctxTimeout,
 			)
-			act = action.NewFindAllTaskAction(uc, g.log)
+			act = action.NewFindAllTaskAction(uc, g.log.WithContext(req.Context()))
+		)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildExportTaskAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewExportTaskInteractor(
+				repository.NewTaskSQL(g.db),
+				presenter.NewUpdateTaskPresenter(),
+			)
+			act = action.NewExportTaskAction(uc, g.log.WithContext(req.Context()))
+		)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildImportTaskAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewImportTaskInteractor(
+				repository.NewTaskSQL(g.db),
+				repository.NewOutboxSQL(g.db),
+				g.ctxTimeout,
+			)
+			act = action.NewImportTaskAction(uc, g.log.WithContext(req.Context()), g.validator)
 		)
 		act.Execute(res, req)
 	}
 
+	// アップロードされたファイルをメモリやログに残さないよう、ボディの大きさを制限し、ログに出力しない
 	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewBodyLimit(action.MaxImportSize).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).WithoutPayload().Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildCalendarFeedAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewCalendarFeedInteractor(
+				repository.NewCalendarTokenSQL(g.db),
+				repository.NewTaskSQL(g.db),
+				presenter.NewCalendarFeedPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewCalendarFeedAction(uc, g.log.WithContext(req.Context()))
+		)
+		act.Execute(res, req)
+	}
+
+	// アカウントはフィードトークンで識別する
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildIssueCalendarTokenAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewIssueCalendarTokenInteractor(
+				repository.NewCalendarTokenSQL(g.db),
+				g.ctxTimeout,
+			)
+			act = action.NewIssueCalendarTokenAction(uc, g.log.WithContext(req.Context()))
+		)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
 		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
 		negroni.NewRecovery(),
 		negroni.Wrap(handler),
 	)
 }
+
+func (g gorillaMux) buildCreateWebhookAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewCreateWebhookInteractor(
+				repository.NewWebhookSQL(g.db),
+				presenter.NewWebhookPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewCreateWebhookAction(uc, g.log.WithContext(req.Context()), g.validator)
+		)
+		act.Execute(res, req)
+	}
+
+	// 署名に使う secret をログに残さないよう、リクエストボディをログに出力しない
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).WithoutPayload().Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildFindAllWebhookAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewFindAllWebhookInteractor(
+				repository.NewWebhookSQL(g.db),
+				presenter.NewWebhookPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewFindAllWebhookAction(uc, g.log.WithContext(req.Context()))
+		)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildDeleteWebhookAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewDeleteWebhookInteractor(
+				repository.NewWebhookSQL(g.db),
+				g.ctxTimeout,
+			)
+			act = action.NewDeleteWebhookAction(uc, g.log.WithContext(req.Context()))
+		)
+
+		withWebhookID(req)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildEnableWebhookAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewEnableWebhookInteractor(
+				repository.NewWebhookSQL(g.db),
+				presenter.NewWebhookPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewEnableWebhookAction(uc, g.log.WithContext(req.Context()))
+		)
+
+		withWebhookID(req)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildFindWebhookDeliveriesAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewFindWebhookDeliveriesInteractor(
+				repository.NewWebhookSQL(g.db),
+				presenter.NewWebhookDeliveryPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewFindWebhookDeliveriesAction(uc, g.log.WithContext(req.Context()))
+		)
+
+		withWebhookID(req)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildPullTaskChangesAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewPullTaskChangesInteractor(
+				repository.NewTaskSQL(g.db),
+				presenter.NewPullTaskChangesPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewPullTaskChangesAction(uc, g.log.WithContext(req.Context()))
+		)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildPushTaskChangesAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewPushTaskChangesInteractor(
+				repository.NewTaskSQL(g.db),
+				repository.NewOutboxSQL(g.db),
+				presenter.NewUpdateTaskPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewPushTaskChangesAction(uc, g.log.WithContext(req.Context()), g.validator)
+		)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.HandlerFunc(middleware.NewIdempotency(
+			repository.NewIdempotencySQL(g.db),
+			g.log,
+			g.idempotencyTTL,
+		).Execute),
+		negroni.Wrap(handler),
+	)
+}
+
+func (g gorillaMux) buildStreamTaskEventAction() *negroni.Negroni {
+	var handler http.HandlerFunc = func(res http.ResponseWriter, req *http.Request) {
+		var (
+			uc = usecase.NewStreamTaskEventInteractor(
+				repository.NewOutboxSQL(g.db),
+				g.notifier,
+				presenter.NewTaskEventPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewStreamTaskEventAction(uc, g.log.WithContext(req.Context()))
+		)
+		act.Execute(res, req)
+	}
+
+	return negroni.New(
+		negroni.HandlerFunc(middleware.NewRequestID().Execute),
+		negroni.HandlerFunc(middleware.NewLocale().Execute),
+		negroni.HandlerFunc(middleware.NewAccount(g.log).Execute),
+		negroni.HandlerFunc(middleware.NewLogger(g.log).Execute),
+		negroni.NewRecovery(),
+		negroni.Wrap(handler),
+	)
+}
+
+// パスパラメータの購読IDをクエリに設定する
+func withWebhookID(req *http.Request) {
+	var q = req.URL.Query()
+	q.Add("webhook_id", mux.Vars(req)["webhook_id"])
+	req.URL.RawQuery = q.Encode()
+}
@@ -0,0 +1,295 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// 設定ファイルのパスを指定するフラグと環境変数
+	fileFlag = "config"
+	fileEnv  = "APP_CONFIG_FILE"
+
+	// 値の代わりに、値を書いたファイルのパスを指定する環境変数の接尾辞 (Docker や Kubernetes の secret 用)
+	fileEnvSuffix = "_FILE"
+)
+
+// 設定の項目
+type field struct {
+	// 設定ファイルのキーとフラグの名前
+	key   string
+	env   string
+	usage string
+	set   func(string) error
+}
+
+// 環境変数を読み取る (os.LookupEnv)
+type LookupEnv func(string) (string, bool)
+
+// 既定値に、設定ファイル・環境変数・フラグの順に値を上書きし、検証した設定を返却する
+// 設定ファイルは -config フラグか APP_CONFIG_FILE で指定し、拡張子が .json の場合は JSON、それ以外は YAML として読み取る
+// 空の環境変数は指定しなかったものとみなす
+// 不正な値がある場合は、すべての不正な値をまとめたエラーを返却する
+// -h が指定された場合は、使い方を出力して flag.ErrHelp を返却する
+func Load(args []string, lookupEnv LookupEnv) (Settings, error) {
+	var (
+		s      = Default()
+		fields = s.fields()
+	)
+
+	flagValues, path, err := parseFlags(fields, args)
+	if err != nil {
+		return Settings{}, err
+	}
+	if path == "" {
+		path, _ = lookupEnv(fileEnv)
+	}
+
+	var errs []error
+	if path != "" {
+		errs = append(errs, applyFile(fields, path)...)
+	}
+	errs = append(errs, applyEnv(fields, lookupEnv)...)
+	for _, f := range fields {
+		if v, ok := flagValues[f.key]; ok {
+			if err := f.set(v); err != nil {
+				errs = append(errs, fmt.Errorf("flag -%s: %w", f.key, err))
+			}
+		}
+	}
+
+	// 読み取れなかった値は前の値のまま検証し、すべてのエラーをまとめて返却する
+	if err := errors.Join(append(errs, s.Validate())...); err != nil {
+		return Settings{}, err
+	}
+
+	return s, nil
+}
+
+func (s *Settings) fields() []field {
+	return []field{
+		stringField("app.name", "APP_NAME", "application name", &s.App.Name),
+		intField("app.port", "APP_PORT", "HTTP port", &s.App.Port),
+		durationField("app.context_timeout", "APP_CONTEXT_TIMEOUT", "time given to a use case per request", &s.App.ContextTimeout),
+		boolField("app.legacy_errors", "APP_LEGACY_ERRORS", "use the legacy error response format", &s.App.LegacyErrors),
+		durationField("app.idempotency_ttl", "APP_IDEMPOTENCY_TTL", "how long Idempotency-Key responses are kept", &s.App.IdempotencyTTL),
+		stringField("app.trace_exporter", "APP_TRACE_EXPORTER", "where finished spans are written (none, stdout)", &s.App.TraceExporter),
+		stringField("app.health_disk_path", "APP_HEALTH_DISK_PATH", "directory whose free space is checked by /health/ready", &s.App.HealthDiskPath),
+
+		durationField("http.read_timeout", "HTTP_READ_TIMEOUT", "maximum duration for reading a request", &s.HTTP.ReadTimeout),
+		durationField("http.write_timeout", "HTTP_WRITE_TIMEOUT", "maximum duration for writing a response", &s.HTTP.WriteTimeout),
+		durationField("http.shutdown_timeout", "HTTP_SHUTDOWN_TIMEOUT", "time to wait for in-flight requests on shutdown", &s.HTTP.ShutdownTimeout),
+
+		stringField("database.driver", "POSTGRES_DRIVER", "database/sql driver name", &s.Database.Driver),
+		stringField("database.host", "POSTGRES_HOST", "database host", &s.Database.Host),
+		stringField("database.port", "POSTGRES_PORT", "database port", &s.Database.Port),
+		stringField("database.name", "POSTGRES_DB", "database name", &s.Database.Name),
+		stringField("database.user", "POSTGRES_USER", "database user", &s.Database.User),
+		stringField("database.password", "POSTGRES_PASSWORD", "database password", &s.Database.Password),
+		stringField("database.url", "DATABASE_URL", "database URL, used instead of host, port, name, user and password", &s.Database.URL),
+		stringField("database.sslmode", "POSTGRES_SSLMODE", "disable, require, verify-ca or verify-full", &s.Database.SSLMode),
+		stringField("database.sslrootcert", "POSTGRES_SSLROOTCERT", "CA certificate used to verify the server", &s.Database.SSLRootCert),
+		stringField("database.sslcert", "POSTGRES_SSLCERT", "client certificate", &s.Database.SSLCert),
+		stringField("database.sslkey", "POSTGRES_SSLKEY", "client private key", &s.Database.SSLKey),
+		intField("database.connect_retries", "POSTGRES_CONNECT_RETRIES", "maximum number of connection attempts on startup", &s.Database.ConnectRetries),
+		durationField("database.connect_backoff", "POSTGRES_CONNECT_BACKOFF", "wait after the first failed connection attempt", &s.Database.ConnectBackoff),
+		durationField("database.connect_max_backoff", "POSTGRES_CONNECT_MAX_BACKOFF", "longest wait between connection attempts", &s.Database.ConnectMaxBackoff),
+		durationField("database.connect_timeout", "POSTGRES_CONNECT_TIMEOUT", "startup deadline for all connection attempts", &s.Database.ConnectTimeout),
+		intField("database.max_open_conns", "POSTGRES_MAX_OPEN_CONNS", "maximum open connections (0 is unlimited)", &s.Database.MaxOpenConns),
+		intField("database.max_idle_conns", "POSTGRES_MAX_IDLE_CONNS", "maximum idle connections", &s.Database.MaxIdleConns),
+		durationField("database.conn_max_lifetime", "POSTGRES_CONN_MAX_LIFETIME", "close connections older than this (0 is no limit)", &s.Database.ConnMaxLifetime),
+		durationField("database.conn_max_idle_time", "POSTGRES_CONN_MAX_IDLE_TIME", "close connections idle for longer than this (0 is no limit)", &s.Database.ConnMaxIdleTime),
+	}
+}
+
+// フラグを解析し、指定されたフラグの値と設定ファイルのパスを返却する
+// フラグは設定ファイルと環境変数の後に反映するため、ここでは値を記録するだけにする
+func parseFlags(fields []field, args []string) (map[string]string, string, error) {
+	var (
+		fs     = flag.NewFlagSet("todo-api", flag.ContinueOnError)
+		values = make(map[string]string)
+		path   string
+	)
+
+	fs.StringVar(&path, fileFlag, "", "configuration file (YAML or JSON, also "+fileEnv+")")
+	for _, f := range fields {
+		var key = f.key
+		fs.Func(key, f.usage+" ("+f.env+")", func(v string) error {
+			values[key] = v
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, "", err
+	}
+	if fs.NArg() > 0 {
+		return nil, "", fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	return values, path, nil
+}
+
+// 設定ファイルの値を反映する
+// ネストしたキーは "." でつなげた名前 (database.host など) の項目に反映する
+func applyFile(fields []field, path string) []error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return []error{fmt.Errorf("config file: %w", err)}
+	}
+
+	var doc map[string]interface{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(b, &doc)
+	} else {
+		err = yaml.Unmarshal(b, &doc)
+	}
+	if err != nil {
+		return []error{fmt.Errorf("config file %s: %w", path, err)}
+	}
+
+	var values = make(map[string]string)
+	if err := flatten(values, "", doc); err != nil {
+		return []error{fmt.Errorf("config file %s: %w", path, err)}
+	}
+
+	var (
+		errs  []error
+		known = make(map[string]bool, len(fields))
+	)
+	for _, f := range fields {
+		known[f.key] = true
+		if v, ok := values[f.key]; ok {
+			if err := f.set(v); err != nil {
+				errs = append(errs, fmt.Errorf("config file %s: %s: %w", path, f.key, err))
+			}
+		}
+	}
+
+	// 書き間違いに気付けるよう、未知のキーはエラーにする
+	var unknown []string
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("config file %s: unknown key %q", path, key))
+	}
+
+	return errs
+}
+
+func flatten(dst map[string]string, prefix string, doc map[string]interface{}) error {
+	for k, v := range doc {
+		var key = k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if err := flatten(dst, key, v); err != nil {
+				return err
+			}
+		case []interface{}:
+			return fmt.Errorf("%s must be a single value", key)
+		case nil:
+			dst[key] = ""
+		default:
+			dst[key] = fmt.Sprint(v)
+		}
+	}
+
+	return nil
+}
+
+// 環境変数の値を反映する
+// KEY_FILE が指定されている場合は、ファイルの内容から末尾の改行を除いた値を使う
+func applyEnv(fields []field, lookupEnv LookupEnv) []error {
+	var errs []error
+	for _, f := range fields {
+		var (
+			value, _ = lookupEnv(f.env)
+			path, _  = lookupEnv(f.env + fileEnvSuffix)
+		)
+
+		if path != "" {
+			if value != "" {
+				errs = append(errs, fmt.Errorf("only one of %s and %s can be set", f.env, f.env+fileEnvSuffix))
+				continue
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env+fileEnvSuffix, err))
+				continue
+			}
+			value = strings.TrimRight(string(b), "\r\n")
+		}
+
+		if value == "" {
+			continue
+		}
+
+		if err := f.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+	}
+
+	return errs
+}
+
+func stringField(key, env, usage string, dst *string) field {
+	return field{key: key, env: env, usage: usage, set: func(v string) error {
+		*dst = v
+		return nil
+	}}
+}
+
+func intField(key, env, usage string, dst *int) field {
+	return field{key: key, env: env, usage: usage, set: func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("must be an integer: %q", v)
+		}
+
+		*dst = n
+		return nil
+	}}
+}
+
+func boolField(key, env, usage string, dst *bool) field {
+	return field{key: key, env: env, usage: usage, set: func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("must be true or false: %q", v)
+		}
+
+		*dst = b
+		return nil
+	}}
+}
+
+func durationField(key, env, usage string, dst *time.Duration) field {
+	return field{key: key, env: env, usage: usage, set: func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return fmt.Errorf("must be a non-negative duration such as 30s: %q", v)
+		}
+
+		*dst = d
+		return nil
+	}}
+}
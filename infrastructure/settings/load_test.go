@@ -0,0 +1,235 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	var path = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func envFrom(env map[string]string) LookupEnv {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	var (
+		yamlFile = writeFile(t, "config.yaml", `
+app:
+  name: todo
+  port: 9000
+  context_timeout: 5s
+http:
+  write_timeout: 30s
+database:
+  host: db
+  max_open_conns: 20
+`)
+		jsonFile   = writeFile(t, "config.json", `{"app":{"legacy_errors":true},"database":{"conn_max_lifetime":"30m"}}`)
+		secretFile = writeFile(t, "secret", "s3cr3t\n")
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected func(*Settings)
+	}{
+		{
+			name:     "Defaults",
+			expected: func(*Settings) {},
+		},
+		{
+			name: "YAML file",
+			args: []string{"-config", yamlFile},
+			expected: func(s *Settings) {
+				s.App.Name = "todo"
+				s.App.Port = 9000
+				s.App.ContextTimeout = 5 * time.Second
+				s.HTTP.WriteTimeout = 30 * time.Second
+				s.Database.Host = "db"
+				s.Database.MaxOpenConns = 20
+			},
+		},
+		{
+			name: "JSON file from environment",
+			env:  map[string]string{"APP_CONFIG_FILE": jsonFile},
+			expected: func(s *Settings) {
+				s.App.LegacyErrors = true
+				s.Database.ConnMaxLifetime = 30 * time.Minute
+			},
+		},
+		{
+			name: "Environment overrides file, flags override environment",
+			args: []string{"-config", yamlFile, "-app.port", "9100", "-database.host=flag-db"},
+			env: map[string]string{
+				"APP_PORT":                "9001",
+				"APP_NAME":                "env-todo",
+				"POSTGRES_HOST":           "env-db",
+				"POSTGRES_MAX_OPEN_CONNS": "",
+			},
+			expected: func(s *Settings) {
+				s.App.Name = "env-todo"
+				s.App.Port = 9100
+				s.App.ContextTimeout = 5 * time.Second
+				s.HTTP.WriteTimeout = 30 * time.Second
+				s.Database.Host = "flag-db"
+				s.Database.MaxOpenConns = 20
+			},
+		},
+		{
+			name: "Secrets from files",
+			env: map[string]string{
+				"POSTGRES_PASSWORD_FILE": secretFile,
+				"DATABASE_URL_FILE":      writeFile(t, "url", "postgres://todo:s3cr3t@db/todo\n"),
+			},
+			expected: func(s *Settings) {
+				s.Database.Password = "s3cr3t"
+				s.Database.URL = "postgres://todo:s3cr3t@db/todo"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := Load(tt.args, envFrom(tt.env))
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, nil)
+			}
+
+			var expected = Default()
+			tt.expected(&expected)
+
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%+v'", tt.name, result, expected)
+			}
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		secretFile  = writeFile(t, "secret", "s3cr3t\n")
+		unknownFile = writeFile(t, "config.yml", "app:\n  prot: 8080\ndatabase:\n  host: [a, b]\n")
+		typoFile    = writeFile(t, "typo.yml", "app:\n  prot: 8080\n")
+	)
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		// エラーに含まれる文字列
+		expectedErrs []string
+	}{
+		{
+			name: "Invalid values are reported together",
+			args: []string{"-http.read_timeout", "soon"},
+			env: map[string]string{
+				"APP_PORT":                     "70000",
+				"APP_LEGACY_ERRORS":            "yes",
+				"APP_TRACE_EXPORTER":           "jaeger",
+				"POSTGRES_CONNECT_RETRIES":     "0",
+				"POSTGRES_CONNECT_MAX_BACKOFF": "100ms",
+				"POSTGRES_CONN_MAX_LIFETIME":   "-1m",
+			},
+			expectedErrs: []string{
+				"flag -http.read_timeout",
+				"APP_LEGACY_ERRORS",
+				"POSTGRES_CONN_MAX_LIFETIME",
+				"app.port",
+				"app.trace_exporter",
+				"database.connect_retries",
+				"database.connect_max_backoff",
+			},
+		},
+		{
+			name: "Values out of range",
+			args: []string{"-http.shutdown_timeout", "0s", "-database.max_idle_conns", "-1"},
+			env:  map[string]string{"POSTGRES_SSLMODE": "prefer"},
+			expectedErrs: []string{
+				"http.shutdown_timeout",
+				"database.max_idle_conns",
+				"database.sslmode",
+			},
+		},
+		{
+			name: "Invalid secrets",
+			env: map[string]string{
+				"POSTGRES_PASSWORD":      "pass",
+				"POSTGRES_PASSWORD_FILE": secretFile,
+				"POSTGRES_USER_FILE":     secretFile + ".missing",
+				"DATABASE_URL":           "mysql://todo:pass@db/todo",
+			},
+			expectedErrs: []string{
+				"POSTGRES_PASSWORD_FILE",
+				"POSTGRES_USER_FILE",
+				"database.url",
+			},
+		},
+		{
+			name:         "Nested value in file",
+			args:         []string{"-config", unknownFile},
+			expectedErrs: []string{"database.host must be a single value"},
+		},
+		{
+			name:         "Unknown key in file",
+			args:         []string{"-config", typoFile},
+			expectedErrs: []string{`unknown key "app.prot"`},
+		},
+		{
+			name:         "Missing file",
+			args:         []string{"-config", typoFile + ".missing"},
+			expectedErrs: []string{"config file"},
+		},
+		{
+			name:         "Unexpected arguments",
+			args:         []string{"serve"},
+			expectedErrs: []string{"unexpected arguments: serve"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(tt.args, envFrom(tt.env))
+			if err == nil {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: an error", tt.name, err)
+			}
+
+			for _, expected := range tt.expectedErrs {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("[TestCase '%s'] Result: '%v' | Expected: an error containing '%v'", tt.name, err, expected)
+				}
+			}
+
+			// パスワードはエラーに含めない
+			if strings.Contains(err.Error(), "pass@") || strings.Contains(err.Error(), "s3cr3t") {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: no password in the error", tt.name, err)
+			}
+		})
+	}
+}
@@ -1,62 +1,148 @@
 package validation
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/doglapping707/todo-api-go/adapter/locale"
 	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/go-playground/locales"
 	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ja"
 	ut "github.com/go-playground/universal-translator"
 	go_playground "github.com/go-playground/validator/v10"
 	en_translations "github.com/go-playground/validator/v10/translations/en"
+	ja_translations "github.com/go-playground/validator/v10/translations/ja"
 )
 
+// 言語ごとの翻訳
+// 言語を追加する場合は locale.Supported とこの一覧の両方に追加する
+var translations = map[string]struct {
+	locale   locales.Translator
+	register func(*go_playground.Validate, ut.Translator) error
+}{
+	locale.English: {
+		locale:   en.New(),
+		register: en_translations.RegisterDefaultTranslations,
+	},
+	locale.Japanese: {
+		locale:   ja.New(),
+		register: ja_translations.RegisterDefaultTranslations,
+	},
+}
+
 // バリデーター
+// go_playground.Validate と ut.Translator は並行して利用できるため、
+// 検証ごとの状態はフィールドに保持しない
 type goPlayground struct {
-	validator *go_playground.Validate
-	translate ut.Translator
-	err       error
-	msg       []string
+	validator  *go_playground.Validate
+	translates map[string]ut.Translator
 }
 
-// バリデーターを生成し返却する
+// タスクと Webhook のルールを登録したバリデーターを生成し返却する
 func NewGoPlayground() (validator.Validator, error) {
+	return NewGoPlaygroundWithRules(taskRules().Register(webhookRules()...))
+}
+
+// 独自のルールを登録したバリデーターを生成し返却する
+func NewGoPlaygroundWithRules(rules *Registry) (validator.Validator, error) {
 	var (
-		language         = en.New()
-		uni              = ut.New(language, language)
-		translate, found = uni.GetTranslator("en")
+		fallback = translations[locale.Default()].locale
+		uni      = ut.New(fallback)
+		v        = go_playground.New()
 	)
 
-	if !found {
-		return nil, errors.New("translator not found")
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	var translates = make(map[string]ut.Translator, len(locale.Supported))
+	for _, lang := range locale.Supported {
+		t, ok := translations[lang]
+		if !ok {
+			return nil, fmt.Errorf("translation not registered: %s", lang)
+		}
+
+		if err := uni.AddTranslator(t.locale, true); err != nil {
+			return nil, err
+		}
+
+		translate, found := uni.GetTranslator(t.locale.Locale())
+		if !found {
+			return nil, errors.New("translator not found")
+		}
+
+		if err := t.register(v, translate); err != nil {
+			return nil, err
+		}
+
+		translates[lang] = translate
 	}
 
-	v := go_playground.New()
-	if err := en_translations.RegisterDefaultTranslations(v, translate); err != nil {
-		return nil, errors.New("translator not found")
+	if err := rules.apply(v, translates); err != nil {
+		return nil, err
 	}
 
-	return &goPlayground{validator: v, translate: translate}, nil
+	return goPlayground{validator: v, translates: translates}, nil
 }
 
-func (g *goPlayground) Validate(i interface{}) error {
-	if len(g.msg) > 0 {
-		g.msg = nil
+// 構造体を検証し、コンテキストの言語で翻訳した違反の一覧を返却する
+func (g goPlayground) Validate(ctx context.Context, i interface{}) []validator.Violation {
+	var (
+		violations []validator.Violation
+		errs       go_playground.ValidationErrors
+	)
+
+	err := g.validator.StructCtx(ctx, i)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.As(err, &errs):
+		// 構造体以外が渡された場合
+		return []validator.Violation{{Rule: "struct", Message: err.Error()}}
 	}
 
-	g.err = g.validator.Struct(i)
-	if g.err != nil {
-		return g.err
+	var translate = g.translator(locale.FromContext(ctx))
+	for _, err := range errs {
+		violations = append(violations, validator.Violation{
+			Field:   fieldPath(err),
+			Rule:    err.Tag(),
+			Param:   err.Param(),
+			Message: err.Translate(translate),
+		})
 	}
 
-	return nil
+	return violations
 }
 
-func (g *goPlayground) Messages() []string {
-	if g.err != nil {
-		for _, err := range g.err.(go_playground.ValidationErrors) {
-			g.msg = append(g.msg, err.Translate(g.translate))
-		}
+func (g goPlayground) translator(lang string) ut.Translator {
+	if t, ok := g.translates[lang]; ok {
+		return t
+	}
+
+	return g.translates[locale.Default()]
+}
+
+// エラーメッセージとフィールドパスにJSONのフィールド名を使用する
+func jsonFieldName(field reflect.StructField) string {
+	var name = strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+// 構造体名を除いたフィールドのパスを返却する (例: "CreateTaskInput.title" → "title")
+func fieldPath(err go_playground.FieldError) string {
+	var ns = err.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
 	}
 
-	return g.msg
+	return err.Field()
 }
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/adapter/locale"
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+)
+
+func TestGoPlayground_Validate(t *testing.T) {
+	type input struct {
+		Title string   `json:"title" validate:"required,lte=5"`
+		Tags  []string `json:"tags,omitempty" validate:"dive,lte=3"`
+		Note  string   `validate:"required"`
+	}
+
+	tests := []struct {
+		name     string
+		lang     string
+		input    input
+		expected []validator.Violation
+	}{
+		{
+			name:     "Valid input",
+			input:    input{Title: "Task", Tags: []string{"a"}, Note: "n"},
+			expected: nil,
+		},
+		{
+			name:  "JSON field names and rule parameters",
+			input: input{Title: "Task_1", Tags: []string{"ok", "long"}},
+			expected: []validator.Violation{
+				{Field: "title", Rule: "lte", Param: "5", Message: "title must be at maximum 5 characters in length"},
+				{Field: "tags[1]", Rule: "lte", Param: "3", Message: "tags[1] must be at maximum 3 characters in length"},
+				{Field: "Note", Rule: "required", Message: "Note is a required field"},
+			},
+		},
+		{
+			name:  "Japanese messages",
+			lang:  locale.Japanese,
+			input: input{Title: "Task_1", Note: "n"},
+			expected: []validator.Violation{
+				{Field: "title", Rule: "lte", Param: "5", Message: "titleの長さは最大でも5文字でなければなりません"},
+			},
+		},
+		{
+			name:  "Unsupported language falls back to English",
+			lang:  "fr",
+			input: input{Title: "Task", Note: ""},
+			expected: []validator.Violation{
+				{Field: "Note", Rule: "required", Message: "Note is a required field"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewGoPlayground()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ctx = context.Background()
+			if tt.lang != "" {
+				ctx = locale.WithLanguage(ctx, tt.lang)
+			}
+
+			if got := v.Validate(ctx, tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Got: '%+v' | Want: '%+v'", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/doglapping707/todo-api-go/adapter/locale"
+	ut "github.com/go-playground/universal-translator"
+	go_playground "github.com/go-playground/validator/v10"
+)
+
+// 独自のバリデーションルール
+// `validate:"<Tag>"` のようにタグで指定して使用する
+type Rule struct {
+	Tag  string
+	Func go_playground.Func
+	// 言語ごとのメッセージ ({0} はフィールド名、{1} はパラメータに置換される)
+	Messages map[string]string
+}
+
+// 構造体単位のバリデーションルール
+// 複数のフィールドにまたがる検証に使用し、違反は sl.ReportError で Tags のいずれかを指定して報告する
+type StructRule struct {
+	Func  go_playground.StructLevelFunc
+	Types []interface{}
+	// 報告するタグと、言語ごとのメッセージ
+	Tags map[string]map[string]string
+}
+
+// 独自のバリデーションルールの一覧
+type Registry struct {
+	rules       []Rule
+	structRules []StructRule
+}
+
+// 空のルール一覧を生成し返却する
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// ルールを追加する
+func (r *Registry) Register(rules ...Rule) *Registry {
+	r.rules = append(r.rules, rules...)
+	return r
+}
+
+// 構造体単位のルールを追加する
+func (r *Registry) RegisterStruct(rules ...StructRule) *Registry {
+	r.structRules = append(r.structRules, rules...)
+	return r
+}
+
+// ルールと翻訳をバリデーターに登録する
+func (r *Registry) apply(v *go_playground.Validate, translates map[string]ut.Translator) error {
+	for _, rule := range r.rules {
+		if err := v.RegisterValidation(rule.Tag, rule.Func); err != nil {
+			return fmt.Errorf("error registering rule %s: %w", rule.Tag, err)
+		}
+
+		if err := registerMessages(v, translates, rule.Tag, rule.Messages); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range r.structRules {
+		v.RegisterStructValidation(rule.Func, rule.Types...)
+
+		for tag, messages := range rule.Tags {
+			if err := registerMessages(v, translates, tag, messages); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// タグのメッセージを言語ごとに登録する
+// メッセージがない言語はフォールバックの言語のメッセージを使用する
+func registerMessages(v *go_playground.Validate, translates map[string]ut.Translator, tag string, messages map[string]string) error {
+	fallback, ok := messages[locale.Default()]
+	if !ok {
+		return fmt.Errorf("message for rule %s is not defined in %s", tag, locale.Default())
+	}
+
+	for lang, translate := range translates {
+		var msg, ok = messages[lang]
+		if !ok {
+			msg = fallback
+		}
+
+		if err := v.RegisterTranslation(
+			tag,
+			translate,
+			func(t ut.Translator) error {
+				return t.Add(tag, msg, true)
+			},
+			func(t ut.Translator, fe go_playground.FieldError) string {
+				s, err := t.T(tag, fe.Field(), fe.Param())
+				if err != nil {
+					return fe.Error()
+				}
+
+				return s
+			},
+		); err != nil {
+			return fmt.Errorf("error registering message for rule %s: %w", tag, err)
+		}
+	}
+
+	return nil
+}
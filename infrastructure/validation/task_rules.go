@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/doglapping707/todo-api-go/adapter/ical"
+	"github.com/doglapping707/todo-api-go/adapter/locale"
+	"github.com/doglapping707/todo-api-go/usecase"
+	go_playground "github.com/go-playground/validator/v10"
+)
+
+// 現在時刻 (テストで差し替える)
+var now = time.Now
+
+// 英小文字と数字をハイフンでつないだ文字列 (例: "work", "side-project-2")
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// タスクの入力値に使用するルールを返却する
+func taskRules() *Registry {
+	return NewRegistry().
+		Register(
+			Rule{
+				Tag:  "notblank",
+				Func: notBlank,
+				Messages: map[string]string{
+					locale.English:  "{0} must not be blank",
+					locale.Japanese: "{0}は空白のみにできません",
+				},
+			},
+			Rule{
+				Tag:  "slug",
+				Func: slug,
+				Messages: map[string]string{
+					locale.English:  "{0} must contain only lowercase letters, numbers and hyphens",
+					locale.Japanese: "{0}には英小文字、数字、ハイフンのみ使用できます",
+				},
+			},
+			Rule{
+				Tag:  "nospace",
+				Func: noSpace,
+				Messages: map[string]string{
+					locale.English:  "{0} must not contain whitespace",
+					locale.Japanese: "{0}に空白を含めることはできません",
+				},
+			},
+			Rule{
+				Tag:  "rrule",
+				Func: rrule,
+				Messages: map[string]string{
+					locale.English:  "{0} must be a valid iCalendar RRULE such as FREQ=WEEKLY;BYDAY=MO",
+					locale.Japanese: "{0}にはFREQ=WEEKLY;BYDAY=MOのようなiCalendarのRRULEを指定してください",
+				},
+			},
+		).
+		RegisterStruct(
+			StructRule{
+				Func:  dueDateNotPast,
+				Types: []interface{}{usecase.CreateTaskInput{}, usecase.BatchOperationInput{}},
+				Tags: map[string]map[string]string{
+					"notpast": {
+						locale.English:  "{0} must not be in the past unless the task is completed",
+						locale.Japanese: "完了していないタスクの{0}に過去の日時は指定できません",
+					},
+				},
+			},
+		)
+}
+
+// 空白以外の文字を含むこと
+func notBlank(fl go_playground.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+// URLやフィルタにそのまま使用できる文字列であること
+func slug(fl go_playground.FieldLevel) bool {
+	return slugPattern.MatchString(fl.Field().String())
+}
+
+// 空白を含まないこと (todo.txt の key:value として書き出せること)
+func noSpace(fl go_playground.FieldLevel) bool {
+	return !strings.ContainsFunc(fl.Field().String(), unicode.IsSpace)
+}
+
+// iCalendar の繰り返しの規則として解釈できること
+func rrule(fl go_playground.FieldLevel) bool {
+	return ical.ValidRRule(fl.Field().String())
+}
+
+// 作成する完了していないタスクの期日が過去でないこと
+// 一括操作では create の task だけを検証する (期日を過ぎたタスクの更新は許可する)
+func dueDateNotPast(sl go_playground.StructLevel) {
+	switch input := sl.Current().Interface().(type) {
+	case usecase.CreateTaskInput:
+		if pastDueDate(input.DueDate, input.Completed) {
+			sl.ReportError(input.DueDate, "due_date", "DueDate", "notpast", "")
+		}
+	case usecase.BatchOperationInput:
+		if input.Op == usecase.BatchOpCreate && input.Task != nil && pastDueDate(input.Task.DueDate, input.Task.Completed) {
+			sl.ReportError(input.Task.DueDate, "task.due_date", "Task.DueDate", "notpast", "")
+		}
+	}
+}
+
+func pastDueDate(dueDate *time.Time, completed bool) bool {
+	return dueDate != nil && !completed && dueDate.Before(now())
+}
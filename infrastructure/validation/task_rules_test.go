@@ -0,0 +1,151 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/adapter/locale"
+	"github.com/doglapping707/todo-api-go/adapter/validator"
+	"github.com/doglapping707/todo-api-go/usecase"
+)
+
+func TestTaskRules(t *testing.T) {
+	var fixedNow = time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+	defer func() { now = time.Now }()
+
+	var (
+		past   = fixedNow.Add(-time.Hour)
+		future = fixedNow.Add(time.Hour)
+	)
+
+	tests := []struct {
+		name     string
+		lang     string
+		input    interface{}
+		expected []validator.Violation
+	}{
+		{
+			name:     "Valid create input",
+			input:    usecase.CreateTaskInput{Title: "Task", DueDate: &future, Tags: []string{"work", "side-project-2"}},
+			expected: nil,
+		},
+		{
+			name:  "Blank title",
+			input: usecase.CreateTaskInput{Title: "   "},
+			expected: []validator.Violation{
+				{Field: "title", Rule: "notblank", Message: "title must not be blank"},
+			},
+		},
+		{
+			name:  "Tag is not a slug",
+			input: usecase.UpdateTaskInput{Title: "Task", Tags: []string{"work", "Side Project"}},
+			expected: []validator.Violation{
+				{Field: "tags[1]", Rule: "slug", Message: "tags[1] must contain only lowercase letters, numbers and hyphens"},
+			},
+		},
+		{
+			name:  "Invalid recurrence",
+			input: usecase.UpdateTaskInput{Title: "Task", Recurrence: "FREQ=FORTNIGHTLY"},
+			expected: []validator.Violation{
+				{Field: "recurrence", Rule: "rrule", Message: "recurrence must be a valid iCalendar RRULE such as FREQ=WEEKLY;BYDAY=MO"},
+			},
+		},
+		{
+			name:     "Valid recurrence",
+			input:    usecase.UpdateTaskInput{Title: "Task", Priority: 9, Recurrence: "FREQ=WEEKLY;BYDAY=MO"},
+			expected: nil,
+		},
+		{
+			name:  "Due date in the past",
+			input: usecase.CreateTaskInput{Title: "Task", DueDate: &past},
+			expected: []validator.Violation{
+				{Field: "due_date", Rule: "notpast", Message: "due_date must not be in the past unless the task is completed"},
+			},
+		},
+		{
+			name:  "Due date in the past in Japanese",
+			lang:  locale.Japanese,
+			input: usecase.CreateTaskInput{Title: "Task", DueDate: &past},
+			expected: []validator.Violation{
+				{Field: "due_date", Rule: "notpast", Message: "完了していないタスクのdue_dateに過去の日時は指定できません"},
+			},
+		},
+		{
+			name:     "Due date in the past for a completed task",
+			input:    usecase.CreateTaskInput{Title: "Task", DueDate: &past, Completed: true},
+			expected: nil,
+		},
+		{
+			name:     "Overdue task can still be updated",
+			input:    usecase.UpdateTaskInput{Title: "Task", DueDate: &past},
+			expected: nil,
+		},
+		{
+			name:     "Valid webhook",
+			input:    usecase.CreateWebhookInput{URL: "https://example.com/hooks", Events: []string{"task.created"}},
+			expected: nil,
+		},
+		{
+			name:  "Webhook URL is not http",
+			input: usecase.CreateWebhookInput{URL: "ftp://example.com/hooks", Events: []string{"task.created"}},
+			expected: []validator.Violation{
+				{Field: "url", Rule: "httpurl", Message: "url must be an absolute http or https URL"},
+			},
+		},
+		{
+			name:  "Webhook URL to the metadata service",
+			input: usecase.CreateWebhookInput{URL: "http://169.254.169.254/latest/meta-data", Events: []string{"task.created"}},
+			expected: []validator.Violation{
+				{Field: "url", Rule: "publichost", Message: "url must not point to a private or local address"},
+			},
+		},
+		{
+			name:  "Webhook URL to localhost in Japanese",
+			lang:  locale.Japanese,
+			input: usecase.CreateWebhookInput{URL: "http://localhost:8080/hooks", Events: []string{"task.created"}},
+			expected: []validator.Violation{
+				{Field: "url", Rule: "publichost", Message: "urlにはプライベートまたはローカルのアドレスを指定できません"},
+			},
+		},
+		{
+			name:  "Unknown webhook event",
+			input: usecase.CreateWebhookInput{URL: "https://example.com/hooks", Events: []string{"task.archived"}},
+			expected: []validator.Violation{
+				{Field: "events[0]", Rule: "oneof", Param: "task.created task.updated task.deleted", Message: "events[0] must be one of [task.created task.updated task.deleted]"},
+			},
+		},
+	}
+
+	v, err := NewGoPlayground()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctx = context.Background()
+			if tt.lang != "" {
+				ctx = locale.WithLanguage(ctx, tt.lang)
+			}
+
+			if got := v.Validate(ctx, tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Got: '%+v' | Want: '%+v'", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewGoPlaygroundWithRules_MissingDefaultMessage(t *testing.T) {
+	var rules = NewRegistry().Register(Rule{
+		Tag:      "custom",
+		Func:     notBlank,
+		Messages: map[string]string{locale.Japanese: "{0}は不正です"},
+	})
+
+	if _, err := NewGoPlaygroundWithRules(rules); err == nil {
+		t.Error("expected an error when the default language message is missing")
+	}
+}
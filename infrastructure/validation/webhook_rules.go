@@ -0,0 +1,63 @@
+package validation
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/doglapping707/todo-api-go/adapter/locale"
+	"github.com/doglapping707/todo-api-go/adapter/webhook"
+	go_playground "github.com/go-playground/validator/v10"
+)
+
+// Webhook の入力値に使用するルールを返却する
+func webhookRules() []Rule {
+	return []Rule{
+		{
+			Tag:  "httpurl",
+			Func: httpURL,
+			Messages: map[string]string{
+				locale.English:  "{0} must be an absolute http or https URL",
+				locale.Japanese: "{0}にはhttpまたはhttpsの絶対URLを指定してください",
+			},
+		},
+		{
+			Tag:  "publichost",
+			Func: publicHost,
+			Messages: map[string]string{
+				locale.English:  "{0} must not point to a private or local address",
+				locale.Japanese: "{0}にはプライベートまたはローカルのアドレスを指定できません",
+			},
+		},
+	}
+}
+
+// 配信先として使用できる http/https の絶対URLであること
+func httpURL(fl go_playground.FieldLevel) bool {
+	u, err := url.Parse(fl.Field().String())
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// URLのホストがループバックやプライベートのアドレス、localhost でないこと
+// 名前で指定されたホストの解決先は、配信する時に接続するアドレスで確認する
+func publicHost(fl go_playground.FieldLevel) bool {
+	u, err := url.Parse(fl.Field().String())
+	if err != nil {
+		return false
+	}
+
+	var host = strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return webhook.IsPublicAddress(ip)
+	}
+
+	return true
+}
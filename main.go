@@ -1,25 +1,46 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"os"
-	"time"
 
 	"github.com/doglapping707/todo-api-go/infrastructure"
 	"github.com/doglapping707/todo-api-go/infrastructure/database"
 	"github.com/doglapping707/todo-api-go/infrastructure/log"
 	"github.com/doglapping707/todo-api-go/infrastructure/router"
+	"github.com/doglapping707/todo-api-go/infrastructure/settings"
 	"github.com/doglapping707/todo-api-go/infrastructure/validation"
 )
 
 func main() {
+	// ロガーを設定する前に読み取るため、エラーは標準エラー出力に書き込む
+	s, err := settings.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid configuration:")
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
 	var app = infrastructure.NewConfig().
-		Name(os.Getenv("APP_NAME")).
-		ContextTimeout(10 * time.Second).
+		Name(s.App.Name).
+		ContextTimeout(s.App.ContextTimeout).
+		LegacyErrorFormat(s.App.LegacyErrors).
 		Logger(log.InstanceLogrusLogger).
+		Tracing(s.App.TraceExporter).
 		Validator(validation.InstanceGoPlayground).
-		DbSQL(database.InstancePostgres)
+		DbSQL(database.InstancePostgres, s.Database).
+		DbListener(database.InstancePostgres, s.Database).
+		Metrics().
+		HealthChecks(s.App.HealthDiskPath)
 
-	app.WebServerPort(os.Getenv("APP_PORT")).
+	app.WebServerPort(s.App.Port).
+		HTTPTimeouts(s.HTTP).
+		IdempotencyTTL(s.App.IdempotencyTTL).
 		WebServer(router.InstanceGorillaMux).
 		Start()
 }
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// 一括操作の種類
+const (
+	BatchOpCreate   = "create"
+	BatchOpUpdate   = "update"
+	BatchOpDelete   = "delete"
+	BatchOpComplete = "complete"
+)
+
+type (
+	BatchTaskUseCase interface {
+		Execute(context.Context, BatchTaskInput) (BatchTaskOutput, error)
+	}
+
+	BatchTaskInput struct {
+		Operations []BatchOperationInput `json:"operations" validate:"required,gte=1,lte=500,dive"`
+		// true の場合は失敗した操作だけを取り消し、残りの操作を続行する
+		ContinueOnError bool `json:"continue_on_error"`
+	}
+
+	BatchOperationInput struct {
+		Op     string        `json:"op" validate:"required,oneof=create update delete complete"`
+		TaskID domain.TaskID `json:"task_id" validate:"required_unless=Op create"`
+		// create と update で使用するタスクの内容
+		Task *UpdateTaskInput `json:"task" validate:"required_if=Op create,required_if=Op update"`
+	}
+
+	BatchTaskOutput struct {
+		// すべての操作がコミットされたか
+		Committed bool
+		// 操作と同じ順番の結果
+		Results []BatchOperationOutput
+	}
+
+	BatchOperationOutput struct {
+		Op     string
+		TaskID domain.TaskID
+		// 操作後のタスク (delete の場合と、コミットされなかった場合は nil)
+		Task *UpdateTaskOutput
+		// 操作が失敗した場合のエラー
+		Err error
+	}
+
+	BatchTaskInteractor struct {
+		repo       domain.TaskRepository
+		outbox     domain.OutboxRepository
+		presenter  UpdateTaskPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewBatchTaskInteractor(
+	taskRepo domain.TaskRepository,
+	outbox domain.OutboxRepository,
+	presenter UpdateTaskPresenter,
+	t time.Duration,
+) BatchTaskUseCase {
+	return BatchTaskInteractor{
+		repo:       taskRepo,
+		outbox:     outbox,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// 複数の操作を1つのトランザクションで実行する
+// いずれかの操作が失敗した場合はすべてロールバックし、Committed を false にして返却する
+// ContinueOnError の場合は失敗した操作だけをロールバックし、残りをコミットする
+func (t BatchTaskInteractor) Execute(ctx context.Context, input BatchTaskInput) (BatchTaskOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.BatchTask")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var results = make([]BatchOperationOutput, len(input.Operations))
+	for i, op := range input.Operations {
+		results[i] = BatchOperationOutput{Op: op.Op, TaskID: op.TaskID}
+	}
+
+	var opFailed bool
+	err := t.repo.WithTransaction(ctx, func(ctxTx context.Context) error {
+		for i, op := range input.Operations {
+			var (
+				task domain.Task
+				err  error
+			)
+
+			if input.ContinueOnError {
+				// 失敗してもトランザクション全体が中断されないよう、操作ごとにセーブポイントを作成する
+				err = t.repo.WithTransaction(ctxTx, func(ctxSp context.Context) error {
+					task, err = t.execute(ctxSp, op)
+					return err
+				})
+			} else {
+				task, err = t.execute(ctxTx, op)
+			}
+
+			if err != nil {
+				results[i].Err = err
+				if !input.ContinueOnError {
+					opFailed = true
+					return err
+				}
+				continue
+			}
+
+			results[i].TaskID = task.ID
+			if op.Op != BatchOpDelete {
+				var output = t.presenter.Output(task)
+				results[i].Task = &output
+			}
+		}
+
+		return nil
+	})
+
+	switch {
+	case opFailed:
+		// ロールバックされた操作の結果は返却しない
+		for i := range results {
+			results[i].Task = nil
+		}
+		return BatchTaskOutput{Committed: false, Results: results}, nil
+	case err != nil:
+		return BatchTaskOutput{}, err
+	}
+
+	return BatchTaskOutput{Committed: true, Results: results}, nil
+}
+
+// 操作を実行し、操作ごとのイベントを書き込む
+// 操作がロールバックされた場合はイベントもロールバックされる
+func (t BatchTaskInteractor) execute(ctx context.Context, op BatchOperationInput) (domain.Task, error) {
+	task, event, err := t.apply(ctx, op)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	if err := t.outbox.Append(ctx, event); err != nil {
+		return domain.Task{}, err
+	}
+
+	return task, nil
+}
+
+func (t BatchTaskInteractor) apply(ctx context.Context, op BatchOperationInput) (domain.Task, domain.Event, error) {
+	var (
+		task domain.Task
+		err  error
+	)
+
+	switch op.Op {
+	case BatchOpCreate:
+		if task, err = t.repo.Create(ctx, op.Task.toTask()); err != nil {
+			return domain.Task{}, domain.Event{}, err
+		}
+		return task, domain.NewTaskEvent(domain.EventTaskCreated, task), nil
+	case BatchOpUpdate:
+		if task, err = t.repo.Update(ctx, op.Task.toTask(), op.TaskID); err != nil {
+			return domain.Task{}, domain.Event{}, err
+		}
+		return task, domain.NewTaskEvent(domain.EventTaskUpdated, task), nil
+	case BatchOpComplete:
+		if task, err = t.repo.FindByID(ctx, op.TaskID); err != nil {
+			return domain.Task{}, domain.Event{}, err
+		}
+
+		task.Completed = true
+		if task, err = t.repo.Update(ctx, task, op.TaskID); err != nil {
+			return domain.Task{}, domain.Event{}, err
+		}
+		return task, domain.NewTaskEvent(domain.EventTaskUpdated, task), nil
+	case BatchOpDelete:
+		if err = t.repo.Delete(ctx, op.TaskID); err != nil {
+			return domain.Task{}, domain.Event{}, err
+		}
+		return domain.Task{ID: op.TaskID}, domain.NewTaskDeletedEvent(op.TaskID), nil
+	}
+
+	return domain.Task{}, domain.Event{}, domain.NewError(domain.KindValidation, "unknown batch operation: "+op.Op)
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// メモリ上でトランザクションを再現するリポジトリ (outbox を兼ねる)
+// WithTransaction は関数がエラーを返却した場合に呼び出し前の状態へ戻す
+type mockTaskRepoBatch struct {
+	domain.TaskRepository
+
+	tasks  map[domain.TaskID]domain.Task
+	events []domain.Event
+	nextID domain.TaskID
+}
+
+func newMockTaskRepoBatch(tasks ...domain.Task) *mockTaskRepoBatch {
+	var m = &mockTaskRepoBatch{tasks: map[domain.TaskID]domain.Task{}, nextID: 100}
+	for _, task := range tasks {
+		m.tasks[task.ID] = task
+	}
+	return m
+}
+
+func (m *mockTaskRepoBatch) Create(_ context.Context, task domain.Task) (domain.Task, error) {
+	m.nextID++
+	task.ID = m.nextID
+	m.tasks[task.ID] = task
+	return task, nil
+}
+
+func (m *mockTaskRepoBatch) Update(_ context.Context, task domain.Task, taskID domain.TaskID) (domain.Task, error) {
+	if _, ok := m.tasks[taskID]; !ok {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+	task.ID = taskID
+	m.tasks[taskID] = task
+	return task, nil
+}
+
+func (m *mockTaskRepoBatch) FindByID(_ context.Context, taskID domain.TaskID) (domain.Task, error) {
+	task, ok := m.tasks[taskID]
+	if !ok {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (m *mockTaskRepoBatch) Delete(_ context.Context, taskID domain.TaskID) error {
+	if _, ok := m.tasks[taskID]; !ok {
+		return domain.ErrTaskNotFound
+	}
+	delete(m.tasks, taskID)
+	return nil
+}
+
+func (m *mockTaskRepoBatch) Append(_ context.Context, events ...domain.Event) error {
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *mockTaskRepoBatch) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	var snapshot = make(map[domain.TaskID]domain.Task, len(m.tasks))
+	for id, task := range m.tasks {
+		snapshot[id] = task
+	}
+	var events = len(m.events)
+
+	if err := fn(ctx); err != nil {
+		m.tasks = snapshot
+		m.events = m.events[:events]
+		return err
+	}
+	return nil
+}
+
+// outbox として使用するためのラッパー
+type mockOutboxBatch struct {
+	domain.OutboxRepository
+
+	repo *mockTaskRepoBatch
+}
+
+func (m mockOutboxBatch) Append(ctx context.Context, events ...domain.Event) error {
+	return m.repo.Append(ctx, events...)
+}
+
+func TestBatchTaskInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var operations = []BatchOperationInput{
+		{Op: BatchOpCreate, Task: &UpdateTaskInput{Title: "Task_new"}},
+		{Op: BatchOpComplete, TaskID: 1},
+		{Op: BatchOpDelete, TaskID: 99},
+		{Op: BatchOpUpdate, TaskID: 2, Task: &UpdateTaskInput{Title: "Task_2_updated"}},
+	}
+
+	tests := []struct {
+		name              string
+		continueOnError   bool
+		expectedCommitted bool
+		expectedErrors    []bool
+		expectedTasks     map[domain.TaskID]string
+		expectedEvents    []string
+	}{
+		{
+			name:              "Batch rolls back every operation on error",
+			expectedCommitted: false,
+			expectedErrors:    []bool{false, false, true, false},
+			expectedTasks:     map[domain.TaskID]string{1: "Task_1", 2: "Task_2"},
+			expectedEvents:    []string{},
+		},
+		{
+			name:              "Batch continues on error",
+			continueOnError:   true,
+			expectedCommitted: true,
+			expectedErrors:    []bool{false, false, true, false},
+			expectedTasks:     map[domain.TaskID]string{1: "Task_1", 2: "Task_2_updated", 101: "Task_new"},
+			expectedEvents:    []string{domain.EventTaskCreated, domain.EventTaskUpdated, domain.EventTaskUpdated},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var repo = newMockTaskRepoBatch(
+				domain.Task{ID: 1, Title: "Task_1"},
+				domain.Task{ID: 2, Title: "Task_2"},
+			)
+
+			var uc = NewBatchTaskInteractor(repo, mockOutboxBatch{repo: repo}, mockUpdateTaskPresenter{}, time.Second)
+
+			result, err := uc.Execute(context.TODO(), BatchTaskInput{
+				Operations:      operations,
+				ContinueOnError: tt.continueOnError,
+			})
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] unexpected error: %v", tt.name, err)
+			}
+
+			if result.Committed != tt.expectedCommitted {
+				t.Errorf("[TestCase '%s'] Committed: '%v' | Expected: '%v'", tt.name, result.Committed, tt.expectedCommitted)
+			}
+
+			for i, r := range result.Results {
+				if (r.Err != nil) != tt.expectedErrors[i] {
+					t.Errorf("[TestCase '%s'] operation %d error: '%v'", tt.name, i, r.Err)
+				}
+			}
+
+			if !errors.Is(result.Results[2].Err, domain.ErrTaskNotFound) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result.Results[2].Err, domain.ErrTaskNotFound)
+			}
+
+			if len(repo.tasks) != len(tt.expectedTasks) {
+				t.Errorf("[TestCase '%s'] Tasks: '%v' | Expected: '%v'", tt.name, repo.tasks, tt.expectedTasks)
+			}
+			for id, title := range tt.expectedTasks {
+				if repo.tasks[id].Title != title {
+					t.Errorf("[TestCase '%s'] Task %d: '%v' | Expected: '%v'", tt.name, id, repo.tasks[id].Title, title)
+				}
+			}
+
+			if tt.continueOnError && !repo.tasks[1].Completed {
+				t.Errorf("[TestCase '%s'] task 1 was not completed", tt.name)
+			}
+
+			// ロールバックされた操作のイベントは残らない
+			var events = make([]string, 0, len(repo.events))
+			for _, event := range repo.events {
+				events = append(events, event.Type)
+			}
+			if !reflect.DeepEqual(events, tt.expectedEvents) {
+				t.Errorf("[TestCase '%s'] Events: '%v' | Expected: '%v'", tt.name, events, tt.expectedEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	CalendarFeedUseCase interface {
+		Execute(context.Context, string) ([]CalendarTaskOutput, error)
+	}
+
+	CalendarFeedPresenter interface {
+		Output([]domain.Task) []CalendarTaskOutput
+	}
+
+	CalendarTaskOutput struct {
+		ID          domain.TaskID
+		Title       string
+		DueDate     *time.Time
+		Tags        []string
+		Completed   bool
+		CompletedAt *time.Time
+		Priority    int
+		Recurrence  string
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+	}
+
+	calendarFeedInteractor struct {
+		tokenRepo  domain.CalendarTokenRepository
+		taskRepo   domain.TaskRepository
+		presenter  CalendarFeedPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewCalendarFeedInteractor(
+	tokenRepo domain.CalendarTokenRepository,
+	taskRepo domain.TaskRepository,
+	presenter CalendarFeedPresenter,
+	t time.Duration,
+) CalendarFeedUseCase {
+	return calendarFeedInteractor{
+		tokenRepo:  tokenRepo,
+		taskRepo:   taskRepo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// フィードトークンを検証し、カレンダーに表示するタスクを返却する
+// タスクはまだアカウントごとに分かれていないため、全件を返却する
+func (t calendarFeedInteractor) Execute(ctx context.Context, token string) ([]CalendarTaskOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.CalendarFeed")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	if token == "" {
+		return nil, domain.ErrCalendarTokenInvalid
+	}
+
+	if err := t.tokenRepo.Verify(ctx, hashCalendarToken(token)); err != nil {
+		return nil, err
+	}
+
+	tasks, err := t.taskRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.presenter.Output(tasks), nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type mockCalendarTokenRepo struct {
+	hash *string
+}
+
+func (m mockCalendarTokenRepo) Save(_ context.Context, tokenHash string) error {
+	*m.hash = tokenHash
+	return nil
+}
+
+func (m mockCalendarTokenRepo) Verify(_ context.Context, tokenHash string) error {
+	if tokenHash != *m.hash {
+		return domain.ErrCalendarTokenInvalid
+	}
+	return nil
+}
+
+type mockTaskRepoCalendar struct {
+	domain.TaskRepository
+}
+
+func (m mockTaskRepoCalendar) FindAll(_ context.Context) ([]domain.Task, error) {
+	return []domain.Task{{ID: 1, Title: "Task_1"}}, nil
+}
+
+type mockCalendarFeedPresenter struct{}
+
+func (m mockCalendarFeedPresenter) Output(tasks []domain.Task) []CalendarTaskOutput {
+	var o []CalendarTaskOutput
+	for _, task := range tasks {
+		o = append(o, CalendarTaskOutput{ID: task.ID, Title: task.Title})
+	}
+	return o
+}
+
+func TestCalendarFeedInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tokens = mockCalendarTokenRepo{hash: new(string)}
+		issue  = NewIssueCalendarTokenInteractor(tokens, time.Second)
+	)
+
+	// トークンを発行し、再発行して以前のトークンを無効にする
+	previous, err := issue.Execute(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	issued, err := issue.Execute(domain.WithAccountID(context.TODO(), 7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// トークンそのものは保存しない
+	if *tokens.hash != hashCalendarToken(issued.Token) {
+		t.Errorf("token hash was not stored: %v", *tokens.hash)
+	}
+
+	var uc = NewCalendarFeedInteractor(tokens, mockTaskRepoCalendar{}, mockCalendarFeedPresenter{}, time.Second)
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedLen   int
+		expectedError error
+	}{
+		{name: "Calendar feed with issued token", token: issued.Token, expectedLen: 1},
+		{name: "Calendar feed with previous token", token: previous.Token, expectedError: domain.ErrCalendarTokenInvalid},
+		{name: "Calendar feed with unknown token", token: "unknown", expectedError: domain.ErrCalendarTokenInvalid},
+		{name: "Calendar feed without token", token: "", expectedError: domain.ErrCalendarTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := uc.Execute(context.TODO(), tt.token)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if len(result) != tt.expectedLen {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: %d tasks", tt.name, result, tt.expectedLen)
+			}
+		})
+	}
+}
@@ -13,7 +13,14 @@ type (
 	}
 
 	CreateTaskInput struct {
-		Title string `json:"title" validate:"required,gte=1,lte=15"`
+		Title      string      `json:"title" validate:"required,notblank,gte=1,lte=15"`
+		DueDate    *time.Time  `json:"due_date"`
+		Tags       []string    `json:"tags" validate:"lte=10,dive,slug,lte=30"`
+		Completed  bool        `json:"completed"`
+		Priority   int         `json:"priority" validate:"gte=0,lte=9"`
+		Recurrence string      `json:"recurrence" validate:"omitempty,rrule"`
+		Contexts   []string    `json:"contexts" validate:"lte=10,dive,slug,lte=30"`
+		Extensions []Extension `json:"extensions" validate:"lte=20,dive"`
 	}
 
 	CreateTaskPresenter interface {
@@ -21,13 +28,23 @@ type (
 	}
 
 	CreateTaskOutput struct {
-		Title     string `json:"title"`
-		CreatedAt string `json:"created_at"`
-		UpdatedAt string `json:"updated_at"`
+		ID          domain.TaskID `json:"id"`
+		Title       string        `json:"title"`
+		DueDate     string        `json:"due_date,omitempty"`
+		Tags        []string      `json:"tags"`
+		Completed   bool          `json:"completed"`
+		Priority    int           `json:"priority,omitempty"`
+		Recurrence  string        `json:"recurrence,omitempty"`
+		Contexts    []string      `json:"contexts,omitempty"`
+		Extensions  []Extension   `json:"extensions,omitempty"`
+		CompletedAt string        `json:"completed_at,omitempty"`
+		CreatedAt   string        `json:"created_at"`
+		UpdatedAt   string        `json:"updated_at"`
 	}
 
 	createTaskInteractor struct {
 		repo       domain.TaskRepository
+		outbox     domain.OutboxRepository
 		presenter  CreateTaskPresenter
 		ctxTimeout time.Duration
 	}
@@ -35,27 +52,45 @@ type (
 
 func NewCreateTaskInteractor(
 	repo domain.TaskRepository,
-	presenter  CreateTaskPresenter,
+	outbox domain.OutboxRepository,
+	presenter CreateTaskPresenter,
 	t time.Duration,
 ) CreateTaskUseCase {
 	return createTaskInteractor{
-		repo: repo,
-		presenter: presenter,
+		repo:       repo,
+		outbox:     outbox,
+		presenter:  presenter,
 		ctxTimeout: t,
 	}
 }
 
 func (t createTaskInteractor) Execute(ctx context.Context, input CreateTaskInput) (CreateTaskOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.CreateTask")
+	defer span.End()
+
 	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
 	defer cancel()
 
 	var task = domain.Task{
-		Title: input.Title,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		Title:      input.Title,
+		DueDate:    input.DueDate,
+		Tags:       input.Tags,
+		Completed:  input.Completed,
+		Priority:   input.Priority,
+		Recurrence: input.Recurrence,
+		Contexts:   input.Contexts,
+		Extensions: toDomainExtensions(input.Extensions),
 	}
 
-	task, err := t.repo.Create(ctx, task)
+	// タスクとイベントを同じトランザクションで書き込む
+	err := t.repo.WithTransaction(ctx, func(ctxTx context.Context) error {
+		var err error
+		if task, err = t.repo.Create(ctxTx, task); err != nil {
+			return err
+		}
+
+		return t.outbox.Append(ctxTx, domain.NewTaskEvent(domain.EventTaskCreated, task))
+	})
 	if err != nil {
 		return t.presenter.Output(domain.Task{}), err
 	}
@@ -21,6 +21,24 @@ func (m mockTaskRepoStore) Create(_ context.Context, _ domain.Task) (domain.Task
 	return m.result, m.err
 }
 
+func (m mockTaskRepoStore) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+// 書き込まれたイベントを記録する outbox
+type mockOutbox struct {
+	domain.OutboxRepository
+
+	events *[]domain.Event
+}
+
+func (m mockOutbox) Append(_ context.Context, events ...domain.Event) error {
+	if m.events != nil {
+		*m.events = append(*m.events, events...)
+	}
+	return nil
+}
+
 type mockCreateTaskPresenter struct {
 	result CreateTaskOutput
 }
@@ -98,7 +116,7 @@ func TestCreateTaskInteractor_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var uc = NewCreateTaskInteractor(tt.repository, tt.presenter, time.Second)
+			var uc = NewCreateTaskInteractor(tt.repository, mockOutbox{}, tt.presenter, time.Second)
 
 			result, err := uc.Execute(context.TODO(), tt.args.input)
 			if (err != nil) && (err.Error() != tt.expectedError) {
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	CreateWebhookUseCase interface {
+		Execute(context.Context, CreateWebhookInput) (WebhookOutput, error)
+	}
+
+	CreateWebhookInput struct {
+		URL    string   `json:"url" validate:"required,httpurl,publichost,lte=2048"`
+		Events []string `json:"events" validate:"required,gte=1,unique,dive,oneof=task.created task.updated task.deleted"`
+		// 省略した場合は生成する
+		Secret string `json:"secret" validate:"omitempty,gte=16,lte=128"`
+	}
+
+	WebhookPresenter interface {
+		Output(domain.WebhookSubscription) WebhookOutput
+	}
+
+	WebhookOutput struct {
+		ID     domain.WebhookID `json:"id"`
+		URL    string           `json:"url"`
+		Events []string         `json:"events"`
+		// 作成時にだけ返却する
+		Secret              string `json:"secret,omitempty"`
+		Active              bool   `json:"active"`
+		ConsecutiveFailures int    `json:"consecutive_failures"`
+		CreatedAt           string `json:"created_at"`
+		UpdatedAt           string `json:"updated_at"`
+	}
+
+	createWebhookInteractor struct {
+		repo       domain.WebhookRepository
+		presenter  WebhookPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewCreateWebhookInteractor(
+	repo domain.WebhookRepository,
+	presenter WebhookPresenter,
+	t time.Duration,
+) CreateWebhookUseCase {
+	return createWebhookInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// リクエストを行ったアカウントの購読を作成する
+func (t createWebhookInteractor) Execute(ctx context.Context, input CreateWebhookInput) (WebhookOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.CreateWebhook")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var accountID, _ = domain.AccountIDFromContext(ctx)
+
+	var secret = input.Secret
+	if secret == "" {
+		var b = make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return WebhookOutput{}, err
+		}
+		secret = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	subscription, err := t.repo.Create(ctx, domain.WebhookSubscription{
+		AccountID: accountID,
+		URL:       input.URL,
+		Events:    input.Events,
+		Secret:    secret,
+	})
+	if err != nil {
+		return WebhookOutput{}, err
+	}
+
+	var output = t.presenter.Output(subscription)
+	output.Secret = subscription.Secret
+
+	return output, nil
+}
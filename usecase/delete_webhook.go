@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	DeleteWebhookUseCase interface {
+		Execute(context.Context, domain.WebhookID) error
+	}
+
+	deleteWebhookInteractor struct {
+		repo       domain.WebhookRepository
+		ctxTimeout time.Duration
+	}
+)
+
+func NewDeleteWebhookInteractor(repo domain.WebhookRepository, t time.Duration) DeleteWebhookUseCase {
+	return deleteWebhookInteractor{
+		repo:       repo,
+		ctxTimeout: t,
+	}
+}
+
+// 購読を削除する (未配信の配信と履歴も削除される)
+func (t deleteWebhookInteractor) Execute(ctx context.Context, id domain.WebhookID) error {
+	ctx, span := domain.StartSpan(ctx, "usecase.DeleteWebhook")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var accountID, _ = domain.AccountIDFromContext(ctx)
+
+	return t.repo.Delete(ctx, accountID, id)
+}
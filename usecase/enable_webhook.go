@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	EnableWebhookUseCase interface {
+		Execute(context.Context, domain.WebhookID) (WebhookOutput, error)
+	}
+
+	enableWebhookInteractor struct {
+		repo       domain.WebhookRepository
+		presenter  WebhookPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewEnableWebhookInteractor(
+	repo domain.WebhookRepository,
+	presenter WebhookPresenter,
+	t time.Duration,
+) EnableWebhookUseCase {
+	return enableWebhookInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// 配信の失敗が続いて無効になった購読を有効に戻す
+// 無効な間に溜まった配信は有効に戻った後に送信される
+func (t enableWebhookInteractor) Execute(ctx context.Context, id domain.WebhookID) (WebhookOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.EnableWebhook")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var accountID, _ = domain.AccountIDFromContext(ctx)
+
+	subscription, err := t.repo.Enable(ctx, accountID, id)
+	if err != nil {
+		return WebhookOutput{}, err
+	}
+
+	return t.presenter.Output(subscription), nil
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	ExportTaskUseCase interface {
+		// タスクを1件ずつ関数に渡す
+		Execute(context.Context, func(UpdateTaskOutput) error) error
+	}
+
+	ExportTaskInteractor struct {
+		repo      domain.TaskRepository
+		presenter UpdateTaskPresenter
+	}
+)
+
+func NewExportTaskInteractor(
+	taskRepo domain.TaskRepository,
+	presenter UpdateTaskPresenter,
+) ExportTaskUseCase {
+	return ExportTaskInteractor{
+		repo:      taskRepo,
+		presenter: presenter,
+	}
+}
+
+// 全件を書き出すまで時間がかかるため、ctxTimeout ではなくリクエストのコンテキストで打ち切る
+func (t ExportTaskInteractor) Execute(ctx context.Context, fn func(UpdateTaskOutput) error) error {
+	ctx, span := domain.StartSpan(ctx, "usecase.ExportTask")
+	defer span.End()
+
+	return t.repo.FindEach(ctx, func(task domain.Task) error {
+		return fn(t.presenter.Output(task))
+	})
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type mockTaskRepoExport struct {
+	domain.TaskRepository
+
+	tasks []domain.Task
+}
+
+func (m mockTaskRepoExport) FindEach(_ context.Context, fn func(domain.Task) error) error {
+	for _, task := range m.tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestExportTaskInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		repo = mockTaskRepoExport{tasks: []domain.Task{{ID: 1, Title: "Task_1"}, {ID: 2, Title: "Task_2"}}}
+		uc   = NewExportTaskInteractor(repo, mockUpdateTaskPresenter{})
+	)
+
+	var titles []string
+	err := uc.Execute(context.TODO(), func(output UpdateTaskOutput) error {
+		titles = append(titles, output.Title)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := []string{"Task_1", "Task_2"}; !reflect.DeepEqual(titles, expected) {
+		t.Errorf("Result: '%v' | Expected: '%v'", titles, expected)
+	}
+
+	// 書き出し中のエラーで中断する
+	var errWrite = errors.New("write error")
+	var calls int
+	err = uc.Execute(context.TODO(), func(UpdateTaskOutput) error {
+		calls++
+		return errWrite
+	})
+	if !errors.Is(err, errWrite) || calls != 1 {
+		t.Errorf("Result: '%v' (%d calls) | ExpectedError: '%v'", err, calls, errWrite)
+	}
+}
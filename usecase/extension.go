@@ -0,0 +1,36 @@
+package usecase
+
+import "github.com/doglapping707/todo-api-go/domain"
+
+// タスクの拡張項目 (todo.txt の key:value)
+type Extension struct {
+	Key   string `json:"key" validate:"required,lte=30,nospace,excludes=:,ne=due,ne=pri"`
+	Value string `json:"value" validate:"required,lte=100,nospace"`
+}
+
+// ドメインの拡張項目を入出力の型に変換する
+func NewExtensions(extensions []domain.Extension) []Extension {
+	if extensions == nil {
+		return nil
+	}
+
+	var e = make([]Extension, 0, len(extensions))
+	for _, ext := range extensions {
+		e = append(e, Extension{Key: ext.Key, Value: ext.Value})
+	}
+
+	return e
+}
+
+func toDomainExtensions(extensions []Extension) []domain.Extension {
+	if extensions == nil {
+		return nil
+	}
+
+	var e = make([]domain.Extension, 0, len(extensions))
+	for _, ext := range extensions {
+		e = append(e, domain.Extension{Key: ext.Key, Value: ext.Value})
+	}
+
+	return e
+}
@@ -17,8 +17,15 @@ type (
 	}
 
 	FindAllTaskOutput struct {
-		ID    domain.TaskID  `json:"id"`
-		Title string         `json:"title"`
+		ID         domain.TaskID `json:"id"`
+		Title      string        `json:"title"`
+		DueDate    string        `json:"due_date,omitempty"`
+		Tags       []string      `json:"tags"`
+		Completed  bool          `json:"completed"`
+		Priority   int           `json:"priority,omitempty"`
+		Recurrence string        `json:"recurrence,omitempty"`
+		Contexts   []string      `json:"contexts,omitempty"`
+		Extensions []Extension   `json:"extensions,omitempty"`
 	}
 
 	findAllTaskInteractor struct {
@@ -41,6 +48,9 @@ func NewFindAllTaskInteractor(
 }
 
 func (t findAllTaskInteractor) Execute(ctx context.Context) ([]FindAllTaskOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.FindAllTask")
+	defer span.End()
+
 	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
 	defer cancel()
 
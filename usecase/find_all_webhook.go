@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	FindAllWebhookUseCase interface {
+		Execute(context.Context) ([]WebhookOutput, error)
+	}
+
+	findAllWebhookInteractor struct {
+		repo       domain.WebhookRepository
+		presenter  WebhookPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewFindAllWebhookInteractor(
+	repo domain.WebhookRepository,
+	presenter WebhookPresenter,
+	t time.Duration,
+) FindAllWebhookUseCase {
+	return findAllWebhookInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// リクエストを行ったアカウントの購読を返却する (秘密鍵は含めない)
+func (t findAllWebhookInteractor) Execute(ctx context.Context) ([]WebhookOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.FindAllWebhook")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var accountID, _ = domain.AccountIDFromContext(ctx)
+
+	subscriptions, err := t.repo.FindAll(ctx, accountID)
+	if err != nil {
+		return []WebhookOutput{}, err
+	}
+
+	var output = make([]WebhookOutput, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		output = append(output, t.presenter.Output(subscription))
+	}
+
+	return output, nil
+}
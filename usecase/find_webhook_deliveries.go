@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	FindWebhookDeliveriesUseCase interface {
+		Execute(context.Context, domain.WebhookID, int) ([]WebhookDeliveryOutput, error)
+	}
+
+	WebhookDeliveryPresenter interface {
+		Output(domain.WebhookDelivery) WebhookDeliveryOutput
+	}
+
+	WebhookDeliveryOutput struct {
+		ID             domain.WebhookDeliveryID `json:"id"`
+		EventID        string                   `json:"event_id"`
+		EventType      string                   `json:"event_type"`
+		Status         string                   `json:"status"`
+		Attempts       int                      `json:"attempts"`
+		NextAttemptAt  string                   `json:"next_attempt_at,omitempty"`
+		LastStatusCode int                      `json:"last_status_code,omitempty"`
+		LastError      string                   `json:"last_error,omitempty"`
+		History        []WebhookAttemptOutput   `json:"history"`
+		CreatedAt      string                   `json:"created_at"`
+		UpdatedAt      string                   `json:"updated_at"`
+	}
+
+	WebhookAttemptOutput struct {
+		Attempt     int    `json:"attempt"`
+		StatusCode  int    `json:"status_code,omitempty"`
+		Error       string `json:"error,omitempty"`
+		DurationMS  int64  `json:"duration_ms"`
+		AttemptedAt string `json:"attempted_at"`
+	}
+
+	findWebhookDeliveriesInteractor struct {
+		repo       domain.WebhookRepository
+		presenter  WebhookDeliveryPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewFindWebhookDeliveriesInteractor(
+	repo domain.WebhookRepository,
+	presenter WebhookDeliveryPresenter,
+	t time.Duration,
+) FindWebhookDeliveriesUseCase {
+	return findWebhookDeliveriesInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// 購読の配信と試行の履歴を新しい順に返却する
+func (t findWebhookDeliveriesInteractor) Execute(
+	ctx context.Context,
+	id domain.WebhookID,
+	limit int,
+) ([]WebhookDeliveryOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.FindWebhookDeliveries")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var accountID, _ = domain.AccountIDFromContext(ctx)
+
+	deliveries, err := t.repo.FindDeliveries(ctx, accountID, id, limit)
+	if err != nil {
+		return []WebhookDeliveryOutput{}, err
+	}
+
+	var output = make([]WebhookDeliveryOutput, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		output = append(output, t.presenter.Output(delivery))
+	}
+
+	return output, nil
+}
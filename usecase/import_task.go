@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	ImportTaskUseCase interface {
+		// 入力値のタスクをまとめて作成し、作成したタスクのIDを入力と同じ順番で返却する
+		Execute(context.Context, []UpdateTaskInput) ([]domain.TaskID, error)
+	}
+
+	ImportTaskInteractor struct {
+		repo       domain.TaskRepository
+		outbox     domain.OutboxRepository
+		ctxTimeout time.Duration
+	}
+)
+
+func NewImportTaskInteractor(
+	taskRepo domain.TaskRepository,
+	outbox domain.OutboxRepository,
+	t time.Duration,
+) ImportTaskUseCase {
+	return ImportTaskInteractor{
+		repo:       taskRepo,
+		outbox:     outbox,
+		ctxTimeout: t,
+	}
+}
+
+func (t ImportTaskInteractor) Execute(ctx context.Context, inputs []UpdateTaskInput) ([]domain.TaskID, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.ImportTask")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var tasks = make([]domain.Task, 0, len(inputs))
+	for _, input := range inputs {
+		tasks = append(tasks, input.toTask())
+	}
+
+	var created []domain.Task
+	err := t.repo.WithTransaction(ctx, func(ctxTx context.Context) error {
+		var err error
+		if created, err = t.repo.CreateMany(ctxTx, tasks); err != nil {
+			return err
+		}
+
+		var events = make([]domain.Event, 0, len(created))
+		for _, task := range created {
+			events = append(events, domain.NewTaskEvent(domain.EventTaskCreated, task))
+		}
+
+		return t.outbox.Append(ctxTx, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids = make([]domain.TaskID, 0, len(created))
+	for _, task := range created {
+		ids = append(ids, task.ID)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type mockTaskRepoImport struct {
+	domain.TaskRepository
+
+	err error
+}
+
+func (m mockTaskRepoImport) CreateMany(_ context.Context, tasks []domain.Task) ([]domain.Task, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var created = make([]domain.Task, len(tasks))
+	for i, task := range tasks {
+		task.ID = domain.TaskID(i + 10)
+		created[i] = task
+	}
+
+	return created, nil
+}
+
+func (m mockTaskRepoImport) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestImportTaskInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var inputs = []UpdateTaskInput{{Title: "Task_1"}, {Title: "Task_2", Completed: true}}
+
+	tests := []struct {
+		name          string
+		repository    domain.TaskRepository
+		expected      []domain.TaskID
+		expectedError interface{}
+	}{
+		{
+			name:       "Import tasks successful",
+			repository: mockTaskRepoImport{},
+			expected:   []domain.TaskID{10, 11},
+		},
+		{
+			name:          "Import tasks error",
+			repository:    mockTaskRepoImport{err: errors.New("error")},
+			expectedError: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events []domain.Event
+				uc     = NewImportTaskInteractor(tt.repository, mockOutbox{events: &events}, time.Second)
+			)
+
+			result, err := uc.Execute(context.TODO(), inputs)
+			if (err != nil) && (err.Error() != tt.expectedError) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expected)
+			}
+
+			// 作成したタスクごとにイベントを書き込む
+			if len(events) != len(tt.expected) {
+				t.Errorf("[TestCase '%s'] Events: '%v' | Expected: '%d'", tt.name, events, len(tt.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	IssueCalendarTokenUseCase interface {
+		Execute(context.Context) (IssueCalendarTokenOutput, error)
+	}
+
+	IssueCalendarTokenOutput struct {
+		Token string `json:"token"`
+	}
+
+	issueCalendarTokenInteractor struct {
+		repo       domain.CalendarTokenRepository
+		ctxTimeout time.Duration
+	}
+)
+
+func NewIssueCalendarTokenInteractor(
+	repo domain.CalendarTokenRepository,
+	t time.Duration,
+) IssueCalendarTokenUseCase {
+	return issueCalendarTokenInteractor{
+		repo:       repo,
+		ctxTimeout: t,
+	}
+}
+
+// API全体のフィードトークンを発行する
+// タスクはまだアカウントごとに分かれていないため、アカウントごとのトークンは発行しない
+// トークンは発行時にだけ返却し、以前のトークンは無効になる
+func (t issueCalendarTokenInteractor) Execute(ctx context.Context) (IssueCalendarTokenOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.IssueCalendarToken")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var b = make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return IssueCalendarTokenOutput{}, err
+	}
+	var token = base64.RawURLEncoding.EncodeToString(b)
+
+	if err := t.repo.Save(ctx, hashCalendarToken(token)); err != nil {
+		return IssueCalendarTokenOutput{}, err
+	}
+
+	return IssueCalendarTokenOutput{Token: token}, nil
+}
+
+// 保存・照合に使用するトークンのハッシュ
+func hashCalendarToken(token string) string {
+	var sum = sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
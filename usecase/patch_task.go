@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	PatchTaskUseCase interface {
+		Execute(context.Context, domain.TaskID, PatchTaskFunc) (UpdateTaskOutput, error)
+	}
+
+	// 現在のタスクを入力値として受け取り、パッチ適用後の入力値を返却する
+	PatchTaskFunc func(UpdateTaskInput) (UpdateTaskInput, error)
+
+	patchTaskInteractor struct {
+		repo       domain.TaskRepository
+		outbox     domain.OutboxRepository
+		presenter  UpdateTaskPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewPatchTaskInteractor(
+	repo domain.TaskRepository,
+	outbox domain.OutboxRepository,
+	presenter UpdateTaskPresenter,
+	t time.Duration,
+) PatchTaskUseCase {
+	return patchTaskInteractor{
+		repo:       repo,
+		outbox:     outbox,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+func (t patchTaskInteractor) Execute(ctx context.Context, taskID domain.TaskID, patch PatchTaskFunc) (UpdateTaskOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.PatchTask")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var updated domain.Task
+	err := t.repo.WithTransaction(ctx, func(ctxTx context.Context) error {
+		task, err := t.repo.FindByID(ctxTx, taskID)
+		if err != nil {
+			return err
+		}
+
+		input, err := patch(UpdateTaskInput{
+			Title:      task.Title,
+			DueDate:    task.DueDate,
+			Tags:       task.Tags,
+			Completed:  task.Completed,
+			Priority:   task.Priority,
+			Recurrence: task.Recurrence,
+			Contexts:   task.Contexts,
+			Extensions: NewExtensions(task.Extensions),
+		})
+		if err != nil {
+			return err
+		}
+
+		task.Title = input.Title
+		task.DueDate = input.DueDate
+		task.Tags = input.Tags
+		task.Completed = input.Completed
+		task.Priority = input.Priority
+		task.Recurrence = input.Recurrence
+		task.Contexts = input.Contexts
+		task.Extensions = toDomainExtensions(input.Extensions)
+
+		if updated, err = t.repo.Update(ctxTx, task, taskID); err != nil {
+			return err
+		}
+
+		return t.outbox.Append(ctxTx, domain.NewTaskEvent(domain.EventTaskUpdated, updated))
+	})
+	if err != nil {
+		return t.presenter.Output(domain.Task{}), err
+	}
+
+	return t.presenter.Output(updated), nil
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type mockTaskRepoPatch struct {
+	domain.TaskRepository
+
+	current   domain.Task
+	findErr   error
+	updateErr error
+
+	updated *domain.Task
+}
+
+func (m mockTaskRepoPatch) FindByID(_ context.Context, _ domain.TaskID) (domain.Task, error) {
+	return m.current, m.findErr
+}
+
+func (m mockTaskRepoPatch) Update(_ context.Context, task domain.Task, _ domain.TaskID) (domain.Task, error) {
+	*m.updated = task
+	return task, m.updateErr
+}
+
+func (m mockTaskRepoPatch) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestPatchTaskInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var rename = func(input UpdateTaskInput) (UpdateTaskInput, error) {
+		input.Title = input.Title + "_patched"
+		return input, nil
+	}
+
+	tests := []struct {
+		name          string
+		repository    mockTaskRepoPatch
+		patch         PatchTaskFunc
+		expectedTitle string
+		expectedError interface{}
+	}{
+		{
+			name: "Patch task successful",
+			repository: mockTaskRepoPatch{
+				current: domain.Task{ID: 1, Title: "Task_1"},
+			},
+			patch:         rename,
+			expectedTitle: "Task_1_patched",
+		},
+		{
+			name: "Patch task not found",
+			repository: mockTaskRepoPatch{
+				findErr: domain.ErrTaskNotFound,
+			},
+			patch:         rename,
+			expectedError: "task not found",
+		},
+		{
+			name: "Patch task patch error",
+			repository: mockTaskRepoPatch{
+				current: domain.Task{ID: 1, Title: "Task_1"},
+			},
+			patch: func(input UpdateTaskInput) (UpdateTaskInput, error) {
+				return input, errors.New("invalid patch")
+			},
+			expectedError: "invalid patch",
+		},
+		{
+			name: "Patch task update error",
+			repository: mockTaskRepoPatch{
+				current:   domain.Task{ID: 1, Title: "Task_1"},
+				updateErr: errors.New("error"),
+			},
+			patch:         rename,
+			expectedTitle: "Task_1_patched",
+			expectedError: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.repository.updated = &domain.Task{}
+
+			var uc = NewPatchTaskInteractor(tt.repository, mockOutbox{}, mockUpdateTaskPresenter{}, time.Second)
+
+			result, err := uc.Execute(context.TODO(), 1, tt.patch)
+			if (err != nil) && (err.Error() != tt.expectedError) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if (err == nil) && (tt.expectedError != nil) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if (err == nil) && (result.Title != tt.expectedTitle) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result.Title, tt.expectedTitle)
+			}
+
+			if tt.repository.updated.Title != tt.expectedTitle {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, tt.repository.updated.Title, tt.expectedTitle)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type (
+	PullTaskChangesUseCase interface {
+		// 指定した連番より後の変更を指定件数まで返却する
+		Execute(context.Context, domain.ChangeSeq, int) (PullTaskChangesOutput, error)
+	}
+
+	PullTaskChangesPresenter interface {
+		// 変更と次に指定する連番、続きがあるかから出力を生成する
+		Output([]domain.TaskChange, domain.ChangeSeq, bool) PullTaskChangesOutput
+	}
+
+	PullTaskChangesOutput struct {
+		// 作成・更新されたタスク (変更した順)
+		Changes []UpdateTaskOutput `json:"changes"`
+		// 削除されたタスク (削除した順)
+		Deleted []DeletedTaskOutput `json:"deleted"`
+		// 次の同期で since に指定するトークン
+		Token string `json:"token"`
+		// 件数の上限に達したため、すぐに続きを取得する必要があるか
+		HasMore bool `json:"has_more"`
+	}
+
+	DeletedTaskOutput struct {
+		ID        domain.TaskID `json:"id"`
+		DeletedAt string        `json:"deleted_at"`
+	}
+
+	pullTaskChangesInteractor struct {
+		repo       domain.TaskRepository
+		presenter  PullTaskChangesPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewPullTaskChangesInteractor(
+	repo domain.TaskRepository,
+	presenter PullTaskChangesPresenter,
+	t time.Duration,
+) PullTaskChangesUseCase {
+	return pullTaskChangesInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// 続きがあるか判定するため、上限より1件多く読み取る
+// トークンは返却した最後の変更の連番とし、変更がなければ指定された連番をそのまま返却する
+func (t pullTaskChangesInteractor) Execute(
+	ctx context.Context,
+	since domain.ChangeSeq,
+	limit int,
+) (PullTaskChangesOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.PullTaskChanges")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	changes, err := t.repo.FindChanges(ctx, since, limit+1)
+	if err != nil {
+		return PullTaskChangesOutput{}, err
+	}
+
+	var hasMore = len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	var token = since
+	if len(changes) > 0 {
+		token = changes[len(changes)-1].Seq
+	}
+
+	return t.presenter.Output(changes, token, hasMore), nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// 連番の順に並べた変更を返却するリポジトリ
+type mockTaskRepoChanges struct {
+	domain.TaskRepository
+
+	changes []domain.TaskChange
+}
+
+func (m mockTaskRepoChanges) FindChanges(_ context.Context, since domain.ChangeSeq, limit int) ([]domain.TaskChange, error) {
+	var changes []domain.TaskChange
+	for _, change := range m.changes {
+		if change.Seq > since && len(changes) < limit {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+type mockPullTaskChangesPresenter struct{}
+
+func (m mockPullTaskChangesPresenter) Output(changes []domain.TaskChange, token domain.ChangeSeq, hasMore bool) PullTaskChangesOutput {
+	var output = PullTaskChangesOutput{HasMore: hasMore}
+	output.Token = strconv.FormatUint(uint64(token), 10)
+	for _, change := range changes {
+		if change.Task != nil {
+			output.Changes = append(output.Changes, UpdateTaskOutput{ID: change.TaskID})
+		} else {
+			output.Deleted = append(output.Deleted, DeletedTaskOutput{ID: change.TaskID})
+		}
+	}
+
+	return output
+}
+
+func TestPullTaskChangesInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var repo = mockTaskRepoChanges{changes: []domain.TaskChange{
+		{Seq: 3, TaskID: 1, Task: &domain.Task{ID: 1}},
+		{Seq: 4, TaskID: 2},
+		{Seq: 7, TaskID: 3, Task: &domain.Task{ID: 3}},
+	}}
+
+	tests := []struct {
+		name            string
+		since           domain.ChangeSeq
+		limit           int
+		expectedChanges int
+		expectedDeleted int
+		expectedToken   domain.ChangeSeq
+		expectedHasMore bool
+	}{
+		{
+			name:            "Returns every change",
+			limit:           10,
+			expectedChanges: 2,
+			expectedDeleted: 1,
+			expectedToken:   7,
+		},
+		{
+			name:            "Stops at the limit",
+			limit:           2,
+			expectedChanges: 1,
+			expectedDeleted: 1,
+			expectedToken:   4,
+			expectedHasMore: true,
+		},
+		{
+			name:            "Returns the changes after the token",
+			since:           4,
+			limit:           1,
+			expectedChanges: 1,
+			expectedToken:   7,
+		},
+		{
+			name:          "Keeps the token when nothing changed",
+			since:         7,
+			limit:         10,
+			expectedToken: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var uc = NewPullTaskChangesInteractor(repo, mockPullTaskChangesPresenter{}, time.Second)
+
+			result, err := uc.Execute(context.TODO(), tt.since, tt.limit)
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] unexpected error: %v", tt.name, err)
+			}
+
+			if len(result.Changes) != tt.expectedChanges || len(result.Deleted) != tt.expectedDeleted {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%d changes, %d deleted'",
+					tt.name, result, tt.expectedChanges, tt.expectedDeleted)
+			}
+
+			if expected := strconv.FormatUint(uint64(tt.expectedToken), 10); result.Token != expected {
+				t.Errorf("[TestCase '%s'] Token: '%v' | Expected: '%v'", tt.name, result.Token, expected)
+			}
+
+			if result.HasMore != tt.expectedHasMore {
+				t.Errorf("[TestCase '%s'] HasMore: '%v' | Expected: '%v'", tt.name, result.HasMore, tt.expectedHasMore)
+			}
+		})
+	}
+}
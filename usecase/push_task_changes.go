@@ -0,0 +1,410 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// 同期で反映する変更の種類
+const (
+	SyncOpCreate = "create"
+	SyncOpUpdate = "update"
+	SyncOpDelete = "delete"
+)
+
+// 競合した項目で採用した変更
+const (
+	SyncWinnerClient = "client"
+	SyncWinnerServer = "server"
+)
+
+type (
+	PushTaskChangesUseCase interface {
+		Execute(context.Context, PushTaskChangesInput, ValidateTaskFunc) (PushTaskChangesOutput, error)
+	}
+
+	// 変更を反映した後のタスクの入力値を検証する
+	// 作成の場合は CreateTaskInput、更新の場合は UpdateTaskInput を渡す
+	ValidateTaskFunc func(interface{}) error
+
+	PushTaskChangesInput struct {
+		// クライアントが最後の同期で受け取ったトークン
+		// この連番より後にサーバーで変更された項目を上書きした場合は、競合として報告する
+		Since domain.ChangeSeq `json:"since,string"`
+		// クライアントで変更した順に並べた変更
+		Mutations []TaskMutationInput `json:"mutations" validate:"required,gte=1,lte=500,dive"`
+	}
+
+	TaskMutationInput struct {
+		Op     string        `json:"op" validate:"required,oneof=create update delete"`
+		TaskID domain.TaskID `json:"task_id" validate:"required_unless=Op create"`
+		// 作成したタスクをクライアントのデータと対応付けるための値 (そのまま返却する)
+		ClientRef string `json:"client_ref" validate:"lte=100"`
+		// クライアントで変更した日時 (サーバーの現在日時より後の場合は現在日時とみなす)
+		UpdatedAt time.Time `json:"updated_at" validate:"required"`
+		// 変更した項目 (TaskField*) と変更後の値
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+
+	PushTaskChangesOutput struct {
+		// 変更と同じ順番の結果
+		Results []TaskMutationOutput
+	}
+
+	TaskMutationOutput struct {
+		Op        string
+		ClientRef string
+		TaskID    domain.TaskID
+		// 反映した後のタスク (削除した場合は nil)
+		// 削除より後に変更されていたため削除しなかった場合は、現在のタスク
+		Task *UpdateTaskOutput
+		// 同じ項目がクライアントとサーバーの両方で変更されていた項目
+		Conflicts []FieldConflictOutput
+		// 変更を反映できなかった場合のエラー
+		Err error
+	}
+
+	FieldConflictOutput struct {
+		Field string `json:"field"`
+		// 採用した変更 (SyncWinner*)
+		Winner      string          `json:"winner"`
+		ClientValue json.RawMessage `json:"client_value"`
+		ServerValue json.RawMessage `json:"server_value"`
+	}
+
+	pushTaskChangesInteractor struct {
+		repo       domain.TaskRepository
+		outbox     domain.OutboxRepository
+		presenter  UpdateTaskPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewPushTaskChangesInteractor(
+	repo domain.TaskRepository,
+	outbox domain.OutboxRepository,
+	presenter UpdateTaskPresenter,
+	t time.Duration,
+) PushTaskChangesUseCase {
+	return pushTaskChangesInteractor{
+		repo:       repo,
+		outbox:     outbox,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// クライアントの変更を1つのトランザクションで順に反映する
+// 失敗した変更だけをロールバックし、結果をエラーとして返却する (残りの変更は反映する)
+func (t pushTaskChangesInteractor) Execute(
+	ctx context.Context,
+	input PushTaskChangesInput,
+	validate ValidateTaskFunc,
+) (PushTaskChangesOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.PushTaskChanges")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	var (
+		results = make([]TaskMutationOutput, len(input.Mutations))
+		now     = time.Now()
+	)
+	err := t.repo.WithTransaction(ctx, func(ctxTx context.Context) error {
+		for i, m := range input.Mutations {
+			results[i] = TaskMutationOutput{Op: m.Op, ClientRef: m.ClientRef, TaskID: m.TaskID}
+
+			// 未来の日時を送ると、その日時まで他の変更で上書きされず削除もされない項目になるため、現在日時に切り詰める
+			if m.UpdatedAt.After(now) {
+				m.UpdatedAt = now
+			}
+
+			var (
+				task      *domain.Task
+				conflicts []FieldConflictOutput
+			)
+
+			// 失敗してもトランザクション全体が中断されないよう、変更ごとにセーブポイントを作成する
+			err := t.repo.WithTransaction(ctxTx, func(ctxSp context.Context) error {
+				var err error
+				task, conflicts, err = t.apply(ctxSp, input.Since, m, validate)
+				return err
+			})
+
+			results[i].Conflicts = conflicts
+			results[i].Err = err
+			if task != nil {
+				var output = t.presenter.Output(*task)
+				results[i].TaskID = task.ID
+				results[i].Task = &output
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return PushTaskChangesOutput{}, err
+	}
+
+	return PushTaskChangesOutput{Results: results}, nil
+}
+
+func (t pushTaskChangesInteractor) apply(
+	ctx context.Context,
+	since domain.ChangeSeq,
+	m TaskMutationInput,
+	validate ValidateTaskFunc,
+) (*domain.Task, []FieldConflictOutput, error) {
+	switch m.Op {
+	case SyncOpCreate:
+		return t.create(ctx, m, validate)
+	case SyncOpUpdate:
+		return t.update(ctx, since, m, validate)
+	case SyncOpDelete:
+		task, err := t.delete(ctx, m)
+		return task, nil, err
+	}
+
+	return nil, nil, domain.NewError(domain.KindValidation, "unknown sync operation: "+m.Op)
+}
+
+func (t pushTaskChangesInteractor) create(
+	ctx context.Context,
+	m TaskMutationInput,
+	validate ValidateTaskFunc,
+) (*domain.Task, []FieldConflictOutput, error) {
+	merged, err := mergeTaskFields(domain.Task{}, 0, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := validate(CreateTaskInput(merged.input)); err != nil {
+		return nil, nil, err
+	}
+
+	var task = merged.input.toTask()
+	task.Versions = merged.versions
+
+	if task, err = t.repo.Create(ctx, task); err != nil {
+		return nil, nil, err
+	}
+
+	if err := t.outbox.Append(ctx, domain.NewTaskEvent(domain.EventTaskCreated, task)); err != nil {
+		return nil, nil, err
+	}
+
+	return &task, nil, nil
+}
+
+// 項目ごとに後から変更した方を採用する (mergeTaskFields を参照)
+// クライアントの変更をすべて採用しなかった場合は書き込まない
+func (t pushTaskChangesInteractor) update(
+	ctx context.Context,
+	since domain.ChangeSeq,
+	m TaskMutationInput,
+	validate ValidateTaskFunc,
+) (*domain.Task, []FieldConflictOutput, error) {
+	current, err := t.repo.FindByID(ctx, m.TaskID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged, err := mergeTaskFields(current, since, m)
+	if err != nil {
+		return nil, merged.conflicts, err
+	}
+
+	if !merged.changed {
+		return &current, merged.conflicts, nil
+	}
+
+	if err := validate(merged.input); err != nil {
+		return nil, merged.conflicts, err
+	}
+
+	var task = merged.input.toTask()
+	task.Versions = merged.versions
+
+	if task, err = t.repo.Update(ctx, task, m.TaskID); err != nil {
+		return nil, merged.conflicts, err
+	}
+
+	if err := t.outbox.Append(ctx, domain.NewTaskEvent(domain.EventTaskUpdated, task)); err != nil {
+		return nil, merged.conflicts, err
+	}
+
+	return &task, merged.conflicts, nil
+}
+
+// 既に削除されているタスクの削除は成功とみなす
+// 削除した日時より後にいずれかの項目が変更されていた場合は削除せず、現在のタスクを返却する
+func (t pushTaskChangesInteractor) delete(ctx context.Context, m TaskMutationInput) (*domain.Task, error) {
+	current, err := t.repo.FindByID(ctx, m.TaskID)
+	if err != nil {
+		if err == domain.ErrTaskNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, version := range current.Versions {
+		if version.UpdatedAt.After(m.UpdatedAt) {
+			return &current, domain.ErrTaskModifiedAfterDelete
+		}
+	}
+
+	if err := t.repo.Delete(ctx, m.TaskID); err != nil {
+		return nil, err
+	}
+
+	return nil, t.outbox.Append(ctx, domain.NewTaskDeletedEvent(m.TaskID))
+}
+
+type mergedTask struct {
+	input     UpdateTaskInput
+	versions  map[string]domain.FieldVersion
+	conflicts []FieldConflictOutput
+	// クライアントの変更を1つ以上採用したか
+	changed bool
+}
+
+// クライアントが変更した項目を現在のタスクに反映する
+//   - 値が同じ項目は何もしない
+//   - サーバーでクライアントより後に変更されていた項目は、サーバーの値を残して競合として報告する
+//   - それ以外はクライアントの値を採用し、変更日時をクライアントで変更した日時にする
+//     since より後にサーバーで変更されていた項目を上書きした場合は、競合として報告する
+func mergeTaskFields(current domain.Task, since domain.ChangeSeq, m TaskMutationInput) (mergedTask, error) {
+	var merged = mergedTask{
+		versions: make(map[string]domain.FieldVersion, len(domain.TaskFields)),
+	}
+	for field, version := range current.Versions {
+		merged.versions[field] = version
+	}
+
+	doc, err := taskFieldValues(UpdateTaskInput{
+		Title:      current.Title,
+		DueDate:    current.DueDate,
+		Tags:       current.Tags,
+		Completed:  current.Completed,
+		Priority:   current.Priority,
+		Recurrence: current.Recurrence,
+		Contexts:   current.Contexts,
+		Extensions: NewExtensions(current.Extensions),
+	})
+	if err != nil {
+		return merged, err
+	}
+
+	// 結果の順番を一定にするため、項目名の順に反映する
+	var fields = make([]string, 0, len(m.Fields))
+	for field := range m.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		serverValue, ok := doc[field]
+		if !ok {
+			return merged, domain.NewError(domain.KindValidation, "unknown task field: "+field)
+		}
+
+		clientValue, err := normalizeTaskField(field, m.Fields[field])
+		if err != nil {
+			return merged, err
+		}
+
+		if string(clientValue) == string(serverValue) {
+			continue
+		}
+
+		var version, versioned = current.Versions[field]
+		if versioned && version.UpdatedAt.After(m.UpdatedAt) {
+			merged.conflicts = append(merged.conflicts, FieldConflictOutput{
+				Field:       field,
+				Winner:      SyncWinnerServer,
+				ClientValue: clientValue,
+				ServerValue: serverValue,
+			})
+			continue
+		}
+
+		if since > 0 && versioned && version.Seq > since {
+			merged.conflicts = append(merged.conflicts, FieldConflictOutput{
+				Field:       field,
+				Winner:      SyncWinnerClient,
+				ClientValue: clientValue,
+				ServerValue: serverValue,
+			})
+		}
+
+		doc[field] = clientValue
+		merged.versions[field] = domain.FieldVersion{UpdatedAt: m.UpdatedAt}
+		merged.changed = true
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return merged, err
+	}
+
+	if err := json.Unmarshal(b, &merged.input); err != nil {
+		return merged, err
+	}
+
+	return merged, nil
+}
+
+// 入力値を項目 (TaskField*) ごとの JSON に変換する
+// 表現の違いで値が異なると判定しないよう、空の配列と null を揃え、日時は UTC にする
+func taskFieldValues(input UpdateTaskInput) (map[string]json.RawMessage, error) {
+	if input.Tags == nil {
+		input.Tags = []string{}
+	}
+	if input.Contexts == nil {
+		input.Contexts = []string{}
+	}
+	if input.Extensions == nil {
+		input.Extensions = []Extension{}
+	}
+	if input.DueDate != nil {
+		var dueDate = input.DueDate.UTC()
+		input.DueDate = &dueDate
+	}
+
+	b, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// クライアントが送った項目の値を taskFieldValues と同じ表現にする
+func normalizeTaskField(field string, value json.RawMessage) (json.RawMessage, error) {
+	b, err := json.Marshal(map[string]json.RawMessage{field: value})
+	if err != nil {
+		return nil, domain.NewError(domain.KindValidation, "invalid task field: "+field)
+	}
+
+	var input UpdateTaskInput
+	if err := json.Unmarshal(b, &input); err != nil {
+		return nil, domain.NewError(domain.KindValidation, "invalid task field: "+field)
+	}
+
+	doc, err := taskFieldValues(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc[field], nil
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+func TestPushTaskChangesInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var at = func(hour int) time.Time {
+		return time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	// タイトルは 10時 (連番 5)、優先度は 12時 (連番 8) にサーバーで変更されている
+	var task = domain.Task{
+		ID:       1,
+		Title:    "Task_1",
+		Priority: 1,
+		Versions: map[string]domain.FieldVersion{
+			domain.TaskFieldTitle:    {UpdatedAt: at(10), Seq: 5},
+			domain.TaskFieldPriority: {UpdatedAt: at(12), Seq: 8},
+		},
+	}
+
+	var fields = func(doc string) map[string]json.RawMessage {
+		var f map[string]json.RawMessage
+		_ = json.Unmarshal([]byte(doc), &f)
+		return f
+	}
+
+	var validate = func(input interface{}) error {
+		var title string
+		switch task := input.(type) {
+		case CreateTaskInput:
+			title = task.Title
+		case UpdateTaskInput:
+			title = task.Title
+		}
+
+		if title == "" {
+			return errors.New("title is required")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name              string
+		since             domain.ChangeSeq
+		mutation          TaskMutationInput
+		expectedErr       error
+		expectedTitle     string
+		expectedPriority  int
+		expectedConflicts []FieldConflictOutput
+		expectedEvents    []string
+		expectedTasks     int
+	}{
+		{
+			name:             "Client change after the last sync is applied",
+			since:            6,
+			mutation:         TaskMutationInput{Op: SyncOpUpdate, TaskID: 1, UpdatedAt: at(11), Fields: fields(`{"title":"Task_client"}`)},
+			expectedTitle:    "Task_client",
+			expectedPriority: 1,
+			expectedEvents:   []string{domain.EventTaskUpdated},
+			expectedTasks:    1,
+		},
+		{
+			name:             "Client overrides a newer server change",
+			since:            4,
+			mutation:         TaskMutationInput{Op: SyncOpUpdate, TaskID: 1, UpdatedAt: at(11), Fields: fields(`{"title":"Task_client"}`)},
+			expectedTitle:    "Task_client",
+			expectedPriority: 1,
+			expectedConflicts: []FieldConflictOutput{
+				{Field: "title", Winner: SyncWinnerClient, ClientValue: json.RawMessage(`"Task_client"`), ServerValue: json.RawMessage(`"Task_1"`)},
+			},
+			expectedEvents: []string{domain.EventTaskUpdated},
+			expectedTasks:  1,
+		},
+		{
+			name:             "Server change after the client change wins",
+			mutation:         TaskMutationInput{Op: SyncOpUpdate, TaskID: 1, UpdatedAt: at(11), Fields: fields(`{"priority":3,"title":"Task_client"}`)},
+			expectedTitle:    "Task_client",
+			expectedPriority: 1,
+			expectedConflicts: []FieldConflictOutput{
+				{Field: "priority", Winner: SyncWinnerServer, ClientValue: json.RawMessage(`3`), ServerValue: json.RawMessage(`1`)},
+			},
+			expectedEvents: []string{domain.EventTaskUpdated},
+			expectedTasks:  1,
+		},
+		{
+			name:             "Unchanged fields are not written",
+			mutation:         TaskMutationInput{Op: SyncOpUpdate, TaskID: 1, UpdatedAt: at(13), Fields: fields(`{"title":"Task_1","tags":[]}`)},
+			expectedTitle:    "Task_1",
+			expectedPriority: 1,
+			expectedEvents:   []string{},
+			expectedTasks:    1,
+		},
+		{
+			name:             "Unknown field",
+			mutation:         TaskMutationInput{Op: SyncOpUpdate, TaskID: 1, UpdatedAt: at(13), Fields: fields(`{"owner":"someone"}`)},
+			expectedErr:      domain.NewError(domain.KindValidation, "unknown task field: owner"),
+			expectedTitle:    "Task_1",
+			expectedPriority: 1,
+			expectedEvents:   []string{},
+			expectedTasks:    1,
+		},
+		{
+			name:             "Invalid task after the change",
+			mutation:         TaskMutationInput{Op: SyncOpUpdate, TaskID: 1, UpdatedAt: at(13), Fields: fields(`{"title":""}`)},
+			expectedErr:      errors.New("title is required"),
+			expectedTitle:    "Task_1",
+			expectedPriority: 1,
+			expectedEvents:   []string{},
+			expectedTasks:    1,
+		},
+		{
+			name:             "Task modified after the delete is kept",
+			mutation:         TaskMutationInput{Op: SyncOpDelete, TaskID: 1, UpdatedAt: at(11)},
+			expectedErr:      domain.ErrTaskModifiedAfterDelete,
+			expectedTitle:    "Task_1",
+			expectedPriority: 1,
+			expectedEvents:   []string{},
+			expectedTasks:    1,
+		},
+		{
+			name:           "Delete",
+			mutation:       TaskMutationInput{Op: SyncOpDelete, TaskID: 1, UpdatedAt: at(13)},
+			expectedEvents: []string{domain.EventTaskDeleted},
+			expectedTasks:  0,
+		},
+		{
+			name:             "Deleting a deleted task succeeds",
+			mutation:         TaskMutationInput{Op: SyncOpDelete, TaskID: 99, UpdatedAt: at(13)},
+			expectedTitle:    "Task_1",
+			expectedPriority: 1,
+			expectedEvents:   []string{},
+			expectedTasks:    1,
+		},
+		{
+			name:             "Create",
+			mutation:         TaskMutationInput{Op: SyncOpCreate, ClientRef: "local-1", UpdatedAt: at(13), Fields: fields(`{"title":"Task_new"}`)},
+			expectedTitle:    "Task_1",
+			expectedPriority: 1,
+			expectedEvents:   []string{domain.EventTaskCreated},
+			expectedTasks:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				repo = newMockTaskRepoBatch(task)
+				uc   = NewPushTaskChangesInteractor(repo, mockOutboxBatch{repo: repo}, mockUpdateTaskPresenter{}, time.Second)
+			)
+
+			result, err := uc.Execute(context.TODO(), PushTaskChangesInput{
+				Since:     tt.since,
+				Mutations: []TaskMutationInput{tt.mutation},
+			}, validate)
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] unexpected error: %v", tt.name, err)
+			}
+
+			var res = result.Results[0]
+			if !reflect.DeepEqual(res.Err, tt.expectedErr) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, res.Err, tt.expectedErr)
+			}
+
+			if res.ClientRef != tt.mutation.ClientRef {
+				t.Errorf("[TestCase '%s'] ClientRef: '%v' | Expected: '%v'", tt.name, res.ClientRef, tt.mutation.ClientRef)
+			}
+
+			if !reflect.DeepEqual(res.Conflicts, tt.expectedConflicts) {
+				t.Errorf("[TestCase '%s'] Conflicts: '%s' | Expected: '%s'", tt.name, res.Conflicts, tt.expectedConflicts)
+			}
+
+			if len(repo.tasks) != tt.expectedTasks {
+				t.Errorf("[TestCase '%s'] Tasks: '%v' | Expected: '%v'", tt.name, len(repo.tasks), tt.expectedTasks)
+			}
+
+			if current := repo.tasks[1]; current.Title != tt.expectedTitle || current.Priority != tt.expectedPriority {
+				t.Errorf("[TestCase '%s'] Task: '%v' | Expected: '%v %v'", tt.name, current, tt.expectedTitle, tt.expectedPriority)
+			}
+
+			var events = make([]string, 0, len(repo.events))
+			for _, event := range repo.events {
+				events = append(events, event.Type)
+			}
+			if !reflect.DeepEqual(events, tt.expectedEvents) {
+				t.Errorf("[TestCase '%s'] Events: '%v' | Expected: '%v'", tt.name, events, tt.expectedEvents)
+			}
+		})
+	}
+}
+
+// 採用した変更の日時を項目の変更日時として書き込む
+func TestPushTaskChangesInteractor_Execute_Versions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		changedAt = time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+		repo      = newMockTaskRepoBatch(domain.Task{ID: 1, Title: "Task_1", Priority: 1})
+		uc        = NewPushTaskChangesInteractor(repo, mockOutboxBatch{repo: repo}, mockUpdateTaskPresenter{}, time.Second)
+	)
+
+	_, err := uc.Execute(context.TODO(), PushTaskChangesInput{
+		Mutations: []TaskMutationInput{{
+			Op:        SyncOpUpdate,
+			TaskID:    1,
+			UpdatedAt: changedAt,
+			Fields:    map[string]json.RawMessage{"title": json.RawMessage(`"Task_client"`), "priority": json.RawMessage(`1`)},
+		}},
+	}, func(interface{}) error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var expected = map[string]domain.FieldVersion{domain.TaskFieldTitle: {UpdatedAt: changedAt}}
+	if result := repo.tasks[1].Versions; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Result: '%v' | Expected: '%v'", result, expected)
+	}
+}
+
+// 未来の変更日時は現在日時として書き込み、後の変更で上書き・削除できるようにする
+func TestPushTaskChangesInteractor_Execute_FutureUpdatedAt(t *testing.T) {
+	t.Parallel()
+
+	var (
+		repo = newMockTaskRepoBatch(domain.Task{ID: 1, Title: "Task_1", Priority: 1})
+		uc   = NewPushTaskChangesInteractor(repo, mockOutboxBatch{repo: repo}, mockUpdateTaskPresenter{}, time.Second)
+	)
+
+	result, err := uc.Execute(context.TODO(), PushTaskChangesInput{
+		Mutations: []TaskMutationInput{{
+			Op:        SyncOpUpdate,
+			TaskID:    1,
+			UpdatedAt: time.Now().AddDate(100, 0, 0),
+			Fields:    map[string]json.RawMessage{"title": json.RawMessage(`"Task_future"`)},
+		}},
+	}, func(interface{}) error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Results[0].Err != nil {
+		t.Fatalf("unexpected error: %v", result.Results[0].Err)
+	}
+
+	if version := repo.tasks[1].Versions[domain.TaskFieldTitle]; version.UpdatedAt.After(time.Now()) {
+		t.Errorf("Result: '%v' | Expected: not after now", version.UpdatedAt)
+	}
+
+	result, err = uc.Execute(context.TODO(), PushTaskChangesInput{
+		Mutations: []TaskMutationInput{{Op: SyncOpDelete, TaskID: 1, UpdatedAt: time.Now().Add(time.Second)}},
+	}, func(interface{}) error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Results[0].Err != nil || len(repo.tasks) != 0 {
+		t.Errorf("Result: '%v' %d tasks | Expected: '<nil>' 0 tasks", result.Results[0].Err, len(repo.tasks))
+	}
+}
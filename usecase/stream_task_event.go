@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// 再開位置のイベントが保持されていない場合に送る種類
+// 受け取ったクライアントはタスクを取得し直す
+const EventStreamReset = "stream.reset"
+
+const (
+	// 通知がなくても新しいイベントを確認する間隔 (通知を取りこぼした場合に備える)
+	defaultStreamPollInterval = 30 * time.Second
+	// 1回に読み取るイベントの件数
+	defaultStreamBatchSize = 100
+)
+
+type (
+	StreamTaskEventUseCase interface {
+		// 開始位置より後のイベントを書き込んだ順に関数に渡す
+		// コンテキストが終了するか、関数がエラーを返すまで戻らない
+		Execute(context.Context, StreamTaskEventInput, func(TaskEventOutput) error) error
+	}
+
+	StreamTaskEventInput struct {
+		// 最後に受け取ったイベントのID (nil の場合は開始した後に起きたイベントだけを渡す)
+		LastEventID *domain.EventID
+	}
+
+	TaskEventPresenter interface {
+		Output(domain.Event) TaskEventOutput
+	}
+
+	TaskEventOutput struct {
+		ID        string      `json:"id"`
+		Type      string      `json:"type"`
+		CreatedAt string      `json:"created_at"`
+		Data      interface{} `json:"data"`
+	}
+
+	streamTaskEventInteractor struct {
+		repo         domain.OutboxRepository
+		notifier     domain.EventNotifier
+		presenter    TaskEventPresenter
+		ctxTimeout   time.Duration
+		pollInterval time.Duration
+		batchSize    int
+	}
+)
+
+func NewStreamTaskEventInteractor(
+	repo domain.OutboxRepository,
+	notifier domain.EventNotifier,
+	presenter TaskEventPresenter,
+	t time.Duration,
+) StreamTaskEventUseCase {
+	return streamTaskEventInteractor{
+		repo:         repo,
+		notifier:     notifier,
+		presenter:    presenter,
+		ctxTimeout:   t,
+		pollInterval: defaultStreamPollInterval,
+		batchSize:    defaultStreamBatchSize,
+	}
+}
+
+// リクエストを行ったアカウントのイベントだけを渡す
+// イベントの書き込みが通知されるたびに、まだ渡していないイベントを読み取る
+// 接続している間続くため、ctxTimeout はクエリごとに適用する
+func (t streamTaskEventInteractor) Execute(
+	ctx context.Context,
+	input StreamTaskEventInput,
+	fn func(TaskEventOutput) error,
+) error {
+	ctx, span := domain.StartSpan(ctx, "usecase.StreamTaskEvent")
+	defer span.End()
+
+	var accountID, _ = domain.AccountIDFromContext(ctx)
+
+	// 開始位置を決める間に書き込まれたイベントを取りこぼさないよう、先に購読する
+	notified, unsubscribe := t.notifier.Subscribe(accountID)
+	defer unsubscribe()
+
+	after, err := t.start(ctx, input, fn)
+	if err != nil {
+		return t.ignoreDone(ctx, err)
+	}
+
+	var timer = time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notified:
+		case <-timer.C:
+		}
+
+		events, err := t.findAfter(ctx, accountID, after)
+		if err != nil {
+			return t.ignoreDone(ctx, err)
+		}
+
+		for _, event := range events {
+			if err := fn(t.presenter.Output(event)); err != nil {
+				return err
+			}
+			after = event.ID
+		}
+
+		// 読み取った件数が上限に達した場合は、残りがあるとみなしてすぐに続ける
+		if len(events) == t.batchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(t.pollInterval)
+		}
+	}
+}
+
+// 読み取りを開始するIDを返却する
+// 指定されたIDより後のイベントが既に削除されている場合や、存在しないIDが指定された場合は、
+// 取りこぼしがあることをリセットのイベントで伝えて最新のイベントの後から開始する
+func (t streamTaskEventInteractor) start(
+	ctx context.Context,
+	input StreamTaskEventInput,
+	fn func(TaskEventOutput) error,
+) (domain.EventID, error) {
+	ctxQuery, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	first, last, err := t.repo.Bounds(ctxQuery)
+	if err != nil {
+		return 0, err
+	}
+
+	if input.LastEventID == nil {
+		return last, nil
+	}
+
+	var lastEventID = *input.LastEventID
+	if lastEventID <= last && first <= lastEventID+1 {
+		return lastEventID, nil
+	}
+
+	if err := fn(TaskEventOutput{
+		ID:        strconv.FormatUint(uint64(last), 10),
+		Type:      EventStreamReset,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return 0, err
+	}
+
+	return last, nil
+}
+
+func (t streamTaskEventInteractor) findAfter(
+	ctx context.Context,
+	accountID domain.AccountID,
+	after domain.EventID,
+) ([]domain.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
+	defer cancel()
+
+	return t.repo.FindAfter(ctx, accountID, after, t.batchSize)
+}
+
+// 接続が切れたことによるエラーは正常な終了とみなす
+func (t streamTaskEventInteractor) ignoreDone(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
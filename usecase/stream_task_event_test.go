@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+// 保持しているイベントを返却する outbox
+type mockOutboxStream struct {
+	domain.OutboxRepository
+
+	first, last domain.EventID
+	events      []domain.Event
+}
+
+func (m mockOutboxStream) Bounds(_ context.Context) (domain.EventID, domain.EventID, error) {
+	return m.first, m.last, nil
+}
+
+func (m mockOutboxStream) FindAfter(_ context.Context, accountID domain.AccountID, after domain.EventID, limit int) ([]domain.Event, error) {
+	var events []domain.Event
+	for _, event := range m.events {
+		if event.AccountID == accountID && event.ID > after && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// 購読しても通知しない notifier
+type mockEventNotifier struct{}
+
+func (m mockEventNotifier) Subscribe(_ domain.AccountID) (<-chan struct{}, func()) {
+	return make(chan struct{}), func() {}
+}
+
+type mockTaskEventPresenter struct{}
+
+func (m mockTaskEventPresenter) Output(event domain.Event) TaskEventOutput {
+	return TaskEventOutput{
+		ID:   strconv.FormatUint(uint64(event.ID), 10),
+		Type: event.Type,
+	}
+}
+
+func TestStreamTaskEventInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var events = []domain.Event{
+		{ID: 5, Type: domain.EventTaskCreated, AccountID: 1},
+		{ID: 6, Type: domain.EventTaskUpdated, AccountID: 2},
+		{ID: 7, Type: domain.EventTaskUpdated, AccountID: 1},
+		// 開始した後に書き込まれたイベント
+		{ID: 8, Type: domain.EventTaskDeleted, AccountID: 1},
+	}
+
+	var lastEventID = func(id domain.EventID) *domain.EventID { return &id }
+
+	tests := []struct {
+		name     string
+		input    StreamTaskEventInput
+		expected []string
+	}{
+		{
+			name:     "Starts after the latest event",
+			expected: []string{"8 task.deleted"},
+		},
+		{
+			name:     "Resumes after the last event id",
+			input:    StreamTaskEventInput{LastEventID: lastEventID(5)},
+			expected: []string{"7 task.updated", "8 task.deleted"},
+		},
+		{
+			name:     "Resumes from the oldest retained event",
+			input:    StreamTaskEventInput{LastEventID: lastEventID(4)},
+			expected: []string{"5 task.created", "7 task.updated", "8 task.deleted"},
+		},
+		{
+			name:     "Resets when events were pruned",
+			input:    StreamTaskEventInput{LastEventID: lastEventID(2)},
+			expected: []string{"7 stream.reset", "8 task.deleted"},
+		},
+		{
+			name:     "Resets on an unknown event id",
+			input:    StreamTaskEventInput{LastEventID: lastEventID(100)},
+			expected: []string{"7 stream.reset", "8 task.deleted"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				repo = mockOutboxStream{first: 5, last: 7, events: events}
+				uc   = NewStreamTaskEventInteractor(repo, mockEventNotifier{}, mockTaskEventPresenter{}, time.Second)
+			)
+
+			ctx, cancel := context.WithTimeout(domain.WithAccountID(context.Background(), 1), 5*time.Second)
+			defer cancel()
+
+			var result []string
+			err := uc.Execute(ctx, tt.input, func(output TaskEventOutput) error {
+				result = append(result, output.ID+" "+output.Type)
+				if len(result) == len(tt.expected) {
+					cancel()
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] unexpected error: %v", tt.name, err)
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+// 通知を受け取るたびにイベントを追加する outbox
+type mockOutboxNotified struct {
+	mockOutboxStream
+
+	mu       sync.Mutex
+	appended []domain.Event
+}
+
+func (m *mockOutboxNotified) FindAfter(ctx context.Context, accountID domain.AccountID, after domain.EventID, limit int) ([]domain.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, m.appended...)
+	m.appended = nil
+
+	return m.mockOutboxStream.FindAfter(ctx, accountID, after, limit)
+}
+
+type mockEventNotifierChan struct {
+	ch chan struct{}
+}
+
+func (m mockEventNotifierChan) Subscribe(_ domain.AccountID) (<-chan struct{}, func()) {
+	return m.ch, func() {}
+}
+
+func TestStreamTaskEventInteractor_Execute_Notified(t *testing.T) {
+	t.Parallel()
+
+	var (
+		repo     = &mockOutboxNotified{mockOutboxStream: mockOutboxStream{first: 1, last: 1}}
+		notifier = mockEventNotifierChan{ch: make(chan struct{}, 1)}
+		uc       = NewStreamTaskEventInteractor(repo, notifier, mockTaskEventPresenter{}, time.Second)
+	)
+
+	ctx, cancel := context.WithTimeout(domain.WithAccountID(context.Background(), 1), 5*time.Second)
+	defer cancel()
+
+	// 最初の読み取りの後にイベントを書き込み、通知する
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		repo.mu.Lock()
+		repo.appended = []domain.Event{{ID: 2, Type: domain.EventTaskCreated, AccountID: 1}}
+		repo.mu.Unlock()
+
+		notifier.ch <- struct{}{}
+	}()
+
+	var result []string
+	err := uc.Execute(ctx, StreamTaskEventInput{}, func(output TaskEventOutput) error {
+		result = append(result, output.ID+" "+output.Type)
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// ポーリングの間隔 (30秒) を待たずに通知で読み取ること
+	if expected := []string{"2 task.created"}; !reflect.DeepEqual(result, expected) || ctx.Err() == context.DeadlineExceeded {
+		t.Errorf("Result: '%v' (%v) | Expected: '%v'", result, ctx.Err(), expected)
+	}
+}
@@ -9,42 +9,94 @@ import (
 
 type (
 	UpdateTaskUseCase interface {
-		Execute(context.Context, UpdateTaskInput, domain.TaskID) error
+		Execute(context.Context, UpdateTaskInput, domain.TaskID) (UpdateTaskOutput, error)
 	}
 
 	UpdateTaskInput struct {
-		Title string `json:"title" validate:"required,gte=1,lte=15"`
+		Title      string      `json:"title" validate:"required,notblank,gte=1,lte=15"`
+		DueDate    *time.Time  `json:"due_date"`
+		Tags       []string    `json:"tags" validate:"lte=10,dive,slug,lte=30"`
+		Completed  bool        `json:"completed"`
+		Priority   int         `json:"priority" validate:"gte=0,lte=9"`
+		Recurrence string      `json:"recurrence" validate:"omitempty,rrule"`
+		Contexts   []string    `json:"contexts" validate:"lte=10,dive,slug,lte=30"`
+		Extensions []Extension `json:"extensions" validate:"lte=20,dive"`
+	}
+
+	UpdateTaskPresenter interface {
+		Output(domain.Task) UpdateTaskOutput
+	}
+
+	UpdateTaskOutput struct {
+		ID          domain.TaskID `json:"id"`
+		Title       string        `json:"title"`
+		DueDate     string        `json:"due_date,omitempty"`
+		Tags        []string      `json:"tags"`
+		Completed   bool          `json:"completed"`
+		Priority    int           `json:"priority,omitempty"`
+		Recurrence  string        `json:"recurrence,omitempty"`
+		Contexts    []string      `json:"contexts,omitempty"`
+		Extensions  []Extension   `json:"extensions,omitempty"`
+		CompletedAt string        `json:"completed_at,omitempty"`
+		CreatedAt   string        `json:"created_at"`
+		UpdatedAt   string        `json:"updated_at"`
 	}
 
 	UpdateTaskInteractor struct {
 		repo       domain.TaskRepository
+		outbox     domain.OutboxRepository
+		presenter  UpdateTaskPresenter
 		ctxTimeout time.Duration
 	}
 )
 
 func NewUpdateTaskInteractor(
 	taskRepo domain.TaskRepository,
+	outbox domain.OutboxRepository,
+	presenter UpdateTaskPresenter,
 	t time.Duration,
 ) UpdateTaskUseCase {
 	return UpdateTaskInteractor{
-		repo: taskRepo,
+		repo:       taskRepo,
+		outbox:     outbox,
+		presenter:  presenter,
 		ctxTimeout: t,
 	}
 }
 
-func (t UpdateTaskInteractor) Execute(ctx context.Context, input UpdateTaskInput, taskID domain.TaskID) error {
+func (t UpdateTaskInteractor) Execute(ctx context.Context, input UpdateTaskInput, taskID domain.TaskID) (UpdateTaskOutput, error) {
+	ctx, span := domain.StartSpan(ctx, "usecase.UpdateTask")
+	defer span.End()
+
 	ctx, cancel := context.WithTimeout(ctx, t.ctxTimeout)
 	defer cancel()
 
-	var task = domain.Task{
-		Title:     input.Title,
-		UpdatedAt: time.Now(),
-	}
+	var task domain.Task
+	err := t.repo.WithTransaction(ctx, func(ctxTx context.Context) error {
+		var err error
+		if task, err = t.repo.Update(ctxTx, input.toTask(), taskID); err != nil {
+			return err
+		}
 
-	err := t.repo.Update(ctx, task, taskID)
+		return t.outbox.Append(ctxTx, domain.NewTaskEvent(domain.EventTaskUpdated, task))
+	})
 	if err != nil {
-		return err
+		return t.presenter.Output(domain.Task{}), err
 	}
 
-	return nil
+	return t.presenter.Output(task), nil
+}
+
+// 入力値から更新するタスクを生成する
+func (i UpdateTaskInput) toTask() domain.Task {
+	return domain.Task{
+		Title:      i.Title,
+		DueDate:    i.DueDate,
+		Tags:       i.Tags,
+		Completed:  i.Completed,
+		Priority:   i.Priority,
+		Recurrence: i.Recurrence,
+		Contexts:   i.Contexts,
+		Extensions: toDomainExtensions(i.Extensions),
+	}
 }
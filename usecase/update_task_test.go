@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/doglapping707/todo-api-go/domain"
+)
+
+type mockTaskRepoUpdate struct {
+	domain.TaskRepository
+
+	result domain.Task
+	err    error
+}
+
+func (m mockTaskRepoUpdate) Update(_ context.Context, _ domain.Task, _ domain.TaskID) (domain.Task, error) {
+	return m.result, m.err
+}
+
+func (m mockTaskRepoUpdate) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+type mockUpdateTaskPresenter struct{}
+
+func (m mockUpdateTaskPresenter) Output(task domain.Task) UpdateTaskOutput {
+	return UpdateTaskOutput{
+		ID:    task.ID,
+		Title: task.Title,
+	}
+}
+
+func TestUpdateTaskInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		input         UpdateTaskInput
+		repository    domain.TaskRepository
+		expected      UpdateTaskOutput
+		expectedError interface{}
+	}{
+		{
+			name:  "Update task successful",
+			input: UpdateTaskInput{Title: "Task_2"},
+			repository: mockTaskRepoUpdate{
+				result: domain.Task{ID: 1, Title: "Task_2"},
+			},
+			expected: UpdateTaskOutput{ID: 1, Title: "Task_2"},
+		},
+		{
+			name:  "Update task not found",
+			input: UpdateTaskInput{Title: "Task_2"},
+			repository: mockTaskRepoUpdate{
+				err: domain.ErrTaskNotFound,
+			},
+			expected:      UpdateTaskOutput{},
+			expectedError: "task not found",
+		},
+		{
+			name:  "Update task generic error",
+			input: UpdateTaskInput{Title: "Task_2"},
+			repository: mockTaskRepoUpdate{
+				err: errors.New("error"),
+			},
+			expected:      UpdateTaskOutput{},
+			expectedError: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uc = NewUpdateTaskInteractor(tt.repository, mockOutbox{}, mockUpdateTaskPresenter{}, time.Second)
+
+			result, err := uc.Execute(context.TODO(), tt.input, 1)
+			if (err != nil) && (err.Error() != tt.expectedError) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expected)
+			}
+		})
+	}
+}